@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -15,7 +16,10 @@ import (
 	redisrepo "github.com/sabirkekw/ecommerce_go/cart-service/internal/repository/redis"
 	service "github.com/sabirkekw/ecommerce_go/cart-service/internal/service/cart"
 	"github.com/sabirkekw/ecommerce_go/cart-service/internal/service/messaging"
+	"github.com/sabirkekw/ecommerce_go/pkg/clientcreds"
+	"github.com/sabirkekw/ecommerce_go/pkg/jwks"
 	"github.com/sabirkekw/ecommerce_go/pkg/logger"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 )
 
 func main() {
@@ -30,16 +34,60 @@ func main() {
 	logger.Log.Infow("connected to Redis")
 
 	postgresRepo := postgresrepo.New(postgres_db, logger.Log)
-	redisRepo := redisrepo.New(redis_db, logger.Log)
+	redisRepo := redisrepo.New(redis_db, cfg.AbandonedCart.TTL, logger.Log)
 
-	productsClient := productsclient.New(logger.Log, 50052)
+	serviceCreds, err := clientcreds.New(logger.Log, cfg.SSO.Addr, cfg.ServiceAccount.ClientID, cfg.ServiceAccount.ClientSecret)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create service credentials", "error", err)
+	}
 
-	kafkaProducer := messaging.New(logger.Log, "checkout-topic")
+	productsClient := productsclient.New(logger.Log, 50052, serviceCreds)
+	ssoClient := productsclient.NewSSO(logger.Log, cfg.SSO.Addr, serviceCreds)
+
+	kafkaProducer := messaging.New(logger.Log, cfg.Kafka.Brokers)
 	defer kafkaProducer.Close()
 
-	service := service.New(postgresRepo, redisRepo, productsClient, kafkaProducer, logger.Log)
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	outboxRelay := messaging.NewOutboxRelay(logger.Log, postgresRepo, kafkaProducer, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize)
+	go outboxRelay.Run(relayCtx)
+
+	mergePolicy, err := service.ParseMergePolicy(cfg.GuestCart.MergePolicy)
+	if err != nil {
+		logger.Log.Fatalw("Invalid guest cart config", "error", err)
+	}
+	if cfg.AbandonedCart.RemindAfter >= cfg.AbandonedCart.TTL {
+		logger.Log.Fatalw("Invalid abandoned cart config, remind_after has to be shorter than ttl")
+	}
+	abandoned := service.AbandonedCarts{
+		RemindAfter: cfg.AbandonedCart.RemindAfter,
+		TTL:         cfg.AbandonedCart.TTL,
+		Topic:       cfg.Kafka.CartAbandonedTopic,
+		BatchSize:   cfg.AbandonedCart.BatchSize,
+	}
+	guestLimit := service.GuestCartLimit{
+		Limit:  cfg.GuestCart.CreateLimit,
+		Window: cfg.GuestCart.CreateWindow,
+	}
+	service := service.New(postgresRepo, redisRepo, productsClient, ssoClient, cfg.Kafka.CheckoutTopic, mergePolicy, guestLimit, abandoned, logger.Log)
+
+	sweepCtx, stopSweep := context.WithCancel(context.Background())
+	defer stopSweep()
+	go service.SweepCarts(sweepCtx, cfg.AbandonedCart.SweepInterval)
+
+	userEventsConsumer := messaging.NewUserEventsConsumer(logger.Log, cfg.Kafka.Brokers, cfg.Kafka.UserEventsGroupID, cfg.Kafka.UserEventsTopic, cfg.Kafka.RetryBackoff, cfg.Kafka.RetryMaxAttempts, service, kafkaProducer, cfg.Kafka.UserEventsDeadLetterTopic)
+	defer userEventsConsumer.Close()
+	go userEventsConsumer.Poll()
+
+	revocations, err := revocation.New(logger.Log, cfg.Revocation.SSOAddr, serviceCreds)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create revocation list", "error", err)
+	}
+	revocationCtx, stopRevocation := context.WithCancel(context.Background())
+	defer stopRevocation()
+	go revocations.Run(revocationCtx, cfg.Revocation.PollInterval)
 
-	application := app.New(logger.Log, cfg.GRPC.Port, cfg.HTTP.Port, service, cfg.JWTSecret, cfg.GRPC.Timeout)
+	application := app.New(logger.Log, cfg.GRPC.Port, cfg.HTTP.Port, service, jwks.NewCache(cfg.JWKS.URL, cfg.JWKS.CacheTTL).Keyfunc, revocations, cfg.GRPC.Timeout)
 	go application.GRPCApp.Run()
 	logger.Log.Infow("Starting gRPC server")
 	go application.HTTPApp.Run()
@@ -3,9 +3,11 @@ package app
 import (
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	grpcapp "github.com/sabirkekw/ecommerce_go/cart-service/internal/app/grpc"
 	httpapp "github.com/sabirkekw/ecommerce_go/cart-service/internal/app/http"
 	cartservice "github.com/sabirkekw/ecommerce_go/cart-service/internal/grpc/server"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"go.uber.org/zap"
 )
 
@@ -14,8 +16,8 @@ type App struct {
 	HTTPApp httpapp.HTTPApp
 }
 
-func New(logger *zap.SugaredLogger, grpcPort int, httpPort int, cartService cartservice.CartService, jwtSecret string, timeout time.Duration) *App {
-	grpcApp := grpcapp.New(logger, grpcPort, cartService, jwtSecret, timeout)
+func New(logger *zap.SugaredLogger, grpcPort int, httpPort int, cartService cartservice.CartService, keyfunc jwt.Keyfunc, revocations *revocation.List, timeout time.Duration) *App {
+	grpcApp := grpcapp.New(logger, grpcPort, cartService, keyfunc, revocations, timeout)
 	httpApp := httpapp.New(logger, httpPort, grpcPort)
 
 	return &App{
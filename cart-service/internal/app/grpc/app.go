@@ -6,8 +6,10 @@ import (
 	"net"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sabirkekw/ecommerce_go/cart-service/internal/grpc/interceptor"
 	cartgrpc "github.com/sabirkekw/ecommerce_go/cart-service/internal/grpc/server"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
@@ -18,10 +20,11 @@ type GRPCApp struct {
 	Port   int
 }
 
-func New(logger *zap.SugaredLogger, port int, service cartgrpc.CartService, jwtSecret string, timeout time.Duration) *GRPCApp {
+func New(logger *zap.SugaredLogger, port int, service cartgrpc.CartService, keyfunc jwt.Keyfunc, revocations *revocation.List, timeout time.Duration) *GRPCApp {
 	logInterceptor := func(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		ctx = context.WithValue(ctx, "logger", logger)
-		ctx = context.WithValue(ctx, "jwtSecret", jwtSecret)
+		ctx = context.WithValue(ctx, "keyfunc", keyfunc)
+		ctx = context.WithValue(ctx, "revocations", revocations)
 		ctx = context.WithValue(ctx, "timeout", timeout)
 		return interceptor.LogInterceptor(ctx, req, serverInfo, handler)
 	}
@@ -31,6 +34,7 @@ func New(logger *zap.SugaredLogger, port int, service cartgrpc.CartService, jwtS
 		interceptor.TimeoutInterceptor,
 		interceptor.AuthInterceptor,
 		interceptor.UserIDExtractorInterceptor,
+		interceptor.AuthorizationInterceptor,
 	),
 	)
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -22,7 +23,7 @@ type HTTPApp struct {
 }
 
 func New(logger *zap.SugaredLogger, httpport int, grpcport int) *HTTPApp {
-	router := runtime.NewServeMux()
+	router := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher))
 	return &HTTPApp{
 		Logger: logger,
 		HTTPPort: httpport,
@@ -48,3 +49,11 @@ func (s *HTTPApp) Run() {
 		panic(err)
 	}
 }
+
+// headerMatcher passes the guest cart token on to the gRPC server next to the default headers.
+func headerMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "X-Cart-Token") {
+		return "x-cart-token", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
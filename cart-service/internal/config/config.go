@@ -27,7 +27,56 @@ type Config struct {
 		Port    int           `yaml:"port"`
 		Timeout time.Duration `yaml:"timeout"`
 	} `yaml:"http"`
-	JWTSecret string `yaml:"jwt_secret"`
+	Kafka struct {
+		Brokers       string `yaml:"brokers" env:"KAFKA_BROKERS" env-default:"localhost:9092"`
+		CheckoutTopic string `yaml:"checkout_topic" env:"KAFKA_CHECKOUT_TOPIC" env-default:"checkout-topic"`
+		// account deletions from sso-service, the cart of a deleted user is dropped
+		UserEventsTopic   string        `yaml:"user_events_topic" env:"KAFKA_USER_EVENTS_TOPIC" env-default:"user-events"`
+		UserEventsGroupID string        `yaml:"user_events_group_id" env-default:"cart-service-user-events"`
+		RetryBackoff      time.Duration `yaml:"retry_backoff" env-default:"5s"`
+		RetryMaxAttempts  int           `yaml:"retry_max_attempts" env-default:"5"`
+		// user events that still fail after all retries end up here
+		UserEventsDeadLetterTopic string `yaml:"user_events_dead_letter_topic" env-default:"cart-service-user-events-dead-letter"`
+		// reminders about idle carts, for a notification consumer
+		CartAbandonedTopic string `yaml:"cart_abandoned_topic" env:"KAFKA_CART_ABANDONED_TOPIC" env-default:"cart-abandoned"`
+	} `yaml:"kafka"`
+	Outbox struct {
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+		BatchSize    uint64        `yaml:"batch_size" env-default:"100"`
+	} `yaml:"outbox"`
+	SSO struct {
+		// sso-service resolves the addresses of an order at checkout
+		Addr string `yaml:"addr" env:"SSO_ADDR" env-default:"sso-service:50051"`
+	} `yaml:"sso"`
+	JWKS struct {
+		URL      string        `yaml:"url" env:"JWKS_URL" env-default:"http://sso-service:8081/.well-known/jwks.json"`
+		CacheTTL time.Duration `yaml:"cache_ttl" env-default:"10m"`
+	} `yaml:"jwks"`
+	Revocation struct {
+		SSOAddr      string        `yaml:"sso_addr" env:"REVOCATION_SSO_ADDR" env-default:"sso-service:50051"`
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"5s"`
+	} `yaml:"revocation"`
+	GuestCart struct {
+		// what a product in both carts ends up as when a guest cart is merged on login:
+		// sum, max, keep_user or keep_guest
+		MergePolicy string `yaml:"merge_policy" env:"GUEST_CART_MERGE_POLICY" env-default:"sum"`
+		// guest carts one address may start per create_window, 0 turns the limit off
+		CreateLimit  int64         `yaml:"create_limit" env:"GUEST_CART_CREATE_LIMIT" env-default:"20"`
+		CreateWindow time.Duration `yaml:"create_window" env-default:"1h"`
+	} `yaml:"guest_cart"`
+	AbandonedCart struct {
+		// a user cart untouched for that long gets one cart-abandoned event
+		RemindAfter time.Duration `yaml:"remind_after" env:"CART_REMIND_AFTER" env-default:"24h"`
+		// a cart untouched for that long is deleted, has to be longer than remind_after
+		TTL           time.Duration `yaml:"ttl" env:"CART_TTL" env-default:"720h"`
+		SweepInterval time.Duration `yaml:"sweep_interval" env-default:"10m"`
+		BatchSize     uint64        `yaml:"batch_size" env-default:"100"`
+	} `yaml:"abandoned_cart"`
+	ServiceAccount struct {
+		// identifies this service to the others, registered in sso-service
+		ClientID     string `yaml:"client_id" env:"SERVICE_CLIENT_ID" env-default:"cart-service"`
+		ClientSecret string `yaml:"client_secret" env:"SERVICE_CLIENT_SECRET"`
+	} `yaml:"service_account"`
 }
 
 func MustLoad() *Config {
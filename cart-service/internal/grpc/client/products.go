@@ -3,10 +3,13 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	productsProto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -15,8 +18,9 @@ type ProductsClient struct {
 	Client productsProto.ProductsServiceClient
 }
 
-func New(logger *zap.SugaredLogger, port int) *ProductsClient {
-	conn, err := grpc.NewClient(fmt.Sprintf("products-service:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+// New dials products-service. creds identify this service, stock reservations are refused without them.
+func New(logger *zap.SugaredLogger, port int, creds credentials.PerRPCCredentials) *ProductsClient {
+	conn, err := grpc.NewClient(fmt.Sprintf("products-service:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithPerRPCCredentials(creds))
 	if err != nil {
 		logger.Errorw("failed to start gRPC products client", "error", err)
 		return nil
@@ -44,3 +48,87 @@ func (c *ProductsClient) GetProductByID(ctx context.Context, id int32) (*product
 
 	return resp.Product, nil
 }
+
+func (c *ProductsClient) GetProductsByIDs(ctx context.Context, ids []int32) ([]*productsProto.Product, error) {
+	const op = "Cart.ProductsClient.GetProductsByIDs"
+	c.Logger.Debugw("requesting products data from Products-service", "ids", len(ids), "op", op)
+
+	resp, err := c.Client.GetProductsByIDs(ctx, &productsProto.GetProductsByIDsRequest{
+		Ids: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Products, nil
+}
+
+func (c *ProductsClient) ReserveStock(ctx context.Context, items []*productsProto.StockItem) (int32, error) {
+	const op = "Cart.ProductsClient.ReserveStock"
+	c.Logger.Debugw("reserving stock in Products-service", "items", len(items), "op", op)
+
+	resp, err := c.Client.ReserveStock(ctx, &productsProto.ReserveStockRequest{
+		Items: items,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.ReservationId, nil
+}
+
+func (c *ProductsClient) ReleaseReservation(ctx context.Context, reservationID int32) error {
+	const op = "Cart.ProductsClient.ReleaseReservation"
+	c.Logger.Debugw("releasing reservation in Products-service", "reservation_id", reservationID, "op", op)
+
+	_, err := c.Client.ReleaseReservation(ctx, &productsProto.ReleaseReservationRequest{
+		ReservationId: reservationID,
+	})
+	return err
+}
+
+var promotionKinds = map[productsProto.PromotionKind]promotions.Kind{
+	productsProto.PromotionKind_PROMOTION_KIND_PERCENTAGE:   promotions.Percentage,
+	productsProto.PromotionKind_PROMOTION_KIND_FIXED_AMOUNT: promotions.FixedAmount,
+	productsProto.PromotionKind_PROMOTION_KIND_BUY_X_GET_Y:  promotions.BuyXGetY,
+}
+
+// GetPromotion returns the promotion of the coupon code and how many times the user redeemed it.
+func (c *ProductsClient) GetPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, int32, error) {
+	const op = "Cart.ProductsClient.GetPromotion"
+	c.Logger.Debugw("requesting promotion from Products-service", "user_id", userID, "op", op)
+
+	resp, err := c.Client.GetPromotion(ctx, &productsProto.GetPromotionRequest{
+		Code:   code,
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	p := resp.Promotion
+	return &promotions.Promotion{
+		ID:                    p.Id,
+		Code:                  p.Code,
+		Kind:                  promotionKinds[p.Kind],
+		PercentOff:            p.PercentOff,
+		AmountOff:             p.AmountOff,
+		Currency:              p.Currency,
+		ProductID:             p.ProductId,
+		BuyQuantity:           p.BuyQuantity,
+		GetQuantity:           p.GetQuantity,
+		MinSubtotal:           p.MinSubtotal,
+		MaxRedemptionsPerUser: p.MaxRedemptionsPerUser,
+		StartsAt:              unixTime(p.StartsAt),
+		EndsAt:                unixTime(p.EndsAt),
+		DisabledAt:            unixTime(p.DisabledAt),
+	}, resp.UserRedemptions, nil
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0)
+	return &t
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/address"
+	ssoProto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type SSOClient struct {
+	Logger *zap.SugaredLogger
+	Client ssoProto.AuthClient
+}
+
+// NewSSO dials sso-service. creds identify this service, checkout addresses are refused without them.
+func NewSSO(logger *zap.SugaredLogger, addr string, creds credentials.PerRPCCredentials) *SSOClient {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithPerRPCCredentials(creds))
+	if err != nil {
+		logger.Errorw("failed to start gRPC sso client", "error", err)
+		return nil
+	}
+	return &SSOClient{
+		Logger: logger,
+		Client: ssoProto.NewAuthClient(conn),
+	}
+}
+
+// GetCheckoutAddresses resolves the shipping and billing address of an order, 0 picks the default.
+func (c *SSOClient) GetCheckoutAddresses(ctx context.Context, userID int32, shippingID int64, billingID int64) (*address.Address, *address.Address, error) {
+	const op = "Cart.SSOClient.GetCheckoutAddresses"
+	c.Logger.Debugw("requesting checkout addresses from SSO-service", "user_id", userID, "op", op)
+
+	resp, err := c.Client.GetCheckoutAddresses(ctx, &ssoProto.GetCheckoutAddressesRequest{
+		UserId:            int64(userID),
+		ShippingAddressId: shippingID,
+		BillingAddressId:  billingID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return addressFromProto(resp.Shipping), addressFromProto(resp.Billing), nil
+}
+
+func addressFromProto(a *ssoProto.Address) *address.Address {
+	if a == nil {
+		return nil
+	}
+	return &address.Address{
+		RecipientName: a.RecipientName,
+		Line1:         a.Line1,
+		Line2:         a.Line2,
+		City:          a.City,
+		Region:        a.Region,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+		Phone:         a.Phone,
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/cart"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ApplyCoupon(ctx context.Context, req *proto.ApplyCouponRequest) (*proto.ApplyCouponResponse, error) {
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	}
+
+	if req.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "coupon code is required")
+	}
+
+	priced, err := s.Service.ApplyCoupon(ctx, userID, req.Code)
+	if err != nil {
+		return nil, cartError(err)
+	}
+	return &proto.ApplyCouponResponse{
+		Cart: cartResponse(priced),
+	}, nil
+}
+
+func (s *Server) RemoveCoupon(ctx context.Context, req *proto.RemoveCouponRequest) (*proto.RemoveCouponResponse, error) {
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	}
+
+	priced, err := s.Service.RemoveCoupon(ctx, userID)
+	if err != nil {
+		return nil, cartError(err)
+	}
+	return &proto.RemoveCouponResponse{
+		Cart: cartResponse(priced),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	service "github.com/sabirkekw/ecommerce_go/cart-service/internal/service/cart"
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/cart"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) SetQuantity(ctx context.Context, req *proto.SetQuantityRequest) (*proto.SetQuantityResponse, error) {
+	if req.ProductId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID")
+	} else if req.Quantity < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid quantity")
+	}
+
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Quantity == 0 {
+		err = s.Service.RemoveFromCart(ctx, owner, req.ProductId)
+	} else {
+		err = s.Service.SetQuantity(ctx, owner, req.ProductId, req.Quantity)
+	}
+	if err != nil {
+		return nil, cartError(err)
+	}
+	return &proto.SetQuantityResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *Server) IncrementQuantity(ctx context.Context, req *proto.IncrementQuantityRequest) (*proto.IncrementQuantityResponse, error) {
+	if req.ProductId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID")
+	} else if req.Delta == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid delta")
+	}
+
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Service.IncrementQuantity(ctx, owner, req.ProductId, req.Delta); err != nil {
+		return nil, cartError(err)
+	}
+	return &proto.IncrementQuantityResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *Server) AddItems(ctx context.Context, req *proto.AddItemsRequest) (*proto.AddItemsResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no items")
+	} else if len(req.Items) > service.MaxItemsPerRequest {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d items per request", service.MaxItemsPerRequest)
+	}
+	items := make([]cart.Item, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductId <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID")
+		} else if item.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid quantity")
+		}
+		items = append(items, cart.Item{ProductID: item.ProductId, Quantity: item.Quantity})
+	}
+
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.Service.AddItems(ctx, owner, items)
+	if err != nil {
+		return nil, cartError(err)
+	}
+	return &proto.AddItemsResponse{
+		Cart: cartResponse(products),
+	}, nil
+}
+
+func (s *Server) ClearCart(ctx context.Context, req *proto.ClearCartRequest) (*proto.ClearCartResponse, error) {
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Service.ClearCart(ctx, owner); err != nil {
+		return nil, cartError(err)
+	}
+	return &proto.ClearCartResponse{
+		Success: true,
+	}, nil
+}
+
+// cartError maps the errors of changing a cart to status errors.
+func cartError(err error) error {
+	switch {
+	case errors.Is(err, apierrors.ErrProductNotFound):
+		return status.Errorf(codes.NotFound, "product not found")
+	case errors.Is(err, apierrors.ErrProductNotInCart):
+		return status.Errorf(codes.NotFound, "product is not in the cart")
+	case errors.Is(err, apierrors.ErrNotEnoughProduct):
+		return status.Errorf(codes.FailedPrecondition, "not enough product")
+	case errors.Is(err, apierrors.ErrCurrencyMismatch):
+		return status.Errorf(codes.FailedPrecondition, "product currency differs from cart currency")
+	case errors.Is(err, apierrors.ErrFailedToReadProduct):
+		return status.Errorf(codes.Internal, "failed to read product")
+	case errors.Is(err, apierrors.ErrFailedToGetCart):
+		return status.Errorf(codes.Internal, "failed to get cart")
+	case errors.Is(err, apierrors.ErrPromotionNotFound):
+		return status.Errorf(codes.NotFound, "coupon not found")
+	case errors.Is(err, apierrors.ErrPromotionLimitReached):
+		return status.Errorf(codes.FailedPrecondition, "coupon usage limit reached")
+	case errors.Is(err, apierrors.ErrPromotionNotApplicable):
+		return status.Errorf(codes.FailedPrecondition, "cart doesn't qualify for the coupon")
+	case errors.Is(err, apierrors.ErrFailedToReadPromotion):
+		return status.Errorf(codes.Internal, "failed to read coupon")
+	default:
+		return status.Errorf(codes.Internal, "internal server error")
+	}
+}
@@ -3,21 +3,34 @@ package server
 import (
 	"context"
 	"errors"
+	"net"
+	"strings"
 
-	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
 	proto "github.com/sabirkekw/ecommerce_go/pkg/api/cart"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type CartService interface {
-	AddToCart(ctx context.Context, userID int32, productID int32, quantity int32) error
-	RemoveFromCart(ctx context.Context, userID int32, productID int32) error
-	GetCart(ctx context.Context, userID int32) ([]*models.ProductData, error)
-	Checkout(ctx context.Context, userID int32) error
+	AddToCart(ctx context.Context, owner cart.Owner, productID int32, quantity int32) error
+	RemoveFromCart(ctx context.Context, owner cart.Owner, productID int32) error
+	SetQuantity(ctx context.Context, owner cart.Owner, productID int32, quantity int32) error
+	IncrementQuantity(ctx context.Context, owner cart.Owner, productID int32, delta int32) error
+	AddItems(ctx context.Context, owner cart.Owner, items []cart.Item) (*cart.PricedCart, error)
+	ClearCart(ctx context.Context, owner cart.Owner) error
+	Checkout(ctx context.Context, userID int32, shippingAddressID int64, billingAddressID int64) error
+	CreateGuestCart(ctx context.Context, clientIP string) (string, error)
+	OpenGuestCart(ctx context.Context, token string) (cart.Owner, error)
+	MergeCart(ctx context.Context, userID int32, token string) (*cart.PricedCart, error)
+	PriceCart(ctx context.Context, owner cart.Owner) (*cart.PricedCart, error)
+	ApplyCoupon(ctx context.Context, userID int32, code string) (*cart.PricedCart, error)
+	RemoveCoupon(ctx context.Context, userID int32) (*cart.PricedCart, error)
 }
 
 type Server struct {
@@ -38,9 +51,9 @@ func Register(grpc *grpc.Server, server *Server) {
 }
 
 func (s *Server) AddToCart(ctx context.Context, req *proto.AddToCartRequest) (*proto.AddToCartResponse, error) {
-	userID, ok := ctx.Value("user_id").(int32)
-	if !ok {
-		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	productID := req.ProductId
@@ -51,15 +64,8 @@ func (s *Server) AddToCart(ctx context.Context, req *proto.AddToCartRequest) (*p
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid quantity")
 	}
 
-	err := s.Service.AddToCart(ctx, userID, productID, quantity)
-	if errors.Is(err, apierrors.ErrProductNotFound) {
-		return nil, status.Errorf(codes.NotFound, "product not found")
-	} else if errors.Is(err, apierrors.ErrNotEnoughProduct) {
-		return nil, status.Errorf(codes.FailedPrecondition, "not enough product")
-	} else if errors.Is(err, apierrors.ErrFailedToReadProduct) {
-		return nil, status.Errorf(codes.Internal, "failed to read product")
-	} else if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal server error")
+	if err := s.Service.AddToCart(ctx, owner, productID, quantity); err != nil {
+		return nil, cartError(err)
 	}
 	return &proto.AddToCartResponse{
 		Success: true,
@@ -67,16 +73,16 @@ func (s *Server) AddToCart(ctx context.Context, req *proto.AddToCartRequest) (*p
 }
 
 func (s *Server) RemoveFromCart(ctx context.Context, req *proto.RemoveFromCartRequest) (*proto.RemoveFromCartResponse, error) {
-	userID, ok := ctx.Value("user_id").(int32)
-	if !ok {
-		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	productID := req.ProductId
 	if productID <= 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID")
 	}
-	err := s.Service.RemoveFromCart(ctx, userID, productID)
+	err = s.Service.RemoveFromCart(ctx, owner, productID)
 	if errors.Is(err, apierrors.ErrIncorrectID) {
 		return nil, status.Errorf(codes.NotFound, "No product with id %v in cart", productID)
 	} else if err != nil {
@@ -88,48 +94,98 @@ func (s *Server) RemoveFromCart(ctx context.Context, req *proto.RemoveFromCartRe
 }
 
 func (s *Server) GetCart(ctx context.Context, req *proto.GetCartRequest) (*proto.GetCartResponse, error) {
-	userID, ok := ctx.Value("user_id").(int32)
-	if !ok {
-		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	owner, err := s.cartOwner(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	products, err := s.Service.GetCart(ctx, userID)
+	priced, err := s.Service.PriceCart(ctx, owner)
 	if errors.Is(err, apierrors.ErrFailedToGetCart) {
 		return nil, status.Errorf(codes.Internal, "failed to get cart")
+	} else if errors.Is(err, apierrors.ErrFailedToReadProduct) {
+		return nil, status.Errorf(codes.Internal, "failed to read product")
+	} else if errors.Is(err, apierrors.ErrFailedToReadPromotion) {
+		return nil, status.Errorf(codes.Internal, "failed to read coupon")
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
 
-	var response []*proto.CartProduct
-	for _, product := range products {
-		responseProduct := &proto.CartProduct{
-			Id:          product.ID,
-			ProductName: product.ProductName,
-			Quantity:    product.Quantity,
-			Description: product.Description,
-		}
-		response = append(response, responseProduct)
-	}
 	return &proto.GetCartResponse{
-		Cart: &proto.Cart{
-			Products: response,
-		},
+		Cart: cartResponse(priced),
 	}, nil
 }
 
+func cartResponse(priced *cart.PricedCart) *proto.Cart {
+	products := make([]*proto.CartProduct, 0, len(priced.Lines))
+	for _, line := range priced.Lines {
+		products = append(products, &proto.CartProduct{
+			Id:                line.Product.ID,
+			ProductName:       line.Product.ProductName,
+			Quantity:          line.Product.Quantity,
+			Description:       line.Product.Description,
+			UnitPrice:         line.Product.UnitPrice,
+			Currency:          line.Product.Currency,
+			LineTotal:         line.LineTotal(),
+			CurrentUnitPrice:  line.CurrentUnitPrice,
+			AvailableQuantity: line.AvailableQuantity,
+			ProductDeleted:    line.ProductDeleted,
+			InsufficientStock: line.InsufficientStock,
+			PriceChanged:      line.PriceChanged,
+		})
+	}
+	return &proto.Cart{
+		Products:      products,
+		TotalPrice:    priced.GrandTotal,
+		Currency:      priced.Currency,
+		Subtotal:      priced.Subtotal,
+		Discount:      priced.Discount,
+		Tax:           priced.Tax,
+		GrandTotal:    priced.GrandTotal,
+		HasStaleItems: priced.HasStaleLines(),
+		CouponCode:    priced.Coupon,
+		CouponApplied: priced.CouponApplied,
+	}
+}
+
 func (s *Server) Checkout(ctx context.Context, req *proto.CheckoutRequest) (*proto.CheckoutResponse, error) {
 	userID, ok := ctx.Value("user_id").(int32)
 	if !ok {
 		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
 	}
 
-	err := s.Service.Checkout(ctx, userID)
+	if req.ShippingAddressId < 0 || req.BillingAddressId < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid address ID")
+	}
+
+	err := s.Service.Checkout(ctx, userID, req.ShippingAddressId, req.BillingAddressId)
 	if errors.Is(err, apierrors.ErrFailedToCheckout) {
 		return nil, status.Errorf(codes.Internal, "failed to checkout cart")
 	} else if errors.Is(err, apierrors.ErrFailedToGetCart) {
 		return nil, status.Errorf(codes.Internal, "failed to get cart")
 	} else if errors.Is(err, apierrors.ErrEmptyCart) {
 		return nil, status.Errorf(codes.FailedPrecondition, "your cart is empty!")
+	} else if errors.Is(err, apierrors.ErrAddressNotFound) {
+		return nil, status.Errorf(codes.NotFound, "address not found")
+	} else if errors.Is(err, apierrors.ErrNoShippingAddress) {
+		return nil, status.Errorf(codes.FailedPrecondition, "add a shipping address before checking out")
+	} else if errors.Is(err, apierrors.ErrCartChanged) {
+		return nil, status.Errorf(codes.Aborted, "cart changed during checkout, try again")
+	} else if errors.Is(err, apierrors.ErrNotEnoughProduct) {
+		return nil, status.Errorf(codes.FailedPrecondition, "not enough product")
+	} else if errors.Is(err, apierrors.ErrProductNotFound) {
+		return nil, status.Errorf(codes.NotFound, "product not found")
+	} else if errors.Is(err, apierrors.ErrCurrencyMismatch) {
+		return nil, status.Errorf(codes.FailedPrecondition, "products in cart are priced in different currencies")
+	} else if errors.Is(err, apierrors.ErrFailedToReadProduct) {
+		return nil, status.Errorf(codes.Internal, "failed to read product")
+	} else if errors.Is(err, apierrors.ErrPromotionNotFound) {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon no longer exists, remove it from the cart")
+	} else if errors.Is(err, apierrors.ErrPromotionLimitReached) {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon usage limit reached, remove it from the cart")
+	} else if errors.Is(err, apierrors.ErrPromotionNotApplicable) {
+		return nil, status.Errorf(codes.FailedPrecondition, "cart doesn't qualify for the coupon, remove it from the cart")
+	} else if errors.Is(err, apierrors.ErrFailedToReadPromotion) {
+		return nil, status.Errorf(codes.Internal, "failed to read coupon")
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
@@ -137,3 +193,84 @@ func (s *Server) Checkout(ctx context.Context, req *proto.CheckoutRequest) (*pro
 		Success: true,
 	}, nil
 }
+
+func (s *Server) CreateGuestCart(ctx context.Context, req *proto.CreateGuestCartRequest) (*proto.CreateGuestCartResponse, error) {
+	token, err := s.Service.CreateGuestCart(ctx, clientIP(ctx))
+	if errors.Is(err, apierrors.ErrTooManyGuestCarts) {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many guest carts, try again later")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+	return &proto.CreateGuestCartResponse{
+		CartToken: token,
+	}, nil
+}
+
+func (s *Server) MergeCart(ctx context.Context, req *proto.MergeCartRequest) (*proto.MergeCartResponse, error) {
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	}
+
+	if req.CartToken == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cart token is required")
+	}
+
+	products, err := s.Service.MergeCart(ctx, userID, req.CartToken)
+	if errors.Is(err, apierrors.ErrGuestCartNotFound) {
+		return nil, status.Errorf(codes.NotFound, "guest cart not found")
+	} else if errors.Is(err, apierrors.ErrCurrencyMismatch) {
+		return nil, status.Errorf(codes.FailedPrecondition, "guest cart currency differs from cart currency")
+	} else if errors.Is(err, apierrors.ErrFailedToGetCart) {
+		return nil, status.Errorf(codes.Internal, "failed to get cart")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+	return &proto.MergeCartResponse{
+		Cart: cartResponse(products),
+	}, nil
+}
+
+// cartOwner returns the cart the call is about: the caller's own one, or for guests the cart of
+// their cart token.
+func (s *Server) cartOwner(ctx context.Context) (cart.Owner, error) {
+	if userID, ok := ctx.Value("user_id").(int32); ok {
+		return cart.User(userID), nil
+	}
+
+	token, ok := ctx.Value("cart_token").(string)
+	if !ok {
+		return cart.Owner{}, status.Errorf(codes.Internal, "Failed to fetch user ID")
+	}
+	owner, err := s.Service.OpenGuestCart(ctx, token)
+	if errors.Is(err, apierrors.ErrGuestCartNotFound) {
+		return cart.Owner{}, status.Errorf(codes.NotFound, "guest cart not found")
+	} else if err != nil {
+		return cart.Owner{}, status.Errorf(codes.Internal, "internal server error")
+	}
+	return owner, nil
+}
+
+// clientIP returns the address the request came from. The HTTP gateway runs in the same process and
+// dials over loopback, so x-forwarded-for is only trusted from there; direct clients can't spoof it.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+				// the gateway appends the address it saw last
+				hops := strings.Split(forwarded[len(forwarded)-1], ",")
+				return strings.TrimSpace(hops[len(hops)-1])
+			}
+		}
+	}
+	return host
+}
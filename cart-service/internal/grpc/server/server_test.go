@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/cart"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkoutService fails Checkout with err, the other methods panic through the nil CartService.
+type checkoutService struct {
+	CartService
+	err error
+}
+
+func (s *checkoutService) Checkout(ctx context.Context, userID int32, shippingAddressID int64, billingAddressID int64) error {
+	return s.err
+}
+
+func TestCheckoutErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{nil, codes.OK},
+		{apierrors.ErrAddressNotFound, codes.NotFound},
+		{apierrors.ErrNoShippingAddress, codes.FailedPrecondition},
+		{apierrors.ErrEmptyCart, codes.FailedPrecondition},
+		{apierrors.ErrCartChanged, codes.Aborted},
+		{apierrors.ErrNotEnoughProduct, codes.FailedPrecondition},
+		{apierrors.ErrProductNotFound, codes.NotFound},
+		{apierrors.ErrPromotionLimitReached, codes.FailedPrecondition},
+		{fmt.Errorf("reserve stock: %w", apierrors.ErrNotEnoughProduct), codes.FailedPrecondition},
+		{apierrors.ErrFailedToCheckout, codes.Internal},
+		{errors.New("unexpected"), codes.Internal},
+	}
+
+	ctx := context.WithValue(context.Background(), "user_id", int32(1))
+	for _, tt := range tests {
+		s := New(&checkoutService{err: tt.err}, zap.NewNop().Sugar())
+		_, err := s.Checkout(ctx, &proto.CheckoutRequest{})
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("Checkout() with %v: code = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCheckoutRejectsNegativeAddressIDs(t *testing.T) {
+	s := New(&checkoutService{}, zap.NewNop().Sugar())
+	ctx := context.WithValue(context.Background(), "user_id", int32(1))
+
+	_, err := s.Checkout(ctx, &proto.CheckoutRequest{ShippingAddressId: -1})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("code = %s, want %s", got, codes.InvalidArgument)
+	}
+}
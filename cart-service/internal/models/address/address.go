@@ -0,0 +1,14 @@
+package address
+
+// Address is a copy of an address book entry taken at checkout, so later edits in sso-service
+// don't move orders that were already placed.
+type Address struct {
+	RecipientName string
+	Line1         string
+	Line2         string
+	City          string
+	Region        string
+	PostalCode    string
+	Country       string
+	Phone         string
+}
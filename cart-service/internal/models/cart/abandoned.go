@@ -0,0 +1,14 @@
+package cart
+
+import (
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+)
+
+// Abandoned is a user cart nobody touched for a while.
+type Abandoned struct {
+	UserID    int32
+	UpdatedAt time.Time
+	Products  []*product.ProductData
+}
@@ -0,0 +1,7 @@
+package cart
+
+// Item is a product and how many of it to put into a cart.
+type Item struct {
+	ProductID int32
+	Quantity  int32
+}
@@ -0,0 +1,30 @@
+package cart
+
+import "fmt"
+
+// Owner is whose cart it is: a signed in user, or a guest holding a cart token. Exactly one of
+// the fields is set.
+type Owner struct {
+	UserID int32
+	// GuestID is the hash of the guest's cart token, the token itself is never stored
+	GuestID string
+}
+
+func User(userID int32) Owner {
+	return Owner{UserID: userID}
+}
+
+func Guest(guestID string) Owner {
+	return Owner{GuestID: guestID}
+}
+
+func (o Owner) IsGuest() bool {
+	return o.GuestID != ""
+}
+
+func (o Owner) String() string {
+	if o.IsGuest() {
+		return "guest:" + o.GuestID
+	}
+	return fmt.Sprintf("user:%d", o.UserID)
+}
@@ -0,0 +1,52 @@
+package cart
+
+import "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+
+// Line is a cart line checked against the catalog. Product is the line as stored, its UnitPrice
+// is the price at the moment the product was added.
+type Line struct {
+	Product           *product.ProductData
+	CurrentUnitPrice  int64
+	CurrentCurrency   string
+	AvailableQuantity int32
+	// ProductDeleted lines are gone from the catalog and don't count towards the totals.
+	ProductDeleted    bool
+	InsufficientStock bool
+	PriceChanged      bool
+}
+
+// LineTotal is the line at the current price.
+func (l *Line) LineTotal() int64 {
+	if l.ProductDeleted {
+		return 0
+	}
+	return l.CurrentUnitPrice * int64(l.Product.Quantity)
+}
+
+// Stale lines need the user's attention before checkout.
+func (l *Line) Stale() bool {
+	return l.ProductDeleted || l.InsufficientStock || l.PriceChanged
+}
+
+// PricedCart is a cart re-priced against the catalog. All amounts are in minor units of Currency.
+type PricedCart struct {
+	Lines    []*Line
+	Currency string
+	Subtotal int64
+	Discount int64
+	// Tax is a placeholder, nothing computes it yet
+	Tax        int64
+	GrandTotal int64
+	// Coupon is on the cart, CouponApplied tells whether the cart qualifies for it right now
+	Coupon        string
+	CouponApplied bool
+}
+
+func (c *PricedCart) HasStaleLines() bool {
+	for _, line := range c.Lines {
+		if line.Stale() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package outbox
+
+import "time"
+
+// Message is an event waiting in the outbox table to be published to Kafka.
+type Message struct {
+	ID        int64
+	Topic     string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+}
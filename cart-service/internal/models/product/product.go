@@ -5,4 +5,10 @@ type ProductData struct {
 	ProductName string
 	Quantity    int32
 	Description string
+	UnitPrice   int64 // minor units of Currency, snapshotted on AddToCart
+	Currency    string
+}
+
+func (p *ProductData) LineTotal() int64 {
+	return p.UnitPrice * int64(p.Quantity)
 }
@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/outbox"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// touchCart records that the owner's cart changed just now. A user cart that changes starts a new
+// idle period, so it can be reminded about again.
+func (r *Repository) touchCart(ctx context.Context, db execer, owner cart.Owner) error {
+	const op = "Cart.Repository.Postgres.touchCart"
+
+	var query sq.Sqlizer
+	if owner.IsGuest() {
+		query = r.builder.Update("guest_carts").
+			Set("updated_at", sq.Expr("NOW()")).
+			Where(sq.Eq{"id": owner.GuestID})
+	} else {
+		query = r.builder.Insert("carts").
+			Columns("user_id").
+			Values(owner.UserID).
+			Suffix("ON CONFLICT (user_id) DO UPDATE SET updated_at = NOW(), reminded_at = NULL")
+	}
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := db.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// forgetCart drops the last-modified time of a user cart that was emptied.
+func (r *Repository) forgetCart(ctx context.Context, db execer, userID int32) error {
+	const op = "Cart.Repository.Postgres.forgetCart"
+
+	strSql, args, err := r.builder.Delete("carts").
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := db.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// FindAbandonedCarts returns up to limit user carts untouched since idleSince that haven't been
+// reminded about in their current idle period, oldest first.
+func (r *Repository) FindAbandonedCarts(ctx context.Context, idleSince time.Time, limit uint64) ([]*cart.Abandoned, error) {
+	const op = "Cart.Repository.Postgres.FindAbandonedCarts"
+
+	query := r.builder.Select("user_id", "updated_at").
+		From("carts").
+		Where(sq.Lt{"updated_at": idleSince}).
+		Where(sq.Eq{"reminded_at": nil}).
+		Where(sq.Expr("EXISTS (SELECT 1 FROM cart WHERE cart.user_id = carts.user_id)")).
+		OrderBy("updated_at").
+		Limit(limit)
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := r.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var abandoned []*cart.Abandoned
+	for rows.Next() {
+		var c cart.Abandoned
+		if err := rows.Scan(&c.UserID, &c.UpdatedAt); err != nil {
+			r.logger.Errorw("Failed to scan row", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		abandoned = append(abandoned, &c)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("Row iteration error", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	for _, c := range abandoned {
+		products, err := r.GetCart(ctx, cart.User(c.UserID))
+		if err != nil {
+			return nil, err
+		}
+		c.Products = products
+	}
+	return abandoned, nil
+}
+
+// MarkCartAbandoned queues the cart-abandoned event of the cart and marks the cart as reminded in one
+// transaction. It returns false without queueing anything if the cart changed since updatedAt or
+// was reminded about already.
+func (r *Repository) MarkCartAbandoned(ctx context.Context, userID int32, updatedAt time.Time, message *outbox.Message) (bool, error) {
+	const op = "Cart.Repository.Postgres.MarkCartAbandoned"
+	r.logger.Debugw("Marking cart abandoned", "user_id", userID, "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	updateQuery := r.builder.Update("carts").
+		Set("reminded_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "updated_at": updatedAt, "reminded_at": nil})
+	strSql, args, err := updateQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("Failed to get affected rows", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		r.logger.Debugw("Cart changed concurrently", "op", op)
+		return false, nil
+	}
+
+	insertQuery := r.builder.Insert("outbox").
+		Columns("topic", "message_key", "payload").
+		Values(message.Topic, message.Key, message.Payload)
+	strSql, args, err = insertQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	return true, nil
+}
+
+// ExpireCarts deletes every cart untouched since before, user carts along with their coupon, and
+// returns whose carts they were so their cache can go too.
+func (r *Repository) ExpireCarts(ctx context.Context, before time.Time) ([]cart.Owner, error) {
+	const op = "Cart.Repository.Postgres.ExpireCarts"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var owners []cart.Owner
+	rows, err := tx.QueryContext(ctx, `DELETE FROM carts WHERE updated_at < $1 RETURNING user_id`, before)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	var userIDs []int32
+	for rows.Next() {
+		var userID int32
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			r.logger.Errorw("Failed to scan row", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		userIDs = append(userIDs, userID)
+		owners = append(owners, cart.User(userID))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("Row iteration error", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	if len(userIDs) > 0 {
+		for _, table := range []string{"cart", "cart_coupons"} {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE user_id = ANY($1)`, pq.Array(userIDs)); err != nil {
+				r.logger.Errorw("Failed to execute SQL query", "error", err, "table", table, "op", op)
+				return nil, apierrors.ErrUnknown
+			}
+		}
+	}
+
+	// guest cart items go with their cart
+	rows, err = tx.QueryContext(ctx, `DELETE FROM guest_carts WHERE updated_at < $1 RETURNING id`, before)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	for rows.Next() {
+		var guestID string
+		if err := rows.Scan(&guestID); err != nil {
+			rows.Close()
+			r.logger.Errorw("Failed to scan row", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		owners = append(owners, cart.Guest(guestID))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("Row iteration error", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return owners, nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+// SetCoupon replaces the coupon of the user's cart, a cart holds at most one.
+func (r *Repository) SetCoupon(ctx context.Context, userID int32, code string) error {
+	const op = "Cart.Repository.Postgres.SetCoupon"
+	r.logger.Debugw("Setting cart coupon", "user_id", userID, "op", op)
+
+	query := r.builder.Insert("cart_coupons").
+		Columns("user_id", "code").
+		Values(userID, code).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET code = EXCLUDED.code, created_at = NOW()")
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := r.db.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// GetCoupon returns an empty code if the cart has no coupon.
+func (r *Repository) GetCoupon(ctx context.Context, userID int32) (string, error) {
+	const op = "Cart.Repository.Postgres.GetCoupon"
+
+	query := r.builder.Select("code").
+		From("cart_coupons").
+		Where(sq.Eq{"user_id": userID})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return "", apierrors.ErrUnknown
+	}
+
+	var code string
+	if err := r.db.QueryRowContext(ctx, strSql, args...).Scan(&code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return "", apierrors.ErrUnknown
+	}
+	return code, nil
+}
+
+// ClearCoupon is a no-op for a cart without a coupon.
+func (r *Repository) ClearCoupon(ctx context.Context, userID int32) error {
+	const op = "Cart.Repository.Postgres.ClearCoupon"
+	r.logger.Debugw("Clearing cart coupon", "user_id", userID, "op", op)
+
+	query := r.builder.Delete("cart_coupons").
+		Where(sq.Eq{"user_id": userID})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := r.db.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+func (r *Repository) CreateGuestCart(ctx context.Context, guestID string) error {
+	const op = "Cart.Repository.Postgres.CreateGuestCart"
+	r.logger.Debugw("Creating guest cart", "op", op)
+
+	query := r.builder.Insert("guest_carts").
+		Columns("id").
+		Values(guestID)
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := r.db.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// TouchGuestCart marks the guest cart as used just now. apierrors.ErrGuestCartNotFound means the
+// token doesn't belong to any cart, or the cart was merged already.
+func (r *Repository) TouchGuestCart(ctx context.Context, guestID string) error {
+	const op = "Cart.Repository.Postgres.TouchGuestCart"
+
+	query := r.builder.Update("guest_carts").
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": guestID})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := r.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("Failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		r.logger.Debugw("Guest cart not found", "op", op)
+		return apierrors.ErrGuestCartNotFound
+	}
+	return nil
+}
+
+// MergeGuestCart writes the merged lines into the user's cart and drops the guest cart in one
+// transaction, so a guest cart is merged at most once. apierrors.ErrGuestCartNotFound means it was
+// merged concurrently.
+func (r *Repository) MergeGuestCart(ctx context.Context, guestID string, userID int32, products []*models.ProductData) error {
+	const op = "Cart.Repository.Postgres.MergeGuestCart"
+	r.logger.Debugw("Merging guest cart", "user_id", userID, "products", len(products), "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	deleteQuery := r.builder.Delete("guest_carts").
+		Where(sq.Eq{"id": guestID})
+	strSql, args, err := deleteQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("Failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		r.logger.Debugw("Guest cart is gone", "op", op)
+		return apierrors.ErrGuestCartNotFound
+	}
+
+	if len(products) > 0 {
+		insertQuery := r.builder.Insert("cart").
+			Columns("user_id", "product_id", "quantity", "description", "unit_price", "currency").
+			Suffix("ON CONFLICT (user_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity, description = EXCLUDED.description, unit_price = EXCLUDED.unit_price, currency = EXCLUDED.currency, updated_at = NOW()")
+		for _, product := range products {
+			insertQuery = insertQuery.Values(userID, product.ID, product.Quantity, product.Description, product.UnitPrice, product.Currency)
+		}
+		strSql, args, err = insertQuery.ToSql()
+		if err != nil {
+			r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		if err := r.touchCart(ctx, tx, cart.User(userID)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Successfully merged guest cart", "op", op)
+	return nil
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/outbox"
+	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+// CheckoutCart takes the ordered products off the user's cart, clears the coupon and stores the
+// checkout event in one transaction, so the event is published if and only if the products left the
+// cart. Lines added or topped up after the cart was read stay in it. apierrors.ErrEmptyCart means the
+// cart was checked out concurrently, apierrors.ErrCartChanged that it lost some of the ordered products.
+func (r *Repository) CheckoutCart(ctx context.Context, userID int32, products []*models.ProductData, message *outbox.Message) error {
+	const op = "Cart.Repository.Postgres.CheckoutCart"
+	r.logger.Debugw("Taking ordered products off cart and writing checkout event to outbox", "user_id", userID, "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	selectQuery := r.builder.Select("product_id", "quantity").
+		From("cart").
+		Where(sq.Eq{"user_id": userID}).
+		Suffix("FOR UPDATE")
+	strSql, args, err := selectQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rows, err := tx.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	inCart := make(map[int32]int32)
+	for rows.Next() {
+		var productID, quantity int32
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			rows.Close()
+			r.logger.Errorw("Failed to scan row", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		inCart[productID] = quantity
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("Row iteration error", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if len(inCart) == 0 {
+		// someone else checked this cart out in the meantime
+		r.logger.Debugw("Cart is empty", "op", op)
+		return apierrors.ErrEmptyCart
+	}
+
+	var orderedIDs []int32
+	left := len(inCart)
+	for _, product := range products {
+		quantity, ok := inCart[product.ID]
+		if !ok || quantity < product.Quantity {
+			r.logger.Debugw("Ordered product is no longer in the cart", "product_id", product.ID, "op", op)
+			return apierrors.ErrCartChanged
+		}
+		if quantity == product.Quantity {
+			orderedIDs = append(orderedIDs, product.ID)
+			left--
+			continue
+		}
+		// topped up after the cart was read, the rest stays
+		strSql, args, err := r.builder.Update("cart").
+			Set("quantity", sq.Expr("quantity - ?", product.Quantity)).
+			Set("updated_at", sq.Expr("NOW()")).
+			Where(sq.Eq{"user_id": userID, "product_id": product.ID}).
+			ToSql()
+		if err != nil {
+			r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+	}
+
+	if len(orderedIDs) > 0 {
+		strSql, args, err = r.builder.Delete("cart").
+			Where(sq.Eq{"user_id": userID, "product_id": orderedIDs}).
+			ToSql()
+		if err != nil {
+			r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+	}
+
+	if left == 0 {
+		if err := r.forgetCart(ctx, tx, userID); err != nil {
+			return err
+		}
+	}
+
+	// the coupon went into the order, it's not carried over to the next cart
+	strSql, args, err = r.builder.Delete("cart_coupons").
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	insertQuery := r.builder.Insert("outbox").
+		Columns("topic", "message_key", "payload").
+		Values(message.Topic, message.Key, message.Payload)
+	strSql, args, err = insertQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Successfully checked out database cart", "op", op)
+	return nil
+}
+
+func (r *Repository) FetchPendingOutbox(ctx context.Context, limit uint64) ([]*outbox.Message, error) {
+	const op = "Cart.Repository.Postgres.FetchPendingOutbox"
+
+	query := r.builder.Select("id", "topic", "message_key", "payload", "created_at").
+		From("outbox").
+		Where(sq.Eq{"sent_at": nil}).
+		OrderBy("id").
+		Limit(limit)
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := r.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var messages []*outbox.Message
+	for rows.Next() {
+		var message outbox.Message
+		if err := rows.Scan(&message.ID, &message.Topic, &message.Key, &message.Payload, &message.CreatedAt); err != nil {
+			r.logger.Errorw("Failed to scan row", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		messages = append(messages, &message)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("Row iteration error", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return messages, nil
+}
+
+func (r *Repository) MarkOutboxSent(ctx context.Context, id int64) error {
+	const op = "Cart.Repository.Postgres.MarkOutboxSent"
+
+	query := r.builder.Update("outbox").
+		Set("sent_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := r.db.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
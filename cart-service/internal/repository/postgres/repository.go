@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
 	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 	"go.uber.org/zap"
@@ -24,14 +26,24 @@ func New(db *sql.DB, logger *zap.SugaredLogger) *Repository {
 	}
 }
 
-func (r *Repository) InsertIntoCart(ctx context.Context, userID int32, product *models.ProductData) error {
+// cartTable returns the table holding the lines of the owner's cart and the column naming the cart.
+// User carts live in cart, guest carts in guest_cart_items.
+func cartTable(owner cart.Owner) (table string, column string, id any) {
+	if owner.IsGuest() {
+		return "guest_cart_items", "guest_cart_id", owner.GuestID
+	}
+	return "cart", "user_id", owner.UserID
+}
+
+func (r *Repository) InsertIntoCart(ctx context.Context, owner cart.Owner, product *models.ProductData) error {
 	const op = "Cart.Repository.Postgres.InsertIntoCart"
-	r.logger.Debugw("Inserting cart product into database cart", "user_id", userID, "product_id", product.ID, "op", op)
+	r.logger.Debugw("Inserting cart product into database cart", "owner", owner, "product_id", product.ID, "op", op)
 
-	query := r.builder.Insert("cart").
-		Columns("user_id", "product_id", "quantity", "description").
-		Values(userID, product.ID, product.Quantity, product.Description).
-		Suffix("ON CONFLICT (user_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = NOW()")
+	table, column, id := cartTable(owner)
+	query := r.builder.Insert(table).
+		Columns(column, "product_id", "quantity", "description", "unit_price", "currency").
+		Values(id, product.ID, product.Quantity, product.Description, product.UnitPrice, product.Currency).
+		Suffix("ON CONFLICT (" + column + ", product_id) DO UPDATE SET quantity = EXCLUDED.quantity, unit_price = EXCLUDED.unit_price, currency = EXCLUDED.currency, updated_at = NOW()")
 
 	strSql, args, err := query.ToSql()
 	if err != nil {
@@ -39,20 +51,175 @@ func (r *Repository) InsertIntoCart(ctx context.Context, userID int32, product *
 		return apierrors.ErrUnknown
 	}
 
-	if _, err := r.db.ExecContext(ctx, strSql, args...); err != nil {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
 		r.logger.Errorw("Failed to execute SQL query", "error", err)
 		return apierrors.ErrUnknown
 	}
+	if err := r.touchCart(ctx, tx, owner); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
 	r.logger.Debugw("Successfully inserted product into database cart")
 	return nil
 }
 
-func (r *Repository) DeleteFromCart(ctx context.Context, userID int32, productID int32) error {
+// AddQuantities adds the quantities of products to their lines, products not in the cart yet get a
+// new line. Each product may appear only once. It's a single statement, so concurrent adds don't
+// lose each other's quantity. It returns the resulting lines.
+func (r *Repository) AddQuantities(ctx context.Context, owner cart.Owner, products []*models.ProductData) ([]*models.ProductData, error) {
+	const op = "Cart.Repository.Postgres.AddQuantities"
+	r.logger.Debugw("Adding quantities to database cart", "owner", owner, "products", len(products), "op", op)
+
+	table, column, id := cartTable(owner)
+	query := r.builder.Insert(table).
+		Columns(column, "product_id", "quantity", "description", "unit_price", "currency").
+		Suffix("ON CONFLICT (" + column + ", product_id) DO UPDATE SET quantity = " + table + ".quantity + EXCLUDED.quantity, unit_price = EXCLUDED.unit_price, currency = EXCLUDED.currency, updated_at = NOW() " +
+			"RETURNING product_id, quantity, description, unit_price, currency")
+	for _, product := range products {
+		query = query.Values(id, product.ID, product.Quantity, product.Description, product.UnitPrice, product.Currency)
+	}
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	result := make([]*models.ProductData, 0, len(products))
+	for rows.Next() {
+		var product models.ProductData
+		if err := rows.Scan(&product.ID, &product.Quantity, &product.Description, &product.UnitPrice, &product.Currency); err != nil {
+			r.logger.Errorw("Failed to scan row", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		result = append(result, &product)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("Row iteration error", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	rows.Close()
+	if err := r.touchCart(ctx, tx, owner); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Successfully added quantities to database cart", "op", op)
+	return result, nil
+}
+
+// DecrementQuantity takes by off the quantity of a product in the cart and returns the line.
+// A line that would drop to zero or below is removed, then the returned line is nil.
+// apierrors.ErrProductNotInCart means there is no such line.
+func (r *Repository) DecrementQuantity(ctx context.Context, owner cart.Owner, productID int32, by int32) (*models.ProductData, error) {
+	const op = "Cart.Repository.Postgres.DecrementQuantity"
+	r.logger.Debugw("Decrementing quantity in database cart", "owner", owner, "product_id", productID, "by", by, "op", op)
+
+	table, column, id := cartTable(owner)
+	updateQuery := r.builder.Update(table).
+		Set("quantity", sq.Expr("quantity - ?", by)).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{column: id, "product_id": productID}).
+		Where(sq.Gt{"quantity": by}).
+		Suffix("RETURNING product_id, quantity, description, unit_price, currency")
+
+	strSql, args, err := updateQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var product models.ProductData
+	err = tx.QueryRowContext(ctx, strSql, args...).
+		Scan(&product.ID, &product.Quantity, &product.Description, &product.UnitPrice, &product.Currency)
+	if err == nil {
+		if err := r.touchCart(ctx, tx, owner); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		return &product, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	// not enough left to keep the line
+	deleteQuery := r.builder.Delete(table).
+		Where(sq.Eq{column: id, "product_id": productID}).
+		Where(sq.LtOrEq{"quantity": by})
+	strSql, args, err = deleteQuery.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("Failed to get affected rows", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		r.logger.Debugw("Product is not in the cart", "op", op)
+		return nil, apierrors.ErrProductNotInCart
+	}
+	if err := r.touchCart(ctx, tx, owner); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Removed product from database cart", "op", op)
+	return nil, nil
+}
+
+func (r *Repository) DeleteFromCart(ctx context.Context, owner cart.Owner, productID int32) error {
 	const op = "Cart.Repository.Postgres.DeleteFromCart"
-	r.logger.Debugw("Deleting cart product from database cart", "user_id", userID, "product_id", productID, "op", op)
+	r.logger.Debugw("Deleting cart product from database cart", "owner", owner, "product_id", productID, "op", op)
 
-	query := r.builder.Delete("cart").
-		Where(sq.Eq{"user_id": userID, "product_id": productID})
+	table, column, id := cartTable(owner)
+	query := r.builder.Delete(table).
+		Where(sq.Eq{column: id, "product_id": productID})
 
 	strSql, args, err := query.ToSql()
 	if err != nil {
@@ -60,17 +227,32 @@ func (r *Repository) DeleteFromCart(ctx context.Context, userID int32, productID
 		return apierrors.ErrUnknown
 	}
 
-	if _, err := r.db.ExecContext(ctx, strSql, args...); err != nil {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
 		r.logger.Errorw("Failed to execute SQL query", "error", err)
 		return apierrors.ErrUnknown
 	}
+	if err := r.touchCart(ctx, tx, owner); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
 	r.logger.Debugw("Successfully deleted product from database cart")
 	return nil
 }
-func (r *Repository) GetCart(ctx context.Context, userID int32) ([]*models.ProductData, error) {
-	query := r.builder.Select("user_id", "product_id", "quantity", "description").
-		From("cart").
-		Where(sq.Eq{"user_id": userID})
+func (r *Repository) GetCart(ctx context.Context, owner cart.Owner) ([]*models.ProductData, error) {
+	table, column, id := cartTable(owner)
+	query := r.builder.Select("product_id", "quantity", "description", "unit_price", "currency").
+		From(table).
+		Where(sq.Eq{column: id})
 	strSql, args, err := query.ToSql()
 	if err != nil {
 		r.logger.Errorw("Failed to build SQL query", "error", err)
@@ -87,7 +269,7 @@ func (r *Repository) GetCart(ctx context.Context, userID int32) ([]*models.Produ
 	var products []*models.ProductData
 	for rows.Next() {
 		var product models.ProductData
-		if err := rows.Scan(&product.ID, &product.ID, &product.Quantity, &product.Description); err != nil {
+		if err := rows.Scan(&product.ID, &product.Quantity, &product.Description, &product.UnitPrice, &product.Currency); err != nil {
 			r.logger.Errorw("Failed to scan row", "error", err)
 			return nil, apierrors.ErrUnknown
 		}
@@ -97,6 +279,40 @@ func (r *Repository) GetCart(ctx context.Context, userID int32) ([]*models.Produ
 	return products, nil
 }
 
-func (r *Repository) ClearCart(ctx context.Context, userID int32) error {
-	panic("implement me")
+func (r *Repository) ClearCart(ctx context.Context, owner cart.Owner) error {
+	const op = "Cart.Repository.Postgres.ClearCart"
+	r.logger.Debugw("Clearing database cart", "owner", owner, "op", op)
+
+	table, column, id := cartTable(owner)
+	query := r.builder.Delete(table).
+		Where(sq.Eq{column: id})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("Failed to execute SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if !owner.IsGuest() {
+		if err := r.forgetCart(ctx, tx, owner.UserID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Successfully cleared database cart", "op", op)
+	return nil
 }
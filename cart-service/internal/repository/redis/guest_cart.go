@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+// countScript starts the window with the first count, later counts don't move its end.
+var countScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// CountGuestCart counts one more guest cart created from the address and returns how many were
+// created in the current window.
+func (r *Repository) CountGuestCart(ctx context.Context, clientIP string, window time.Duration) (int64, error) {
+	const op = "Cart.Repository.Redis.CountGuestCart"
+
+	count, err := countScript.Run(ctx, r.client, []string{"guest_carts:" + clientIP}, window.Milliseconds()).Int64()
+	if err != nil {
+		r.logger.Errorw("failed to count guest carts in Redis", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return count, nil
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
 	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 	"go.uber.org/zap"
@@ -14,17 +16,40 @@ import (
 
 type Repository struct {
 	client *redis.Client
+	ttl    time.Duration
 	logger *zap.SugaredLogger
 }
 
-func New(client *redis.Client, logger *zap.SugaredLogger) *Repository {
+// New keeps cached carts for ttl after their last change, as long as storage keeps them. A cart is
+// cached whole on the first read after it expired, writes only update carts that are cached.
+func New(client *redis.Client, ttl time.Duration, logger *zap.SugaredLogger) *Repository {
 	return &Repository{
 		client: client,
+		ttl:    ttl,
 		logger: logger,
 	}
 }
 
-func (r *Repository) InsertIntoCart(ctx context.Context, userID int32, product *models.ProductData) error {
+// cartKey keeps the key of user carts as it was, guest carts get their own prefix.
+func cartKey(owner cart.Owner) string {
+	if owner.IsGuest() {
+		return "cart:guest:" + owner.GuestID
+	}
+	return fmt.Sprintf("cart:%d", owner.UserID)
+}
+
+// insertScript only updates carts that are cached in full. A cart that isn't cached is left to
+// FillCart, so the cache never holds part of a cart.
+var insertScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+return 1
+`)
+
+func (r *Repository) InsertIntoCart(ctx context.Context, owner cart.Owner, product *models.ProductData) error {
 	const op = "Cart.Repository.Redis.InsertIntoCart"
 	r.logger.Debugw("Inserting cart product into Redis cart", "op", op)
 
@@ -34,9 +59,9 @@ func (r *Repository) InsertIntoCart(ctx context.Context, userID int32, product *
 		return apierrors.ErrUnknown
 	}
 
-	key := fmt.Sprintf("cart:%d", userID)
+	key := cartKey(owner)
 	field := strconv.Itoa(int(product.ID))
-	err = r.client.HSet(ctx, key, field, productJSON).Err()
+	err = insertScript.Run(ctx, r.client, []string{key}, field, productJSON, r.ttl.Milliseconds()).Err()
 	if err != nil {
 		r.logger.Errorw("failed to append product to Redis", "error", err, "op", op)
 		return apierrors.ErrUnknown
@@ -44,13 +69,17 @@ func (r *Repository) InsertIntoCart(ctx context.Context, userID int32, product *
 	r.logger.Debugw("Successfully inserted product into Redis cart", "op", op)
 	return nil
 }
-func (r *Repository) DeleteFromCart(ctx context.Context, userID int32, productID int32) error {
+func (r *Repository) DeleteFromCart(ctx context.Context, owner cart.Owner, productID int32) error {
 	const op = "Cart.Repository.Redis.DeleteFromCart"
 	r.logger.Debugw("Deleting cart product from Redis cart", "op", op)
 
-	key := fmt.Sprintf("cart:%d", userID)
+	key := cartKey(owner)
 	field := strconv.Itoa(int(productID))
-	err := r.client.HDel(ctx, key, field).Err()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, key, field)
+		pipe.Expire(ctx, key, r.ttl)
+		return nil
+	})
 	if err != nil {
 		r.logger.Errorw("failed to delete product from Redis", "error", err, "op", op)
 		return apierrors.ErrUnknown
@@ -58,11 +87,43 @@ func (r *Repository) DeleteFromCart(ctx context.Context, userID int32, productID
 	r.logger.Debugw("Successfully deleted product from Redis cart", "op", op)
 	return nil
 }
-func (r *Repository) GetCart(ctx context.Context, userID int32) ([]*models.ProductData, error) {
+
+// FillCart caches the whole cart as read from storage, replacing whatever was cached.
+func (r *Repository) FillCart(ctx context.Context, owner cart.Owner, products []*models.ProductData) error {
+	const op = "Cart.Repository.Redis.FillCart"
+	r.logger.Debugw("Filling Redis cart", "op", op)
+
+	fields := make([]any, 0, 2*len(products))
+	for _, product := range products {
+		productJSON, err := json.Marshal(product)
+		if err != nil {
+			r.logger.Errorw("failed to marshal product to json", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		fields = append(fields, strconv.Itoa(int(product.ID)), productJSON)
+	}
+
+	key := cartKey(owner)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(fields) > 0 {
+			pipe.HSet(ctx, key, fields...)
+			pipe.Expire(ctx, key, r.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Errorw("failed to fill cart in Redis", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Successfully filled Redis cart", "op", op)
+	return nil
+}
+func (r *Repository) GetCart(ctx context.Context, owner cart.Owner) ([]*models.ProductData, error) {
 	const op = "Cart.Repository.Redis.GetCart"
 	r.logger.Debugw("Getting cart from Redis", "op", op)
 
-	key := fmt.Sprintf("cart:%d", userID)
+	key := cartKey(owner)
 	products, err := r.client.HGetAll(ctx, key).Result()
 	if err != nil {
 		r.logger.Errorw("failed to get cart products from Redis", "error", err, "op", op)
@@ -87,6 +148,15 @@ func (r *Repository) GetCart(ctx context.Context, userID int32) ([]*models.Produ
 	r.logger.Debugw("Successfully retrieved cart from Redis", "op", op)
 	return result, nil
 }
-func (r *Repository) ClearCart(ctx context.Context, userID int32) error {
-	panic("implement me")
+func (r *Repository) ClearCart(ctx context.Context, owner cart.Owner) error {
+	const op = "Cart.Repository.Redis.ClearCart"
+	r.logger.Debugw("Clearing Redis cart", "op", op)
+
+	key := cartKey(owner)
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		r.logger.Errorw("failed to clear cart in Redis", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	r.logger.Debugw("Successfully cleared Redis cart", "op", op)
+	return nil
 }
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/outbox"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/service/messaging"
+)
+
+// AbandonedCarts says when carts nobody touches are reminded about and when they are dropped.
+type AbandonedCarts struct {
+	// RemindAfter of idling a user cart gets one cart-abandoned event on Topic, per idle period
+	RemindAfter time.Duration
+	// TTL of idling and a cart is deleted from storage and cache, guest carts included
+	TTL       time.Duration
+	Topic     string
+	BatchSize uint64
+}
+
+// SweepCarts queues reminders for abandoned carts and deletes expired ones every interval until ctx
+// is cancelled.
+func (s *Service) SweepCarts(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.remindAbandonedCarts(ctx)
+			s.expireCarts(ctx)
+		}
+	}
+}
+
+func (s *Service) remindAbandonedCarts(ctx context.Context) {
+	const op = "Cart.Service.remindAbandonedCarts"
+
+	abandoned, err := s.storage.FindAbandonedCarts(ctx, time.Now().Add(-s.abandoned.RemindAfter), s.abandoned.BatchSize)
+	if err != nil {
+		s.logger.Errorw("Failed to find abandoned carts", "error", err, "op", op)
+		return
+	}
+
+	var reminded int
+	for _, c := range abandoned {
+		eventID, err := messaging.NewEventID()
+		if err != nil {
+			s.logger.Errorw("Failed to generate event ID", "error", err, "op", op)
+			return
+		}
+		payload, err := messaging.SerializeCartAbandoned(eventID, c.UserID, c.UpdatedAt, c.Products)
+		if err != nil {
+			s.logger.Errorw("Failed to serialize cart abandoned message", "error", err, "user_id", c.UserID, "op", op)
+			continue
+		}
+
+		// the event goes through the outbox along with the mark, so each idle period is reminded about once
+		queued, err := s.storage.MarkCartAbandoned(ctx, c.UserID, c.UpdatedAt, &outbox.Message{
+			Topic:   s.abandoned.Topic,
+			Key:     strconv.Itoa(int(c.UserID)),
+			Payload: payload,
+		})
+		if err != nil {
+			s.logger.Errorw("Failed to mark cart abandoned", "error", err, "user_id", c.UserID, "op", op)
+			continue
+		}
+		if queued {
+			reminded++
+		}
+	}
+	if reminded > 0 {
+		s.logger.Infow("Queued abandoned cart reminders", "carts", reminded, "op", op)
+	}
+}
+
+func (s *Service) expireCarts(ctx context.Context) {
+	const op = "Cart.Service.expireCarts"
+
+	owners, err := s.storage.ExpireCarts(ctx, time.Now().Add(-s.abandoned.TTL))
+	if err != nil {
+		s.logger.Errorw("Failed to expire carts", "error", err, "op", op)
+		return
+	}
+	// cached carts expire on their own as well, this only drops them sooner
+	for _, owner := range owners {
+		if err := s.cache.ClearCart(ctx, owner); err != nil {
+			s.logger.Warnw("Failed to clear cached expired cart", "error", err, "owner", owner, "op", op)
+		}
+	}
+	if len(owners) > 0 {
+		s.logger.Infow("Expired idle carts", "carts", len(owners), "op", op)
+	}
+}
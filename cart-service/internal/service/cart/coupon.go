@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ApplyCoupon puts the coupon on the user's cart if the cart qualifies for it right now, and returns
+// the cart with the discount. Guests have to sign in first, limits are counted per user.
+func (s *Service) ApplyCoupon(ctx context.Context, userID int32, code string) (*cart.PricedCart, error) {
+	const op = "Cart.Service.ApplyCoupon"
+	s.logger.Debugw("Applying coupon", "user_id", userID, "op", op)
+
+	code = promotions.NormalizeCode(code)
+	promotion, err := s.getPromotion(ctx, code, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := cart.User(userID)
+	priced, err := s.PriceCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := promotion.Discount(couponLines(priced.Lines), priced.Currency, time.Now())
+	if err != nil {
+		s.logger.Debugw("Cart doesn't qualify for coupon", "error", err, "op", op)
+		return nil, err
+	}
+
+	if err := s.storage.SetCoupon(ctx, userID, code); err != nil {
+		s.logger.Errorw("Failed to set coupon", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	priced.Coupon = code
+	priced.CouponApplied = true
+	priced.Discount = discount
+	priced.GrandTotal = priced.Subtotal - priced.Discount + priced.Tax
+	return priced, nil
+}
+
+func (s *Service) RemoveCoupon(ctx context.Context, userID int32) (*cart.PricedCart, error) {
+	const op = "Cart.Service.RemoveCoupon"
+	s.logger.Debugw("Removing coupon", "user_id", userID, "op", op)
+
+	if err := s.storage.ClearCoupon(ctx, userID); err != nil {
+		s.logger.Errorw("Failed to clear coupon", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return s.PriceCart(ctx, cart.User(userID))
+}
+
+// applyCoupon sets the discount of the coupon on the owner's cart, if it has one. A coupon the cart
+// doesn't qualify for stays on it without a discount, it may qualify again once more is added.
+func (s *Service) applyCoupon(ctx context.Context, owner cart.Owner, priced *cart.PricedCart) error {
+	const op = "Cart.Service.applyCoupon"
+	if owner.IsGuest() {
+		return nil
+	}
+
+	code, err := s.storage.GetCoupon(ctx, owner.UserID)
+	if err != nil {
+		s.logger.Errorw("Failed to get coupon", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if code == "" {
+		return nil
+	}
+	priced.Coupon = code
+
+	promotion, err := s.getPromotion(ctx, code, owner.UserID)
+	if errors.Is(err, apierrors.ErrPromotionNotFound) || errors.Is(err, apierrors.ErrPromotionLimitReached) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	discount, err := promotion.Discount(couponLines(priced.Lines), priced.Currency, time.Now())
+	if err != nil {
+		s.logger.Debugw("Cart doesn't qualify for coupon", "error", err, "op", op)
+		return nil
+	}
+	priced.Discount = discount
+	priced.CouponApplied = true
+	return nil
+}
+
+// checkoutDiscount returns the coupon and its discount on the cart being checked out. It is computed
+// from the same lines and prices as the discount shown on the cart. A coupon the cart doesn't
+// qualify for fails the checkout, the user has to remove it.
+func (s *Service) checkoutDiscount(ctx context.Context, userID int32, priced *cart.PricedCart) (string, int64, error) {
+	const op = "Cart.Service.checkoutDiscount"
+
+	code, err := s.storage.GetCoupon(ctx, userID)
+	if err != nil {
+		s.logger.Errorw("Failed to get coupon", "error", err, "op", op)
+		return "", 0, apierrors.ErrFailedToCheckout
+	}
+	if code == "" || len(priced.Lines) == 0 {
+		return "", 0, nil
+	}
+
+	promotion, err := s.getPromotion(ctx, code, userID)
+	if err != nil {
+		return "", 0, err
+	}
+	discount, err := promotion.Discount(couponLines(priced.Lines), priced.Currency, time.Now())
+	if err != nil {
+		s.logger.Debugw("Cart doesn't qualify for coupon", "error", err, "op", op)
+		return "", 0, err
+	}
+	return code, discount, nil
+}
+
+// getPromotion looks the coupon up in products-service. The redemption limit is only checked here,
+// order-service enforces it when the order is created.
+func (s *Service) getPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, error) {
+	const op = "Cart.Service.getPromotion"
+
+	promotion, redemptions, err := s.productsProvider.GetPromotion(ctx, code, userID)
+	if status.Code(err) == codes.NotFound {
+		s.logger.Debugw("Promotion not found", "op", op)
+		return nil, apierrors.ErrPromotionNotFound
+	} else if err != nil {
+		s.logger.Errorw("Failed to get promotion", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToReadPromotion
+	}
+	if promotion.LimitReached(redemptions) {
+		s.logger.Debugw("Promotion limit reached", "op", op)
+		return nil, apierrors.ErrPromotionLimitReached
+	}
+	return promotion, nil
+}
+
+// couponLines are the lines still in the catalog, at their current price.
+func couponLines(lines []*cart.Line) []promotions.Line {
+	couponLines := make([]promotions.Line, 0, len(lines))
+	for _, line := range lines {
+		if line.ProductDeleted {
+			continue
+		}
+		couponLines = append(couponLines, promotions.Line{
+			ProductID: line.Product.ID,
+			Quantity:  line.Product.Quantity,
+			UnitPrice: line.CurrentUnitPrice,
+		})
+	}
+	return couponLines
+}
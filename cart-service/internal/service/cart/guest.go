@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+// MergePolicy decides the quantity of a product that is in both the guest and the user cart when
+// the guest cart is merged on login.
+type MergePolicy string
+
+const (
+	// MergeSum adds up both quantities.
+	MergeSum MergePolicy = "sum"
+	// MergeMax keeps the larger quantity.
+	MergeMax MergePolicy = "max"
+	// MergeKeepUser keeps the line of the user cart.
+	MergeKeepUser MergePolicy = "keep_user"
+	// MergeKeepGuest takes the line of the guest cart, it was picked more recently.
+	MergeKeepGuest MergePolicy = "keep_guest"
+)
+
+func ParseMergePolicy(policy string) (MergePolicy, error) {
+	switch p := MergePolicy(policy); p {
+	case MergeSum, MergeMax, MergeKeepUser, MergeKeepGuest:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown cart merge policy %q", policy)
+	}
+}
+
+// GuestCartLimit caps the guest carts one client address may start, the call needs no account.
+type GuestCartLimit struct {
+	// Limit carts per Window, 0 turns the limit off
+	Limit  int64
+	Window time.Duration
+}
+
+// CreateGuestCart starts an empty cart for a visitor that isn't signed in. The returned token is
+// the only way to reach the cart, only its hash is stored. apierrors.ErrTooManyGuestCarts means
+// clientIP used up its limit.
+func (s *Service) CreateGuestCart(ctx context.Context, clientIP string) (string, error) {
+	const op = "Cart.Service.CreateGuestCart"
+	s.logger.Debugw("Creating guest cart", "client_ip", clientIP, "op", op)
+
+	if s.guestLimit.Limit > 0 && clientIP != "" {
+		count, err := s.cache.CountGuestCart(ctx, clientIP, s.guestLimit.Window)
+		if err != nil {
+			// the limit is kept in the cache, carts are still made while it is down
+			s.logger.Warnw("Failed to count guest carts", "error", err, "op", op)
+		} else if count > s.guestLimit.Limit {
+			s.logger.Warnw("Guest cart limit reached", "client_ip", clientIP, "op", op)
+			return "", apierrors.ErrTooManyGuestCarts
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Errorw("Failed to generate cart token", "error", err, "op", op)
+		return "", apierrors.ErrUnknown
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.storage.CreateGuestCart(ctx, guestID(token)); err != nil {
+		s.logger.Errorw("Failed to create guest cart", "error", err, "op", op)
+		return "", apierrors.ErrUnknown
+	}
+	return token, nil
+}
+
+// OpenGuestCart returns the owner of the guest cart the token belongs to.
+func (s *Service) OpenGuestCart(ctx context.Context, token string) (cart.Owner, error) {
+	const op = "Cart.Service.OpenGuestCart"
+
+	owner := cart.Guest(guestID(token))
+	err := s.storage.TouchGuestCart(ctx, owner.GuestID)
+	if errors.Is(err, apierrors.ErrGuestCartNotFound) {
+		s.logger.Debugw("Unknown cart token", "op", op)
+		return cart.Owner{}, err
+	} else if err != nil {
+		s.logger.Errorw("Failed to open guest cart", "error", err, "op", op)
+		return cart.Owner{}, apierrors.ErrUnknown
+	}
+	return owner, nil
+}
+
+// MergeCart moves the guest cart of token into the user's cart and returns the result. Products
+// in both carts are resolved by the merge policy. The guest cart is gone afterwards.
+func (s *Service) MergeCart(ctx context.Context, userID int32, token string) (*cart.PricedCart, error) {
+	const op = "Cart.Service.MergeCart"
+	s.logger.Debugw("Merging guest cart", "user_id", userID, "policy", s.mergePolicy, "op", op)
+
+	guest := cart.Guest(guestID(token))
+	user := cart.User(userID)
+
+	// storage is the source of truth, the cache may lag behind it
+	guestProducts, err := s.storage.GetCart(ctx, guest)
+	if err != nil {
+		s.logger.Errorw("Failed to get guest cart", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToGetCart
+	}
+	userProducts, err := s.storage.GetCart(ctx, user)
+	if err != nil {
+		s.logger.Errorw("Failed to get user cart", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToGetCart
+	}
+
+	merged, err := mergeProducts(s.mergePolicy, userProducts, guestProducts)
+	if err != nil {
+		s.logger.Debugw("Carts can't be merged", "error", err, "op", op)
+		return nil, err
+	}
+
+	err = s.storage.MergeGuestCart(ctx, guest.GuestID, userID, merged)
+	if errors.Is(err, apierrors.ErrGuestCartNotFound) {
+		s.logger.Debugw("Guest cart not found", "op", op)
+		return nil, err
+	} else if err != nil {
+		s.logger.Errorw("Failed to merge storage carts", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	products, err := s.storage.GetCart(ctx, user)
+	if err != nil {
+		s.logger.Errorw("Failed to get merged cart", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToGetCart
+	}
+
+	// refilling the cache from storage, reads fall back to storage if any of this fails
+	if err := s.cache.ClearCart(ctx, guest); err != nil {
+		s.logger.Warnw("Failed to clear cached guest cart", "error", err, "op", op)
+	}
+	if err := s.cache.FillCart(ctx, user, products); err != nil {
+		s.logger.Warnw("Failed to cache merged cart", "error", err, "op", op)
+		_ = s.cache.ClearCart(ctx, user)
+	}
+
+	s.logger.Debugw("Successfully merged guest cart", "op", op)
+	return s.priceProducts(ctx, user, products)
+}
+
+// mergeProducts returns the lines of the user cart that change: every guest product, resolved
+// against the user cart by policy. Both carts have to be in the same currency.
+func mergeProducts(policy MergePolicy, userProducts []*models.ProductData, guestProducts []*models.ProductData) ([]*models.ProductData, error) {
+	var currency string
+	byID := make(map[int32]*models.ProductData, len(userProducts))
+	for _, product := range userProducts {
+		byID[product.ID] = product
+		currency = product.Currency
+	}
+
+	merged := make([]*models.ProductData, 0, len(guestProducts))
+	for _, guestProduct := range guestProducts {
+		if currency != "" && guestProduct.Currency != currency {
+			return nil, apierrors.ErrCurrencyMismatch
+		}
+
+		userProduct, ok := byID[guestProduct.ID]
+		if !ok {
+			merged = append(merged, guestProduct)
+			continue
+		}
+
+		product := *userProduct
+		switch policy {
+		case MergeSum:
+			product.Quantity += guestProduct.Quantity
+		case MergeMax:
+			product.Quantity = max(userProduct.Quantity, guestProduct.Quantity)
+		case MergeKeepGuest:
+			product = *guestProduct
+		case MergeKeepUser:
+			continue
+		}
+		merged = append(merged, &product)
+	}
+	return merged, nil
+}
+
+func guestID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+)
+
+func TestMergeProducts(t *testing.T) {
+	line := func(id int32, quantity int32, unitPrice int64, currency string) *models.ProductData {
+		return &models.ProductData{ID: id, Quantity: quantity, UnitPrice: unitPrice, Currency: currency}
+	}
+
+	tests := []struct {
+		name    string
+		policy  MergePolicy
+		user    []*models.ProductData
+		guest   []*models.ProductData
+		want    []models.ProductData
+		wantErr error
+	}{
+		{
+			name:   "empty user cart takes the guest cart",
+			policy: MergeSum,
+			guest:  []*models.ProductData{line(1, 2, 100, "USD"), line(2, 1, 50, "USD")},
+			want:   []models.ProductData{*line(1, 2, 100, "USD"), *line(2, 1, 50, "USD")},
+		},
+		{
+			name:   "empty guest cart changes nothing",
+			policy: MergeSum,
+			user:   []*models.ProductData{line(1, 2, 100, "USD")},
+			want:   []models.ProductData{},
+		},
+		{
+			name:   "sum adds up both quantities",
+			policy: MergeSum,
+			user:   []*models.ProductData{line(1, 2, 100, "USD"), line(3, 1, 10, "USD")},
+			guest:  []*models.ProductData{line(1, 3, 120, "USD"), line(2, 1, 50, "USD")},
+			want:   []models.ProductData{*line(1, 5, 100, "USD"), *line(2, 1, 50, "USD")},
+		},
+		{
+			name:   "max keeps the larger quantity",
+			policy: MergeMax,
+			user:   []*models.ProductData{line(1, 2, 100, "USD"), line(2, 4, 50, "USD")},
+			guest:  []*models.ProductData{line(1, 3, 120, "USD"), line(2, 1, 60, "USD")},
+			want:   []models.ProductData{*line(1, 3, 100, "USD"), *line(2, 4, 50, "USD")},
+		},
+		{
+			name:   "keep user leaves common lines alone",
+			policy: MergeKeepUser,
+			user:   []*models.ProductData{line(1, 2, 100, "USD")},
+			guest:  []*models.ProductData{line(1, 3, 120, "USD"), line(2, 1, 50, "USD")},
+			want:   []models.ProductData{*line(2, 1, 50, "USD")},
+		},
+		{
+			name:   "keep guest takes the guest line",
+			policy: MergeKeepGuest,
+			user:   []*models.ProductData{line(1, 2, 100, "USD")},
+			guest:  []*models.ProductData{line(1, 3, 120, "USD")},
+			want:   []models.ProductData{*line(1, 3, 120, "USD")},
+		},
+		{
+			name:    "carts in different currencies",
+			policy:  MergeSum,
+			user:    []*models.ProductData{line(1, 2, 100, "USD")},
+			guest:   []*models.ProductData{line(2, 1, 50, "EUR")},
+			wantErr: apierrors.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := mergeProducts(tt.policy, tt.user, tt.guest)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("mergeProducts() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got := make([]models.ProductData, 0, len(merged))
+			for _, product := range merged {
+				got = append(got, *product)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeProducts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeProductsKeepsInputs(t *testing.T) {
+	user := []*models.ProductData{{ID: 1, Quantity: 2, UnitPrice: 100, Currency: "USD"}}
+	guest := []*models.ProductData{{ID: 1, Quantity: 3, UnitPrice: 100, Currency: "USD"}}
+
+	if _, err := mergeProducts(MergeSum, user, guest); err != nil {
+		t.Fatalf("mergeProducts() error = %v", err)
+	}
+	if user[0].Quantity != 2 || guest[0].Quantity != 3 {
+		t.Errorf("mergeProducts() changed its inputs: user %d, guest %d", user[0].Quantity, guest[0].Quantity)
+	}
+}
+
+// guestStorage and guestCache only know guest carts, anything else panics through the nil interfaces.
+type guestStorage struct {
+	Storage
+	created int
+}
+
+func (s *guestStorage) CreateGuestCart(ctx context.Context, guestID string) error {
+	s.created++
+	return nil
+}
+
+type guestCache struct {
+	Cache
+	counts map[string]int64
+	err    error
+}
+
+func (c *guestCache) CountGuestCart(ctx context.Context, clientIP string, window time.Duration) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	c.counts[clientIP]++
+	return c.counts[clientIP], nil
+}
+
+func TestCreateGuestCartLimit(t *testing.T) {
+	storage := &guestStorage{}
+	cache := &guestCache{counts: map[string]int64{}}
+	s := New(storage, cache, nil, nil, "", MergeSum, GuestCartLimit{Limit: 2, Window: time.Hour}, AbandonedCarts{}, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.CreateGuestCart(ctx, "203.0.113.7"); err != nil {
+			t.Fatalf("cart %d: CreateGuestCart() error = %v", i+1, err)
+		}
+	}
+	if _, err := s.CreateGuestCart(ctx, "203.0.113.7"); !errors.Is(err, apierrors.ErrTooManyGuestCarts) {
+		t.Errorf("third cart: error = %v, want %v", err, apierrors.ErrTooManyGuestCarts)
+	}
+	if _, err := s.CreateGuestCart(ctx, "203.0.113.8"); err != nil {
+		t.Errorf("another address: CreateGuestCart() error = %v", err)
+	}
+
+	// a cache that is down doesn't stop guests from shopping
+	cache.err = apierrors.ErrUnknown
+	if _, err := s.CreateGuestCart(ctx, "203.0.113.7"); err != nil {
+		t.Errorf("cache down: CreateGuestCart() error = %v", err)
+	}
+	if storage.created != 4 {
+		t.Errorf("stored %d guest carts, want 4", storage.created)
+	}
+}
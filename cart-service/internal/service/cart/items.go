@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	protoProducts "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+// MaxItemsPerRequest caps AddItems, products-service looks up at most that many products at once.
+const MaxItemsPerRequest = 100
+
+// AddToCart adds quantity of the product to the cart, on top of what is already there.
+func (s *Service) AddToCart(ctx context.Context, owner cart.Owner, productID int32, quantity int32) error {
+	const op = "Cart.Service.AddToCart"
+	s.logger.Debugw("Adding product to cart", "product_id", productID, "op", op)
+
+	return s.addItems(ctx, owner, []cart.Item{{ProductID: productID, Quantity: quantity}})
+}
+
+// AddItems adds several products to the cart and returns the cart. The products are checked in one
+// round trip to products-service and either all of them are added or none.
+func (s *Service) AddItems(ctx context.Context, owner cart.Owner, items []cart.Item) (*cart.PricedCart, error) {
+	const op = "Cart.Service.AddItems"
+	s.logger.Debugw("Adding products to cart", "items", len(items), "op", op)
+
+	if err := s.addItems(ctx, owner, items); err != nil {
+		return nil, err
+	}
+	return s.PriceCart(ctx, owner)
+}
+
+// IncrementQuantity changes the quantity of a product already in the cart by delta, which may be
+// negative. The product is removed once nothing is left of it.
+func (s *Service) IncrementQuantity(ctx context.Context, owner cart.Owner, productID int32, delta int32) error {
+	const op = "Cart.Service.IncrementQuantity"
+	s.logger.Debugw("Incrementing product quantity", "product_id", productID, "delta", delta, "op", op)
+
+	products, err := s.GetCart(ctx, owner)
+	if err != nil && !errors.Is(err, apierrors.ErrEmptyCart) {
+		s.logger.Errorw("Failed to get cart", "error", err, "op", op)
+		return apierrors.ErrFailedToGetCart
+	}
+	var line *models.ProductData
+	for _, product := range products {
+		if product.ID == productID {
+			line = product
+		}
+	}
+	if line == nil {
+		s.logger.Debugw("Product is not in the cart", "op", op)
+		return apierrors.ErrProductNotInCart
+	}
+
+	if delta > 0 {
+		return s.addItems(ctx, owner, []cart.Item{{ProductID: productID, Quantity: delta}})
+	}
+
+	product, err := s.storage.DecrementQuantity(ctx, owner, productID, -delta)
+	if errors.Is(err, apierrors.ErrProductNotInCart) {
+		s.logger.Debugw("Product was removed concurrently", "op", op)
+		return err
+	} else if err != nil {
+		s.logger.Errorw("Failed to decrement quantity: storage", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if product == nil {
+		err = s.cache.DeleteFromCart(ctx, owner, productID)
+	} else {
+		product.ProductName = line.ProductName
+		err = s.cache.InsertIntoCart(ctx, owner, product)
+	}
+	if err != nil {
+		s.logger.Errorw("Failed to decrement quantity: cache", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// addItems checks stock and currency of every item against products-service and the cart, then
+// adds them all in one statement. Items of the same product are added up.
+func (s *Service) addItems(ctx context.Context, owner cart.Owner, items []cart.Item) error {
+	const op = "Cart.Service.addItems"
+
+	quantities := make(map[int32]int32, len(items))
+	ids := make([]int32, 0, len(items))
+	for _, item := range items {
+		if _, ok := quantities[item.ProductID]; !ok {
+			ids = append(ids, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	provided, err := s.productsProvider.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Errorw("Failed to get products", "error", err, "op", op)
+		return apierrors.ErrFailedToReadProduct
+	}
+	providedByID := make(map[int32]*protoProducts.Product, len(provided))
+	for _, product := range provided {
+		providedByID[product.Id] = product
+	}
+
+	products, err := s.GetCart(ctx, owner)
+	if err != nil && !errors.Is(err, apierrors.ErrEmptyCart) {
+		s.logger.Errorw("Failed to get cart", "error", err, "op", op)
+		return apierrors.ErrFailedToGetCart
+	}
+	inCart := make(map[int32]int32, len(products))
+	// every line of a cart has to be in the same currency for the total to make sense
+	var currency string
+	for _, product := range products {
+		inCart[product.ID] = product.Quantity
+		if _, ok := quantities[product.ID]; !ok {
+			currency = product.Currency
+		}
+	}
+
+	lines := make([]*models.ProductData, 0, len(ids))
+	for _, id := range ids {
+		providedProduct, ok := providedByID[id]
+		if !ok {
+			s.logger.Debugw("Product not found", "product_id", id, "op", op)
+			return apierrors.ErrProductNotFound
+		}
+		if providedProduct.Quantity < inCart[id]+quantities[id] {
+			s.logger.Debugw("Not enought product", "product_id", id, "op", op)
+			return apierrors.ErrNotEnoughProduct
+		}
+		if currency == "" {
+			currency = providedProduct.Currency
+		} else if providedProduct.Currency != currency {
+			s.logger.Debugw("Currency mismatch", "cart_currency", currency, "product_currency", providedProduct.Currency, "op", op)
+			return apierrors.ErrCurrencyMismatch
+		}
+
+		lines = append(lines, &models.ProductData{
+			ID:          id,
+			ProductName: providedProduct.ProductName,
+			Quantity:    quantities[id],
+			Description: providedProduct.Description,
+			UnitPrice:   providedProduct.Price,
+			Currency:    providedProduct.Currency,
+		})
+	}
+
+	added, err := s.storage.AddQuantities(ctx, owner, lines)
+	if err != nil {
+		s.logger.Errorw("Failed to add products to cart: storage", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	for _, product := range added {
+		product.ProductName = providedByID[product.ID].ProductName
+		if err := s.cache.InsertIntoCart(ctx, owner, product); err != nil {
+			s.logger.Errorw("Failed to add products to cart: cache", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+	}
+	s.logger.Debugw("Successfully added products to cart", "op", op)
+	return nil
+}
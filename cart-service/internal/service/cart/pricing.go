@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	protoProducts "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+// PriceCart returns the cart re-priced against products-service, with the lines whose product was
+// deleted, ran short of stock or changed its price flagged.
+func (s *Service) PriceCart(ctx context.Context, owner cart.Owner) (*cart.PricedCart, error) {
+	const op = "Cart.Service.PriceCart"
+	s.logger.Debugw("Pricing cart", "owner", owner, "op", op)
+
+	products, err := s.GetCart(ctx, owner)
+	if err != nil && !errors.Is(err, apierrors.ErrEmptyCart) {
+		s.logger.Errorw("Failed to get cart", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToGetCart
+	}
+	return s.priceProducts(ctx, owner, products)
+}
+
+// priceProducts looks up all products of the cart in one round trip. The stored lines are left
+// as they are, the price snapshot is what PriceChanged compares against. The coupon of the owner's
+// cart is applied to the current prices.
+func (s *Service) priceProducts(ctx context.Context, owner cart.Owner, products []*models.ProductData) (*cart.PricedCart, error) {
+	const op = "Cart.Service.priceProducts"
+
+	priced := &cart.PricedCart{Lines: make([]*cart.Line, 0, len(products))}
+	if err := s.priceLines(ctx, priced, products); err != nil {
+		return nil, err
+	}
+	if err := s.applyCoupon(ctx, owner, priced); err != nil {
+		s.logger.Errorw("Failed to apply coupon", "error", err, "op", op)
+		return nil, err
+	}
+	priced.GrandTotal = priced.Subtotal - priced.Discount + priced.Tax
+	return priced, nil
+}
+
+func (s *Service) priceLines(ctx context.Context, priced *cart.PricedCart, products []*models.ProductData) error {
+	const op = "Cart.Service.priceLines"
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int32, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	provided, err := s.productsProvider.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Errorw("Failed to get products", "error", err, "op", op)
+		return apierrors.ErrFailedToReadProduct
+	}
+	providedByID := make(map[int32]*protoProducts.Product, len(provided))
+	for _, product := range provided {
+		providedByID[product.Id] = product
+	}
+
+	for _, product := range products {
+		line := &cart.Line{Product: product}
+		if providedProduct, ok := providedByID[product.ID]; ok {
+			product.ProductName = providedProduct.ProductName
+			line.CurrentUnitPrice = providedProduct.Price
+			line.CurrentCurrency = providedProduct.Currency
+			line.AvailableQuantity = providedProduct.Quantity
+			line.InsufficientStock = providedProduct.Quantity < product.Quantity
+			line.PriceChanged = providedProduct.Price != product.UnitPrice || providedProduct.Currency != product.Currency
+		} else {
+			line.CurrentUnitPrice = product.UnitPrice
+			line.CurrentCurrency = product.Currency
+			line.ProductDeleted = true
+		}
+
+		priced.Lines = append(priced.Lines, line)
+		priced.Subtotal += line.LineTotal()
+		priced.Currency = product.Currency
+	}
+	return nil
+}
+
+// checkoutProducts are the lines as ordered: at the current price, not the one snapshotted when the
+// product was added. A product deleted from the catalog can't be ordered.
+func checkoutProducts(lines []*cart.Line) ([]*models.ProductData, error) {
+	products := make([]*models.ProductData, 0, len(lines))
+	for _, line := range lines {
+		if line.ProductDeleted {
+			return nil, apierrors.ErrProductNotFound
+		}
+		if len(products) > 0 && line.CurrentCurrency != products[0].Currency {
+			return nil, apierrors.ErrCurrencyMismatch
+		}
+		product := *line.Product
+		product.UnitPrice = line.CurrentUnitPrice
+		product.Currency = line.CurrentCurrency
+		products = append(products, &product)
+	}
+	return products, nil
+}
@@ -3,51 +3,97 @@ package service
 import (
 	"context"
 	"errors"
+	"strconv"
+	"time"
 
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/address"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/cart"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/outbox"
 	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/service/messaging"
 	protoProducts "github.com/sabirkekw/ecommerce_go/pkg/api/products"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-type MessageSender interface {
-	SendCheckoutMessage(ctx context.Context, userID int32, products []*models.ProductData) error
-}
-
 type ProductsProvider interface {
 	GetProductByID(ctx context.Context, productID int32) (*protoProducts.Product, error)
+	GetProductsByIDs(ctx context.Context, ids []int32) ([]*protoProducts.Product, error)
+	ReserveStock(ctx context.Context, items []*protoProducts.StockItem) (int32, error)
+	ReleaseReservation(ctx context.Context, reservationID int32) error
+	GetPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, int32, error)
+}
+
+// AddressProvider looks up the addresses of an order in the user's address book.
+type AddressProvider interface {
+	GetCheckoutAddresses(ctx context.Context, userID int32, shippingID int64, billingID int64) (shipping *address.Address, billing *address.Address, err error)
 }
 
 type Repository interface {
-	InsertIntoCart(ctx context.Context, userID int32, product *models.ProductData) error
-	DeleteFromCart(ctx context.Context, userID int32, productID int32) error
-	GetCart(ctx context.Context, userID int32) ([]*models.ProductData, error)
-	ClearCart(ctx context.Context, userID int32) error
+	InsertIntoCart(ctx context.Context, owner cart.Owner, product *models.ProductData) error
+	DeleteFromCart(ctx context.Context, owner cart.Owner, productID int32) error
+	GetCart(ctx context.Context, owner cart.Owner) ([]*models.ProductData, error)
+	ClearCart(ctx context.Context, owner cart.Owner) error
+}
+
+// Cache holds whole carts in front of storage. Writes to a cart that isn't cached are skipped, it
+// is filled from storage on the next read.
+type Cache interface {
+	Repository
+	FillCart(ctx context.Context, owner cart.Owner, products []*models.ProductData) error
+	CountGuestCart(ctx context.Context, clientIP string, window time.Duration) (int64, error)
+}
+
+// Storage is the source of truth for carts, it also owns the outbox.
+type Storage interface {
+	Repository
+	AddQuantities(ctx context.Context, owner cart.Owner, products []*models.ProductData) ([]*models.ProductData, error)
+	DecrementQuantity(ctx context.Context, owner cart.Owner, productID int32, by int32) (*models.ProductData, error)
+	CheckoutCart(ctx context.Context, userID int32, products []*models.ProductData, message *outbox.Message) error
+	CreateGuestCart(ctx context.Context, guestID string) error
+	TouchGuestCart(ctx context.Context, guestID string) error
+	MergeGuestCart(ctx context.Context, guestID string, userID int32, products []*models.ProductData) error
+	SetCoupon(ctx context.Context, userID int32, code string) error
+	GetCoupon(ctx context.Context, userID int32) (string, error)
+	ClearCoupon(ctx context.Context, userID int32) error
+	FindAbandonedCarts(ctx context.Context, idleSince time.Time, limit uint64) ([]*cart.Abandoned, error)
+	MarkCartAbandoned(ctx context.Context, userID int32, updatedAt time.Time, message *outbox.Message) (bool, error)
+	ExpireCarts(ctx context.Context, before time.Time) ([]cart.Owner, error)
 }
 
 type Service struct {
-	storage          Repository
-	cache            Repository
+	storage          Storage
+	cache            Cache
 	productsProvider ProductsProvider
-	messageSender    MessageSender
+	addressProvider  AddressProvider
+	checkoutTopic    string
+	mergePolicy      MergePolicy
+	guestLimit       GuestCartLimit
+	abandoned        AbandonedCarts
 	logger           *zap.SugaredLogger
 }
 
-func New(storage Repository, cache Repository, productsProvider ProductsProvider, messageSender MessageSender, logger *zap.SugaredLogger) *Service {
+func New(storage Storage, cache Cache, productsProvider ProductsProvider, addressProvider AddressProvider, checkoutTopic string, mergePolicy MergePolicy, guestLimit GuestCartLimit, abandoned AbandonedCarts, logger *zap.SugaredLogger) *Service {
 	return &Service{
 		storage:          storage,
 		cache:            cache,
 		productsProvider: productsProvider,
-		messageSender:    messageSender,
+		addressProvider:  addressProvider,
+		checkoutTopic:    checkoutTopic,
+		mergePolicy:      mergePolicy,
+		guestLimit:       guestLimit,
+		abandoned:        abandoned,
 		logger:           logger,
 	}
 }
 
-func (s *Service) AddToCart(ctx context.Context, userID int32, productID int32, quantity int32) error {
-	const op = "Cart.Service.AddToCart"
-	s.logger.Debugw("Adding product to cart", "product_id", productID, "op", op)
+// SetQuantity puts exactly quantity of the product into the cart, whatever was there before.
+func (s *Service) SetQuantity(ctx context.Context, owner cart.Owner, productID int32, quantity int32) error {
+	const op = "Cart.Service.SetQuantity"
+	s.logger.Debugw("Setting product quantity in cart", "product_id", productID, "quantity", quantity, "op", op)
 
 	providedProduct, err := s.productsProvider.GetProductByID(ctx, productID)
 	if errors.Is(err, status.Errorf(codes.NotFound, "product not found")) {
@@ -57,33 +103,49 @@ func (s *Service) AddToCart(ctx context.Context, userID int32, productID int32,
 		s.logger.Errorw("Failed to get product", "error", err, "op", op)
 		return apierrors.ErrFailedToReadProduct
 	}
-	if providedProduct.Quantity <= quantity {
+	if providedProduct.Quantity < quantity {
 		s.logger.Debugw("Not enought product", "op", op)
 		return apierrors.ErrNotEnoughProduct
 	}
 
+	// every line of a cart has to be in the same currency for the total to make sense
+	products, err := s.GetCart(ctx, owner)
+	if err != nil && !errors.Is(err, apierrors.ErrEmptyCart) {
+		s.logger.Errorw("Failed to get cart", "error", err, "op", op)
+		return apierrors.ErrFailedToGetCart
+	}
+	for _, item := range products {
+		if item.ID != productID && item.Currency != providedProduct.Currency {
+			s.logger.Debugw("Currency mismatch", "cart_currency", item.Currency, "product_currency", providedProduct.Currency, "op", op)
+			return apierrors.ErrCurrencyMismatch
+		}
+	}
+
 	product := &models.ProductData{
 		ID:          productID,
 		ProductName: providedProduct.ProductName,
 		Quantity:    quantity,
 		Description: providedProduct.Description,
+		UnitPrice:   providedProduct.Price,
+		Currency:    providedProduct.Currency,
 	}
-	if err := s.storage.InsertIntoCart(ctx, userID, product); err != nil {
+	if err := s.storage.InsertIntoCart(ctx, owner, product); err != nil {
 		s.logger.Errorw("Failed to save product into cart: storage", "error", err, "op", op)
 		return apierrors.ErrUnknown
 	}
-	if err := s.cache.InsertIntoCart(ctx, userID, product); err != nil {
+	if err := s.cache.InsertIntoCart(ctx, owner, product); err != nil {
 		s.logger.Errorw("Failed to save product into cart: cache", "error", err, "op", op)
 		return apierrors.ErrUnknown
 	}
-	s.logger.Debugw("Successfully added product to cart", "op", op)
+	s.logger.Debugw("Successfully set product quantity", "op", op)
 	return nil
 }
-func (s *Service) RemoveFromCart(ctx context.Context, userID int32, productID int32) error {
+
+func (s *Service) RemoveFromCart(ctx context.Context, owner cart.Owner, productID int32) error {
 	const op = "Cart.Service.RemoveFromCart"
 	s.logger.Debugw("Removing product from cart", "id", productID, "op", op)
 
-	err := s.cache.DeleteFromCart(ctx, userID, productID)
+	err := s.cache.DeleteFromCart(ctx, owner, productID)
 	if errors.Is(err, apierrors.ErrProductNotFound) {
 		s.logger.Debugw("No product in cached cart", "op", op)
 	} else if err != nil {
@@ -91,7 +153,7 @@ func (s *Service) RemoveFromCart(ctx context.Context, userID int32, productID in
 		return apierrors.ErrUnknown
 	}
 
-	err = s.storage.DeleteFromCart(ctx, userID, productID)
+	err = s.storage.DeleteFromCart(ctx, owner, productID)
 	if errors.Is(err, apierrors.ErrProductNotFound) {
 		s.logger.Debugw("No product in storage cart", "error", err, "op", op)
 		return err
@@ -102,11 +164,11 @@ func (s *Service) RemoveFromCart(ctx context.Context, userID int32, productID in
 	return nil
 }
 
-func (s *Service) GetCart(ctx context.Context, userID int32) ([]*models.ProductData, error) {
+func (s *Service) GetCart(ctx context.Context, owner cart.Owner) ([]*models.ProductData, error) {
 	const op = "Cart.Service.GetCart"
-	s.logger.Debugw("Getting all products from cart", "User ID", userID, "op", op)
+	s.logger.Debugw("Getting all products from cart", "owner", owner, "op", op)
 
-	products, err := s.cache.GetCart(ctx, userID)
+	products, err := s.cache.GetCart(ctx, owner)
 	if errors.Is(err, apierrors.ErrEmptyCart) {
 		s.logger.Debugw("Cache cart is empty", "op", op)
 	} else if err != nil {
@@ -116,60 +178,171 @@ func (s *Service) GetCart(ctx context.Context, userID int32) ([]*models.ProductD
 		return products, nil
 	}
 
-	products, err = s.storage.GetCart(ctx, userID)
+	products, err = s.storage.GetCart(ctx, owner)
 	if errors.Is(err, apierrors.ErrEmptyCart) {
 		s.logger.Errorw("Storage cart is empty", "op", op)
 		return nil, err
 	} else if err != nil {
 		s.logger.Errorw("Failed to get storage cart", "error", err, "op", op)
 		return nil, err
-	} else {
-		s.logger.Debugw("Retrieved cart from storage", "op", op)
-		return products, nil
 	}
+	s.logger.Debugw("Retrieved cart from storage", "op", op)
+
+	if err := s.cache.FillCart(ctx, owner, products); err != nil {
+		s.logger.Warnw("Failed to cache cart", "error", err, "op", op)
+	}
+	return products, nil
 }
-func (s *Service) Checkout(ctx context.Context, userID int32) error {
+
+// Checkout turns the cart into an order shipped to shippingAddressID and billed to billingAddressID,
+// 0 picks the user's default. The addresses are copied into the checkout event.
+func (s *Service) Checkout(ctx context.Context, userID int32, shippingAddressID int64, billingAddressID int64) error {
 	const op = "Cart.Service.Checkout"
 	s.logger.Debugw("Checking out cart", "User ID", userID, "op", op)
 
-	// getting cart products
-	products, err := s.GetCart(ctx, userID)
+	// the order is made of the cart as stored, the cache may be behind
+	products, err := s.storage.GetCart(ctx, cart.User(userID))
 	if err != nil {
 		s.logger.Errorw("Failed to get cart for checkout", "error", err, "op", op)
+		return apierrors.ErrFailedToGetCart
+	}
+	if len(products) == 0 {
+		s.logger.Debugw("Nothing to checkout", "op", op)
+		return apierrors.ErrEmptyCart
+	}
+
+	// resolving addresses before stock is reserved, a missing address is the user's to fix
+	shipping, billing, err := s.addressProvider.GetCheckoutAddresses(ctx, userID, shippingAddressID, billingAddressID)
+	if code := status.Code(err); code == codes.NotFound {
+		s.logger.Debugw("Checkout address not found", "op", op)
+		return apierrors.ErrAddressNotFound
+	} else if code == codes.FailedPrecondition {
+		s.logger.Debugw("User has no shipping address", "op", op)
+		return apierrors.ErrNoShippingAddress
+	} else if err != nil {
+		s.logger.Errorw("Failed to get checkout addresses", "error", err, "op", op)
+		return apierrors.ErrFailedToCheckout
+	}
+	// the order is placed at the current prices, whatever they were when the products were added
+	priced := &cart.PricedCart{}
+	if err := s.priceLines(ctx, priced, products); err != nil {
 		return err
 	}
-	// checking if products are still available and have enough quantity
+	products, err = checkoutProducts(priced.Lines)
+	if err != nil {
+		s.logger.Debugw("Cart can't be ordered as it is", "error", err, "op", op)
+		return err
+	}
+	couponCode, discount, err := s.checkoutDiscount(ctx, userID, priced)
+	if err != nil {
+		return err
+	}
+	// holding stock until order-service commits the reservation
+	items := make([]*protoProducts.StockItem, 0, len(products))
 	for _, item := range products {
-		p, err := s.productsProvider.GetProductByID(ctx, item.ID)
-
-		if err != nil {
-			return apierrors.ErrFailedToReadProduct
-		}
+		items = append(items, &protoProducts.StockItem{
+			ProductId: item.ID,
+			Quantity:  item.Quantity,
+		})
+	}
+	reservationID, err := s.productsProvider.ReserveStock(ctx, items)
+	if code := status.Code(err); code == codes.FailedPrecondition {
+		s.logger.Debugw("Not enough product to checkout", "op", op)
+		return apierrors.ErrNotEnoughProduct
+	} else if code == codes.NotFound {
+		s.logger.Debugw("Product in cart no longer exists", "op", op)
+		return apierrors.ErrProductNotFound
+	} else if err != nil {
+		s.logger.Errorw("Failed to reserve stock", "error", err, "op", op)
+		return apierrors.ErrFailedToReadProduct
+	}
 
-		if p.Quantity < item.Quantity {
-			return apierrors.ErrNotEnoughProduct
-		}
+	eventID, err := messaging.NewEventID()
+	if err != nil {
+		s.logger.Errorw("Failed to generate checkout event ID", "error", err, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return apierrors.ErrFailedToCheckout
+	}
+	payload, err := messaging.SerializeToJSON(eventID, userID, reservationID, products, shipping, billing, couponCode, discount)
+	if err != nil {
+		s.logger.Errorw("Failed to serialize checkout message", "error", err, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return apierrors.ErrFailedToCheckout
 	}
 
-	// sending checkout message
-	err = s.messageSender.SendCheckoutMessage(ctx, userID, products)
+	// the cache is dropped first: if the transaction below fails, reads just fall back to storage
+	err = s.cache.ClearCart(ctx, cart.User(userID))
 	if err != nil {
-		s.logger.Errorw("Failed to send checkout message", "error", err, "op", op)
+		s.logger.Errorw("Failed to clear cache cart before checkout", "error", err, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return apierrors.ErrFailedToCheckout
+	}
+
+	// taking the ordered products off the cart and queueing checkout message in one transaction, the
+	// outbox relay publishes it
+	err = s.storage.CheckoutCart(ctx, userID, products, &outbox.Message{
+		Topic:   s.checkoutTopic,
+		Key:     strconv.Itoa(int(userID)),
+		Payload: payload,
+	})
+	if errors.Is(err, apierrors.ErrEmptyCart) {
+		s.logger.Debugw("Cart was checked out concurrently", "op", op)
+		s.releaseReservation(ctx, reservationID)
 		return err
+	} else if errors.Is(err, apierrors.ErrCartChanged) {
+		s.logger.Debugw("Cart changed during checkout", "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return err
+	} else if err != nil {
+		s.logger.Errorw("Failed to checkout storage cart", "error", err, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return apierrors.ErrFailedToCheckout
 	}
 
-	// clearing cart, TODO: outbox pattern
-	err = s.cache.ClearCart(ctx, userID)
-	if err != nil {
-		s.logger.Errorw("Failed to clear cache cart after checkout", "error", err, "op", op)
+	s.logger.Debugw("Successfully checked out cart", "op", op)
+	return nil
+}
+
+func (s *Service) releaseReservation(ctx context.Context, reservationID int32) {
+	const op = "Cart.Service.releaseReservation"
+	if err := s.productsProvider.ReleaseReservation(context.WithoutCancel(ctx), reservationID); err != nil {
+		s.logger.Errorw("Failed to release reservation, it will expire on its own", "error", err, "reservation_id", reservationID, "op", op)
+	}
+}
+
+// ClearCart empties the cart. The cache goes first, like on checkout.
+func (s *Service) ClearCart(ctx context.Context, owner cart.Owner) error {
+	const op = "Cart.Service.ClearCart"
+	s.logger.Debugw("Clearing cart", "owner", owner, "op", op)
+
+	if err := s.cache.ClearCart(ctx, owner); err != nil {
+		s.logger.Errorw("Failed to clear cached cart", "error", err, "op", op)
 		return apierrors.ErrUnknown
 	}
-	err = s.storage.ClearCart(ctx, userID)
-	if err != nil {
-		s.logger.Errorw("Failed to clear storage cart after checkout", "error", err, "op", op)
+	if err := s.storage.ClearCart(ctx, owner); err != nil {
+		s.logger.Errorw("Failed to clear storage cart", "error", err, "op", op)
 		return apierrors.ErrUnknown
 	}
+	return nil
+}
 
-	s.logger.Debugw("Successfully checked out cart", "op", op)
+// ForgetUser drops the cart and coupon of a deleted user from storage and cache. Clearing an empty
+// cart is fine, so a redelivered event does no harm.
+func (s *Service) ForgetUser(ctx context.Context, userID int32) error {
+	const op = "Cart.Service.ForgetUser"
+	s.logger.Debugw("Forgetting user cart", "user_id", userID, "op", op)
+
+	if err := s.storage.ClearCart(ctx, cart.User(userID)); err != nil {
+		s.logger.Errorw("Failed to clear storage cart", "error", err, "op", op)
+		return err
+	}
+	if err := s.storage.ClearCoupon(ctx, userID); err != nil {
+		s.logger.Errorw("Failed to clear coupon", "error", err, "op", op)
+		return err
+	}
+	if err := s.cache.ClearCart(ctx, cart.User(userID)); err != nil {
+		s.logger.Errorw("Failed to clear cached cart", "error", err, "op", op)
+		return err
+	}
 	return nil
 }
@@ -2,10 +2,12 @@ package messaging
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
-	"os"
-	"strconv"
+	"time"
 
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/address"
 	models "github.com/sabirkekw/ecommerce_go/cart-service/internal/models/product"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 
@@ -16,12 +18,11 @@ import (
 type KafkaProducer struct {
 	logger   *zap.SugaredLogger
 	Producer *kafka.Producer
-	topic    string
 }
 
-func New(logger *zap.SugaredLogger, topic string) *KafkaProducer {
+func New(logger *zap.SugaredLogger, brokers string) *KafkaProducer {
 	producer, err := kafka.NewProducer(&kafka.ConfigMap{
-		"bootstrap.servers": os.Getenv("KAFKA_BROKERS"),
+		"bootstrap.servers": brokers,
 	})
 	if err != nil {
 		panic(err)
@@ -30,26 +31,20 @@ func New(logger *zap.SugaredLogger, topic string) *KafkaProducer {
 	return &KafkaProducer{
 		logger:   logger,
 		Producer: producer,
-		topic:    topic,
 	}
 }
 
-func (p *KafkaProducer) SendCheckoutMessage(ctx context.Context, userID int32, products []*models.ProductData) error {
-	payload, err := SerializeToJSON(userID, products)
-	if err != nil {
-		p.logger.Errorw("Failed to serialize message", "error", err)
-		return apierrors.ErrUnknown
-	}
-
+// Publish blocks until the broker acknowledges the message or ctx is done.
+func (p *KafkaProducer) Publish(ctx context.Context, topic string, key string, payload []byte) error {
 	deliveryChan := make(chan kafka.Event, 1)
 	defer close(deliveryChan)
 
-	err = p.Producer.Produce(&kafka.Message{
+	err := p.Producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{
-			Topic:     &p.topic,
+			Topic:     &topic,
 			Partition: kafka.PartitionAny,
 		},
-		Key:   []byte(strconv.Itoa(int(userID))),
+		Key:   []byte(key),
 		Value: payload,
 	}, deliveryChan)
 
@@ -77,27 +72,118 @@ func (p *KafkaProducer) Close() {
 	p.Producer.Close()
 }
 
-func SerializeToJSON(userID int32, products []*models.ProductData) ([]byte, error) {
+// NewEventID returns a random ID consumers use to drop redelivered events.
+func NewEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func SerializeToJSON(eventID string, userID int32, reservationID int32, products []*models.ProductData, shipping *address.Address, billing *address.Address, couponCode string, discount int64) ([]byte, error) {
 	type ProductIDQuantity struct {
-		ID       int32 `json:"id"`
-		Quantity int32 `json:"quantity"`
+		ID        int32  `json:"id"`
+		Quantity  int32  `json:"quantity"`
+		UnitPrice int64  `json:"unit_price"`
+		Currency  string `json:"currency"`
+	}
+	type AddressData struct {
+		RecipientName string `json:"recipient_name"`
+		Line1         string `json:"line1"`
+		Line2         string `json:"line2,omitempty"`
+		City          string `json:"city"`
+		Region        string `json:"region,omitempty"`
+		PostalCode    string `json:"postal_code,omitempty"`
+		Country       string `json:"country"`
+		Phone         string `json:"phone,omitempty"`
 	}
 	type CheckoutMessage struct {
-		UserID   int32                `json:"user_id"`
-		Products []*ProductIDQuantity `json:"products"`
+		EventID         string               `json:"event_id"`
+		UserID          int32                `json:"user_id"`
+		ReservationID   int32                `json:"reservation_id"`
+		Products        []*ProductIDQuantity `json:"products"`
+		ShippingAddress *AddressData         `json:"shipping_address"`
+		BillingAddress  *AddressData         `json:"billing_address"`
+		CouponCode      string               `json:"coupon_code,omitempty"`
+		Discount        int64                `json:"discount,omitempty"`
+	}
+	toAddressData := func(a *address.Address) *AddressData {
+		if a == nil {
+			return nil
+		}
+		return &AddressData{
+			RecipientName: a.RecipientName,
+			Line1:         a.Line1,
+			Line2:         a.Line2,
+			City:          a.City,
+			Region:        a.Region,
+			PostalCode:    a.PostalCode,
+			Country:       a.Country,
+			Phone:         a.Phone,
+		}
 	}
 
 	var productsData []*ProductIDQuantity
 	for _, p := range products {
 		productsData = append(productsData, &ProductIDQuantity{
-			ID:       p.ID,
-			Quantity: p.Quantity,
+			ID:        p.ID,
+			Quantity:  p.Quantity,
+			UnitPrice: p.UnitPrice,
+			Currency:  p.Currency,
 		})
 	}
 
 	message := CheckoutMessage{
-		UserID:   userID,
-		Products: productsData,
+		EventID:         eventID,
+		UserID:          userID,
+		ReservationID:   reservationID,
+		Products:        productsData,
+		ShippingAddress: toAddressData(shipping),
+		BillingAddress:  toAddressData(billing),
+		CouponCode:      couponCode,
+		Discount:        discount,
 	}
 	return json.Marshal(message)
 }
+
+// EventCartAbandoned is published once a user cart sits idle for long enough, a notification consumer
+// reminds the user about it.
+const EventCartAbandoned = "cart.abandoned"
+
+func SerializeCartAbandoned(eventID string, userID int32, updatedAt time.Time, products []*models.ProductData) ([]byte, error) {
+	type CartProduct struct {
+		ID          int32  `json:"id"`
+		ProductName string `json:"product_name,omitempty"`
+		Quantity    int32  `json:"quantity"`
+		UnitPrice   int64  `json:"unit_price"`
+		Currency    string `json:"currency"`
+	}
+	type CartAbandonedMessage struct {
+		EventID string `json:"event_id"`
+		Type    string `json:"type"`
+		UserID  int32  `json:"user_id"`
+		// unix seconds of the last change to the cart
+		UpdatedAt int64          `json:"updated_at"`
+		Products  []*CartProduct `json:"products"`
+	}
+
+	productsData := make([]*CartProduct, 0, len(products))
+	for _, p := range products {
+		productsData = append(productsData, &CartProduct{
+			ID:          p.ID,
+			ProductName: p.ProductName,
+			Quantity:    p.Quantity,
+			UnitPrice:   p.UnitPrice,
+			Currency:    p.Currency,
+		})
+	}
+
+	return json.Marshal(CartAbandonedMessage{
+		EventID:   eventID,
+		Type:      EventCartAbandoned,
+		UserID:    userID,
+		UpdatedAt: updatedAt.Unix(),
+		Products:  productsData,
+	})
+}
@@ -0,0 +1,77 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/cart-service/internal/models/outbox"
+	"go.uber.org/zap"
+)
+
+type OutboxStore interface {
+	FetchPendingOutbox(ctx context.Context, limit uint64) ([]*outbox.Message, error)
+	MarkOutboxSent(ctx context.Context, id int64) error
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key string, payload []byte) error
+}
+
+// OutboxRelay moves events from the outbox table to Kafka. Delivery is at-least-once:
+// a crash between Publish and MarkOutboxSent sends the event again on the next run.
+type OutboxRelay struct {
+	logger    *zap.SugaredLogger
+	store     OutboxStore
+	publisher Publisher
+	interval  time.Duration
+	batchSize uint64
+}
+
+func NewOutboxRelay(logger *zap.SugaredLogger, store OutboxStore, publisher Publisher, interval time.Duration, batchSize uint64) *OutboxRelay {
+	return &OutboxRelay{
+		logger:    logger,
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+	const op = "Cart.Messaging.OutboxRelay.Run"
+	r.logger.Infow("Outbox relay started", "interval", r.interval, "op", op)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Infow("Outbox relay stopped", "op", op)
+			return
+		case <-ticker.C:
+			r.relayBatch(ctx)
+		}
+	}
+}
+
+func (r *OutboxRelay) relayBatch(ctx context.Context) {
+	const op = "Cart.Messaging.OutboxRelay.relayBatch"
+
+	messages, err := r.store.FetchPendingOutbox(ctx, r.batchSize)
+	if err != nil {
+		r.logger.Errorw("Failed to fetch pending outbox messages", "error", err, "op", op)
+		return
+	}
+	for _, message := range messages {
+		// stop at the first failure so events keep their order
+		if err := r.publisher.Publish(ctx, message.Topic, message.Key, message.Payload); err != nil {
+			r.logger.Errorw("Failed to publish outbox message", "error", err, "id", message.ID, "op", op)
+			return
+		}
+		if err := r.store.MarkOutboxSent(ctx, message.ID); err != nil {
+			r.logger.Errorw("Failed to mark outbox message as sent", "error", err, "id", message.ID, "op", op)
+			return
+		}
+		r.logger.Debugw("Outbox message published", "id", message.ID, "topic", message.Topic, "op", op)
+	}
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE cart
+    ADD COLUMN IF NOT EXISTS unit_price BIGINT NOT NULL DEFAULT 0 CHECK (unit_price >= 0),
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- +goose Down
+ALTER TABLE cart
+    DROP COLUMN IF EXISTS unit_price,
+    DROP COLUMN IF EXISTS currency;
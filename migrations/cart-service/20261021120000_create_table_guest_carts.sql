@@ -0,0 +1,30 @@
+-- +goose Up
+-- carts of visitors that aren't signed in, found by the hash of the opaque cart token they hold
+CREATE TABLE IF NOT EXISTS guest_carts (
+    id          CHAR(64)  PRIMARY KEY,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS guest_cart_items (
+    guest_cart_id CHAR(64)     NOT NULL REFERENCES guest_carts(id) ON DELETE CASCADE,
+    product_id    INT          NOT NULL,
+    quantity      INT          NOT NULL CHECK (quantity > 0),
+    description   VARCHAR(255),
+    unit_price    BIGINT       NOT NULL DEFAULT 0 CHECK (unit_price >= 0),
+    currency      CHAR(3)      NOT NULL DEFAULT 'USD',
+    updated_at    TIMESTAMP    NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (guest_cart_id, product_id)
+);
+
+-- the upsert of cart lines already sets it
+ALTER TABLE cart
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- +goose Down
+ALTER TABLE cart
+    DROP COLUMN IF EXISTS updated_at;
+
+DROP TABLE IF EXISTS guest_cart_items;
+DROP TABLE IF EXISTS guest_carts;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- one row per non-empty user cart, updated_at moves on every change to its lines. Guest carts
+-- already have guest_carts.updated_at.
+CREATE TABLE IF NOT EXISTS carts (
+    user_id     INTEGER   PRIMARY KEY,
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- set once the cart-abandoned event of the current idle period is queued
+    reminded_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS carts_updated_at_idx ON carts (updated_at);
+CREATE INDEX IF NOT EXISTS guest_carts_updated_at_idx ON guest_carts (updated_at);
+
+INSERT INTO carts (user_id, updated_at)
+SELECT user_id, MAX(updated_at) FROM cart GROUP BY user_id
+ON CONFLICT (user_id) DO NOTHING;
+
+-- +goose Down
+DROP INDEX IF EXISTS guest_carts_updated_at_idx;
+DROP TABLE IF EXISTS carts;
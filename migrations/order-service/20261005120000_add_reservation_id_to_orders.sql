@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS reservation_id INTEGER;
+
+-- +goose Down
+ALTER TABLE orders DROP COLUMN IF EXISTS reservation_id;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS processed_events(
+    event_id VARCHAR(64) PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS processed_events;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dead_letters(
+    id SERIAL PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL DEFAULT '',
+    message_key VARCHAR(255) NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    replayed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS dead_letters_pending_idx ON dead_letters (id) WHERE replayed_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS dead_letters;
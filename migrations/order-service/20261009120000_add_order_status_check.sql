@@ -0,0 +1,14 @@
+-- +goose Up
+UPDATE orders SET status = 'pending' WHERE status = 'created';
+
+ALTER TABLE orders
+    ALTER COLUMN status SET DEFAULT 'pending',
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('pending', 'paid', 'fulfilled', 'shipped', 'delivered', 'cancelled', 'refunded'));
+
+-- +goose Down
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_status_check,
+    ALTER COLUMN status DROP DEFAULT;
+
+UPDATE orders SET status = 'created' WHERE status = 'pending';
@@ -0,0 +1,9 @@
+-- +goose Up
+-- copies of the address book entries chosen at checkout, later edits don't touch placed orders.
+-- NULL for orders placed before addresses existed.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_address JSONB;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS billing_address JSONB;
+
+-- +goose Down
+ALTER TABLE orders DROP COLUMN IF EXISTS billing_address;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_address;
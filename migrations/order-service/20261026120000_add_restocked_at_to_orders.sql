@@ -0,0 +1,13 @@
+-- +goose Up
+-- cancelled and refunded orders get their stock back in products-service, restocked_at records that it
+-- went through. Orders cancelled before are left alone.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS restocked_at TIMESTAMP;
+
+UPDATE orders SET restocked_at = NOW() WHERE status IN ('cancelled', 'refunded');
+
+CREATE INDEX IF NOT EXISTS orders_unrestocked_idx ON orders (id)
+    WHERE restocked_at IS NULL AND status IN ('cancelled', 'refunded');
+
+-- +goose Down
+DROP INDEX IF EXISTS orders_unrestocked_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS restocked_at;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- +goose Down
+ALTER TABLE products
+    DROP COLUMN IF EXISTS price,
+    DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS promotions(
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    kind VARCHAR(20) NOT NULL,
+    percent_off INTEGER NOT NULL DEFAULT 0,
+    amount_off BIGINT NOT NULL DEFAULT 0,
+    currency CHAR(3),
+    product_id INTEGER REFERENCES products(id),
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    min_subtotal BIGINT NOT NULL DEFAULT 0,
+    max_redemptions_per_user INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions(
+    id BIGSERIAL PRIMARY KEY,
+    promotion_id BIGINT NOT NULL REFERENCES promotions(id),
+    user_id INTEGER NOT NULL,
+    -- checkout event the order was created from, makes redelivered events redeem once
+    event_id VARCHAR(64) NOT NULL UNIQUE,
+    discount BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS promotion_redemptions_promotion_user_idx ON promotion_redemptions (promotion_id, user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
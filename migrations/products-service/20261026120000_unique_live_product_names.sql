@@ -0,0 +1,8 @@
+-- +goose Up
+-- names of deleted products can be reused
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_product_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS products_product_name_idx ON products (product_name) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS products_product_name_idx;
+ALTER TABLE products ADD CONSTRAINT products_product_name_key UNIQUE (product_name);
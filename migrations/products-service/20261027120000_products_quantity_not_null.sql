@@ -0,0 +1,9 @@
+-- +goose Up
+-- listings sort and page by (quantity, id), a NULL would fall out of the keyset
+UPDATE products SET quantity = 0 WHERE quantity IS NULL;
+ALTER TABLE products ALTER COLUMN quantity SET DEFAULT 0;
+ALTER TABLE products ALTER COLUMN quantity SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE products ALTER COLUMN quantity DROP NOT NULL;
+ALTER TABLE products ALTER COLUMN quantity DROP DEFAULT;
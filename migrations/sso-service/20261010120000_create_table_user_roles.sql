@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_roles(
+    user_id INTEGER NOT NULL,
+    role VARCHAR(32) NOT NULL CHECK (role IN ('customer', 'admin', 'support')),
+    PRIMARY KEY (user_id, role),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- every existing account is a shopper, staff roles are granted by hand
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'customer' FROM users
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_roles;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- the newest key that isn't retired signs tokens, retired keys stay published until tokens signed by them expire
+CREATE TABLE IF NOT EXISTS signing_keys(
+    kid VARCHAR(64) PRIMARY KEY,
+    private_key BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS signing_keys;
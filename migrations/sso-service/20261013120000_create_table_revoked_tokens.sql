@@ -0,0 +1,20 @@
+-- +goose Up
+-- access tokens revoked before their exp, services poll this by id
+CREATE TABLE IF NOT EXISTS revoked_tokens(
+    id BIGSERIAL PRIMARY KEY,
+    jti VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- the access token last issued for each refresh token, revoked together with the session
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS access_jti VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS access_jti,
+    DROP COLUMN IF EXISTS access_expires_at;
+
+DROP TABLE IF EXISTS revoked_tokens;
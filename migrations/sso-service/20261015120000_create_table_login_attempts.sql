@@ -0,0 +1,16 @@
+-- +goose Up
+-- audit log of every login attempt, also the source of truth for throttling
+CREATE TABLE IF NOT EXISTS login_attempts(
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS login_attempts_email_created_at_idx ON login_attempts (email, created_at);
+CREATE INDEX IF NOT EXISTS login_attempts_client_ip_created_at_idx ON login_attempts (client_ip, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- events waiting to be published to kafka, written in the same transaction as the change they describe
+CREATE TABLE IF NOT EXISTS outbox (
+    id          BIGSERIAL    PRIMARY KEY,
+    topic       VARCHAR(255) NOT NULL,
+    message_key VARCHAR(255) NOT NULL,
+    payload     BYTEA        NOT NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    sent_at     TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
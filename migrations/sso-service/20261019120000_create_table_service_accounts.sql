@@ -0,0 +1,12 @@
+-- +goose Up
+-- internal callers that get access tokens through the client credentials grant
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id          BIGSERIAL    PRIMARY KEY,
+    client_id   VARCHAR(255) NOT NULL UNIQUE,
+    secret_hash VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    disabled_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS service_accounts;
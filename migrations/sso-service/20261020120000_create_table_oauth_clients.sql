@@ -0,0 +1,34 @@
+-- +goose Up
+-- applications that log users in through the OpenID Connect endpoints
+CREATE TABLE IF NOT EXISTS oauth_clients(
+    id BIGSERIAL PRIMARY KEY,
+    client_id VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    -- exact matches only, no wildcards
+    redirect_uris TEXT[] NOT NULL,
+    -- NULL for public clients like browser apps, they are bound by PKCE alone
+    secret_hash VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    disabled_at TIMESTAMP
+);
+
+-- issued by the authorization endpoint, exchanged once for tokens at the token endpoint
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes(
+    id BIGSERIAL PRIMARY KEY,
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    client_id VARCHAR(255) NOT NULL REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    nonce VARCHAR(255) NOT NULL DEFAULT '',
+    -- S256 of the PKCE code verifier
+    code_challenge VARCHAR(128) NOT NULL,
+    second_factor BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- the OAuth client a login was made through, its refresh tokens only work for that client
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS client_id VARCHAR(255) REFERENCES oauth_clients(client_id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE sessions DROP COLUMN IF EXISTS client_id;
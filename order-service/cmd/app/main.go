@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -13,7 +14,10 @@ import (
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/messaging"
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/repository"
 	orderservice "github.com/sabirkekw/ecommerce_go/order-service/internal/services/order"
+	"github.com/sabirkekw/ecommerce_go/pkg/clientcreds"
+	"github.com/sabirkekw/ecommerce_go/pkg/jwks"
 	"github.com/sabirkekw/ecommerce_go/pkg/logger"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 )
 
 func main() {
@@ -33,16 +37,47 @@ func main() {
 
 	orderRepo := repository.New(db, logger.Log)
 
-	productsClient := productsclient.New(logger.Log, 50052)
+	serviceCreds, err := clientcreds.New(logger.Log, config.Revocation.SSOAddr, config.ServiceAccount.ClientID, config.ServiceAccount.ClientSecret)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create service credentials", "error", err)
+	}
+
+	productsClient := productsclient.New(logger.Log, 50052, serviceCreds)
+
+	kafkaProducer := messaging.NewKafkaProducer(logger.Log, config.Kafka.Brokers)
+	defer kafkaProducer.Close()
+
+	orderService := orderservice.NewService(orderRepo, productsClient, kafkaProducer, config.Kafka.Topic, config.Kafka.DeadLetterTopic, logger.Log)
 
-	orderService := orderservice.NewService(orderRepo, productsClient, logger.Log)
+	retryPolicy := messaging.RetryPolicy{
+		MaxAttempts:    config.Kafka.Retry.MaxAttempts,
+		InitialBackoff: config.Kafka.Retry.InitialBackoff,
+		MaxBackoff:     config.Kafka.Retry.MaxBackoff,
+	}
+	restockCtx, stopRestock := context.WithCancel(context.Background())
+	defer stopRestock()
+	go orderService.RestockOrders(restockCtx, config.Restock.Interval)
+	logger.Log.Infow("Started restocking cancelled orders", "interval", config.Restock.Interval)
 
-	kafkaConsumer := messaging.NewKafkaConsumer(logger.Log, config.Kafka.Brokers, config.Kafka.GroupID, config.Kafka.Topic, orderService)
+	kafkaConsumer := messaging.NewKafkaConsumer(logger.Log, config.Kafka.Brokers, config.Kafka.GroupID, config.Kafka.Topic, retryPolicy, orderService)
 	go kafkaConsumer.Poll()
 	logger.Log.Infow("Started Kafka consumer to listen for checkout messages")
 	defer kafkaConsumer.Close()
 
-	application := app.New(logger.Log, config.GRPC.Port, config.HTTP.Port, db, orderService, config.JWTSecret, config.GRPC.Timeout)
+	userEventsConsumer := messaging.NewUserEventsConsumer(logger.Log, config.Kafka.Brokers, config.Kafka.UserEventsGroupID, config.Kafka.UserEventsTopic, retryPolicy, orderService, kafkaProducer, config.Kafka.UserEventsDeadLetterTopic)
+	go userEventsConsumer.Poll()
+	logger.Log.Infow("Started Kafka consumer to listen for user events")
+	defer userEventsConsumer.Close()
+
+	revocations, err := revocation.New(logger.Log, config.Revocation.SSOAddr, serviceCreds)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create revocation list", "error", err)
+	}
+	revocationCtx, stopRevocation := context.WithCancel(context.Background())
+	defer stopRevocation()
+	go revocations.Run(revocationCtx, config.Revocation.PollInterval)
+
+	application := app.New(logger.Log, config.GRPC.Port, config.HTTP.Port, db, orderService, jwks.NewCache(config.JWKS.URL, config.JWKS.CacheTTL).Keyfunc, revocations, config.GRPC.Timeout)
 
 	go application.HTTPServer.Run()
 	go application.GRPCServer.Run()
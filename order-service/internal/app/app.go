@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	grpcapp "github.com/sabirkekw/ecommerce_go/order-service/internal/app/grpc"
 	httpapp "github.com/sabirkekw/ecommerce_go/order-service/internal/app/http"
 	grpcserver "github.com/sabirkekw/ecommerce_go/order-service/internal/grpc/server"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"go.uber.org/zap"
 )
 
@@ -16,8 +18,8 @@ type App struct {
 	Storage    *sql.DB
 }
 
-func New(log *zap.SugaredLogger, grpcport int, httpport int, storage *sql.DB, service grpcserver.OrderService, jwtSecret string, timeout time.Duration) *App {
-	GRPCServer := grpcapp.NewGRPCServer(log, grpcport, service, timeout, jwtSecret)
+func New(log *zap.SugaredLogger, grpcport int, httpport int, storage *sql.DB, service grpcserver.OrderService, keyfunc jwt.Keyfunc, revocations *revocation.List, timeout time.Duration) *App {
+	GRPCServer := grpcapp.NewGRPCServer(log, grpcport, service, timeout, keyfunc, revocations)
 	HTTPServer := httpapp.New(log, httpport, grpcport)
 
 	return &App{
@@ -6,24 +6,26 @@ import (
 	"net"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/grpc/interceptor"
 	grpcserver "github.com/sabirkekw/ecommerce_go/order-service/internal/grpc/server"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
 type GRPCApp struct {
-	Logger    *zap.SugaredLogger
-	Server    *grpc.Server
-	port      int
-	timeout   time.Duration
-	JWTSecret string
+	Logger  *zap.SugaredLogger
+	Server  *grpc.Server
+	port    int
+	timeout time.Duration
 }
 
-func NewGRPCServer(log *zap.SugaredLogger, port int, service grpcserver.OrderService, timeout time.Duration, jwtSecret string) *GRPCApp {
+func NewGRPCServer(log *zap.SugaredLogger, port int, service grpcserver.OrderService, timeout time.Duration, keyfunc jwt.Keyfunc, revocations *revocation.List) *GRPCApp {
 	logInterceptor := func(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		ctx = context.WithValue(ctx, "logger", log)
-		ctx = context.WithValue(ctx, "jwtSecret", jwtSecret)
+		ctx = context.WithValue(ctx, "keyfunc", keyfunc)
+		ctx = context.WithValue(ctx, "revocations", revocations)
 		ctx = context.WithValue(ctx, "timeout", timeout)
 		return interceptor.LogInterceptor(ctx, req, serverInfo, handler)
 	}
@@ -33,16 +35,16 @@ func NewGRPCServer(log *zap.SugaredLogger, port int, service grpcserver.OrderSer
 		interceptor.TimeoutInterceptor,
 		interceptor.AuthInterceptor,
 		interceptor.UserIDExtractorInterceptor,
+		interceptor.AuthorizationInterceptor,
 	),
 	)
 
 	grpcserver.Register(grpcServer, grpcserver.New(service, log))
 
 	return &GRPCApp{
-		Logger:    log,
-		Server:    grpcServer,
-		port:      port,
-		JWTSecret: jwtSecret,
+		Logger: log,
+		Server: grpcServer,
+		port:   port,
 	}
 }
 
@@ -19,7 +19,23 @@ type Config struct {
 		Brokers string `yaml:"brokers" env:"KAFKA_BROKERS" env-default:"localhost:9092"`
 		GroupID string `yaml:"group_id" env:"KAFKA_GROUP_ID" env-default:"order-service-group"`
 		Topic   string `yaml:"topic" env:"KAFKA_TOPIC" env-default:"checkout-topic"`
+		// checkout messages that still fail after all retries end up here
+		DeadLetterTopic string `yaml:"dead_letter_topic" env:"KAFKA_DEAD_LETTER_TOPIC" env-default:"checkout-dead-letter-topic"`
+		// account deletions from sso-service, orders of a deleted user are anonymized
+		UserEventsTopic   string `yaml:"user_events_topic" env:"KAFKA_USER_EVENTS_TOPIC" env-default:"user-events"`
+		UserEventsGroupID string `yaml:"user_events_group_id" env-default:"order-service-user-events"`
+		Retry             struct {
+			MaxAttempts    int           `yaml:"max_attempts" env-default:"5"`
+			InitialBackoff time.Duration `yaml:"initial_backoff" env-default:"500ms"`
+			MaxBackoff     time.Duration `yaml:"max_backoff" env-default:"30s"`
+		} `yaml:"retry"`
+		// user events that still fail after all retries end up here
+		UserEventsDeadLetterTopic string `yaml:"user_events_dead_letter_topic" env-default:"order-service-user-events-dead-letter"`
 	} `yaml:"kafka"`
+	Restock struct {
+		// how often stock of cancelled and refunded orders that didn't go back right away is retried
+		Interval time.Duration `yaml:"interval" env-default:"1m"`
+	} `yaml:"restock"`
 	GRPC struct {
 		Port    int           `yaml:"port"`
 		Timeout time.Duration `yaml:"timeout"`
@@ -28,7 +44,19 @@ type Config struct {
 		Port    int           `yaml:"port"`
 		Timeout time.Duration `yaml:"timeout"`
 	} `yaml:"http"`
-	JWTSecret string `yaml:"jwt_secret"`
+	JWKS struct {
+		URL      string        `yaml:"url" env:"JWKS_URL" env-default:"http://sso-service:8081/.well-known/jwks.json"`
+		CacheTTL time.Duration `yaml:"cache_ttl" env-default:"10m"`
+	} `yaml:"jwks"`
+	Revocation struct {
+		SSOAddr      string        `yaml:"sso_addr" env:"REVOCATION_SSO_ADDR" env-default:"sso-service:50051"`
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"5s"`
+	} `yaml:"revocation"`
+	ServiceAccount struct {
+		// identifies this service to the others, registered in sso-service
+		ClientID     string `yaml:"client_id" env:"SERVICE_CLIENT_ID" env-default:"order-service"`
+		ClientSecret string `yaml:"client_secret" env:"SERVICE_CLIENT_SECRET"`
+	} `yaml:"service_account"`
 }
 
 // done: implement config loading and validation
@@ -3,12 +3,12 @@ package interceptor
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/auth"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -33,19 +33,21 @@ func LogInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerIn
 func UserIDExtractorInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	logger := mustLogger(ctx)
 
-	token, err := tokenFromContext(ctx)
-	if err != nil {
-		logger.Debugw("failed to recieve token from context")
+	claims, ok := ctx.Value("claims").(jwt.MapClaims)
+	if !ok {
+		logger.Debugw("failed to recieve token claims from context")
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
 
-	userID, err := getUserIDFromToken(token, mustJWTSecret(ctx))
-	if err != nil {
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
 		logger.Debugw("No UserID in token", "method", serverInfo.FullMethod)
 		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
 
-	ctx = context.WithValue(ctx, "user_id", userID)
+	ctx = context.WithValue(ctx, "user_id", int32(userID))
+	ctx = context.WithValue(ctx, "roles", auth.RolesFromClaims(claims))
+	ctx = context.WithValue(ctx, "second_factor", auth.HasSecondFactor(claims))
 
 	resp, err := handler(ctx, req)
 	if err != nil {
@@ -63,21 +65,20 @@ func AuthInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerI
 		return nil, status.Errorf(codes.Unauthenticated, "no token")
 	}
 
-	isValid, err := validateToken(token, mustJWTSecret(ctx))
-	if err != nil {
-		if errors.Is(err, apierrors.ErrTokenExpired) {
-			return nil, status.Errorf(codes.Unauthenticated, "token expired")
-		}
+	claims, err := parseClaims(normalizeToken(token), mustKeyfunc(ctx))
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, status.Errorf(codes.Unauthenticated, "token expired")
+	} else if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
-	if !isValid {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	if jti, _ := claims["jti"].(string); mustRevocations(ctx).IsRevoked(jti) {
+		return nil, status.Errorf(codes.Unauthenticated, "token revoked")
 	}
 
 	logger.Debugw("Token valid", "method", serverInfo.FullMethod)
 
-	// keep token around for downstream interceptors
-	ctx = context.WithValue(ctx, "token", token)
+	// the token is parsed once, downstream interceptors read its claims
+	ctx = context.WithValue(ctx, "claims", claims)
 
 	resp, err := handler(ctx, req)
 	if err != nil {
@@ -87,6 +88,24 @@ func AuthInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerI
 	return resp, nil
 }
 
+// AuthorizationInterceptor checks the caller's roles against the shared per-RPC policy.
+func AuthorizationInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	logger := mustLogger(ctx)
+
+	rule := auth.DefaultPolicy.Rule(serverInfo.FullMethod)
+	roles, _ := ctx.Value("roles").([]string)
+	if !rule.Allows(roles) {
+		logger.Debugw("Permission denied", "method", serverInfo.FullMethod, "roles", roles)
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if secondFactor, _ := ctx.Value("second_factor").(bool); rule.SecondFactor && !secondFactor {
+		logger.Debugw("Second factor required", "method", serverInfo.FullMethod)
+		return nil, status.Errorf(codes.PermissionDenied, "second factor required")
+	}
+
+	return handler(ctx, req)
+}
+
 func TimeoutInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	timeout, ok := ctx.Value("timeout").(time.Duration)
 	if !ok {
@@ -124,19 +143,23 @@ func mustLogger(ctx context.Context) *zap.SugaredLogger {
 	return logger
 }
 
-func mustJWTSecret(ctx context.Context) string {
-	secret, ok := ctx.Value("jwtSecret").(string)
+func mustKeyfunc(ctx context.Context) jwt.Keyfunc {
+	keyfunc, ok := ctx.Value("keyfunc").(jwt.Keyfunc)
 	if !ok {
-		panic("failed to recieve jwt secret from context")
+		panic("failed to recieve jwt keyfunc from context")
 	}
-	return secret
+	return keyfunc
 }
 
-func tokenFromContext(ctx context.Context) ([]string, error) {
-	if token, ok := ctx.Value("token").([]string); ok && len(token) > 0 {
-		return token, nil
+func mustRevocations(ctx context.Context) *revocation.List {
+	revocations, ok := ctx.Value("revocations").(*revocation.List)
+	if !ok {
+		panic("failed to recieve revocation list from context")
 	}
+	return revocations
+}
 
+func tokenFromContext(ctx context.Context) ([]string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, errors.New("missing metadata")
@@ -150,52 +173,20 @@ func tokenFromContext(ctx context.Context) ([]string, error) {
 	return token, nil
 }
 
-func validateToken(tokenSliced []string, jwtSecret string) (bool, error) {
-	token := normalizeToken(tokenSliced)
-	parsedToken, err := parseToken(token, jwtSecret)
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return false, apierrors.ErrTokenExpired
-		}
-		return false, apierrors.ErrInvalidToken
-	}
-
-	if !parsedToken.Valid {
-		return false, apierrors.ErrInvalidToken
-	}
-	return true, nil
+func normalizeToken(tokenSliced []string) string {
+	token := strings.Join(tokenSliced, "")
+	return strings.TrimPrefix(token, "Bearer ")
 }
 
-func getUserIDFromToken(tokenSliced []string, jwtSecret string) (int64, error) {
-	token := normalizeToken(tokenSliced)
-	parsedToken, err := parseToken(token, jwtSecret)
+func parseClaims(token string, keyfunc jwt.Keyfunc) (jwt.MapClaims, error) {
+	parsedToken, err := jwt.Parse(token, keyfunc)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	claims, ok := parsedToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, fmt.Errorf("couldnt find user id in token")
+	if !ok || !parsedToken.Valid {
+		return nil, errors.New("invalid token claims")
 	}
-
-	userID, ok := claims["user_id"].(float64)
-	if !ok {
-		return 0, fmt.Errorf("couldnt find user id in token")
-	}
-
-	return int64(userID), nil
-}
-
-func normalizeToken(tokenSliced []string) string {
-	token := strings.Join(tokenSliced, "")
-	return strings.TrimPrefix(token, "Bearer ")
-}
-
-func parseToken(token, jwtSecret string) (*jwt.Token, error) {
-	return jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(jwtSecret), nil
-	})
+	return claims, nil
 }
@@ -7,6 +7,7 @@ import (
 	productsProto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -15,8 +16,9 @@ type ProductsClient struct {
 	Client productsProto.ProductsServiceClient
 }
 
-func New(logger *zap.SugaredLogger, port int) *ProductsClient {
-	conn, err := grpc.NewClient(fmt.Sprintf("products-service:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+// New dials products-service. creds identify this service, stock reservations are refused without them.
+func New(logger *zap.SugaredLogger, port int, creds credentials.PerRPCCredentials) *ProductsClient {
+	conn, err := grpc.NewClient(fmt.Sprintf("products-service:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithPerRPCCredentials(creds))
 	if err != nil {
 		logger.Errorw("failed to start gRPC products client", "error", err)
 		return nil
@@ -44,3 +46,62 @@ func (c *ProductsClient) GetProductByID(ctx context.Context, id int32) (*product
 
 	return resp.Product, nil
 }
+
+func (c *ProductsClient) ReserveStock(ctx context.Context, items []*productsProto.StockItem) (int32, error) {
+	const op = "Order.ProductsClient.ReserveStock"
+	c.Logger.Debugw("reserving stock in Products-service", "items", len(items), "op", op)
+
+	resp, err := c.Client.ReserveStock(ctx, &productsProto.ReserveStockRequest{
+		Items: items,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.ReservationId, nil
+}
+
+func (c *ProductsClient) CommitReservation(ctx context.Context, reservationID int32) error {
+	const op = "Order.ProductsClient.CommitReservation"
+	c.Logger.Debugw("committing reservation in Products-service", "reservation_id", reservationID, "op", op)
+
+	_, err := c.Client.CommitReservation(ctx, &productsProto.CommitReservationRequest{
+		ReservationId: reservationID,
+	})
+	return err
+}
+
+func (c *ProductsClient) ReleaseReservation(ctx context.Context, reservationID int32) error {
+	const op = "Order.ProductsClient.ReleaseReservation"
+	c.Logger.Debugw("releasing reservation in Products-service", "reservation_id", reservationID, "op", op)
+
+	_, err := c.Client.ReleaseReservation(ctx, &productsProto.ReleaseReservationRequest{
+		ReservationId: reservationID,
+	})
+	return err
+}
+
+// RestockReservation gives the stock of a cancelled or refunded order back, once per reservation.
+func (c *ProductsClient) RestockReservation(ctx context.Context, reservationID int32) error {
+	const op = "Order.ProductsClient.RestockReservation"
+	c.Logger.Debugw("restocking reservation in Products-service", "reservation_id", reservationID, "op", op)
+
+	_, err := c.Client.RestockReservation(ctx, &productsProto.RestockReservationRequest{
+		ReservationId: reservationID,
+	})
+	return err
+}
+
+// RedeemPromotion counts the order of eventID against the user's coupon limit, once per event.
+func (c *ProductsClient) RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error {
+	const op = "Order.ProductsClient.RedeemPromotion"
+	c.Logger.Debugw("redeeming promotion in Products-service", "user_id", userID, "event_id", eventID, "op", op)
+
+	_, err := c.Client.RedeemPromotion(ctx, &productsProto.RedeemPromotionRequest{
+		Code:     code,
+		UserId:   userID,
+		EventId:  eventID,
+		Discount: discount,
+	})
+	return err
+}
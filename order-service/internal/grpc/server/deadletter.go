@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/order"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListDeadLetters(ctx context.Context, req *proto.ListDeadLettersRequest) (*proto.ListDeadLettersResponse, error) {
+	letters, err := s.Service.ListDeadLetters(ctx, &deadletter.ListFilter{
+		Limit:           uint64(req.Limit),
+		IncludeReplayed: req.IncludeReplayed,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	response := make([]*proto.DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		protoLetter := &proto.DeadLetter{
+			Id:        letter.ID,
+			EventId:   letter.EventID,
+			Payload:   letter.Payload,
+			Error:     letter.Error,
+			Attempts:  letter.Attempts,
+			CreatedAt: letter.CreatedAt.Unix(),
+		}
+		if letter.ReplayedAt != nil {
+			protoLetter.ReplayedAt = letter.ReplayedAt.Unix()
+		}
+		response = append(response, protoLetter)
+	}
+	return &proto.ListDeadLettersResponse{DeadLetters: response}, nil
+}
+
+func (s *Server) ReplayDeadLetter(ctx context.Context, req *proto.ReplayDeadLetterRequest) (*proto.ReplayDeadLetterResponse, error) {
+	if req.Id <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+	}
+
+	err := s.Service.ReplayDeadLetter(ctx, req.Id)
+	if errors.Is(err, apierrors.ErrDeadLetterNotFound) {
+		return nil, status.Errorf(codes.NotFound, "dead letter not found")
+	} else if errors.Is(err, apierrors.ErrDeadLetterReplayed) {
+		return nil, status.Errorf(codes.FailedPrecondition, "dead letter already replayed")
+	} else if errors.Is(err, apierrors.ErrDeadLetterNotReplayable) {
+		return nil, status.Errorf(codes.FailedPrecondition, "dead letter isn't a checkout message")
+	} else if errors.Is(err, apierrors.ErrDeadLetterOrderExists) {
+		return nil, status.Errorf(codes.FailedPrecondition, "checkout already has an order, cancel it before replaying")
+	} else if errors.Is(err, apierrors.ErrNotEnoughProduct) {
+		return nil, status.Errorf(codes.FailedPrecondition, "not enough stock to replay the checkout")
+	} else if errors.Is(err, apierrors.ErrProductNotFound) {
+		return nil, status.Errorf(codes.FailedPrecondition, "a product of the checkout no longer exists")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+	return &proto.ReplayDeadLetterResponse{}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
 	proto "github.com/sabirkekw/ecommerce_go/pkg/api/order"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
@@ -12,11 +13,25 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
+
 type OrderService interface {
 	CreateOrder(ctx context.Context, order *order.Order) (int32, error)
-	GetOrderByID(ctx context.Context, orderID int32) (*order.Order, error)
+	GetOrderByID(ctx context.Context, requester *order.Requester, orderID int32) (*order.Order, error)
 	GetOrderByUserID(ctx context.Context, userID int32) ([]*order.Order, error)
-	DeleteOrder(ctx context.Context, orderID int32) error
+	DeleteOrder(ctx context.Context, requester *order.Requester, orderID int32) error
+	UpdateOrderStatus(ctx context.Context, orderID int32, status string) (*order.Order, error)
+	ListDeadLetters(ctx context.Context, filter *deadletter.ListFilter) ([]*deadletter.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id int32) error
+}
+
+var orderStatuses = map[string]proto.OrderStatus{
+	order.StatusPending:   proto.OrderStatus_ORDER_STATUS_PENDING,
+	order.StatusPaid:      proto.OrderStatus_ORDER_STATUS_PAID,
+	order.StatusFulfilled: proto.OrderStatus_ORDER_STATUS_FULFILLED,
+	order.StatusShipped:   proto.OrderStatus_ORDER_STATUS_SHIPPED,
+	order.StatusDelivered: proto.OrderStatus_ORDER_STATUS_DELIVERED,
+	order.StatusCancelled: proto.OrderStatus_ORDER_STATUS_CANCELLED,
+	order.StatusRefunded:  proto.OrderStatus_ORDER_STATUS_REFUNDED,
 }
 
 type Server struct {
@@ -36,32 +51,24 @@ func Register(grpc *grpc.Server, server *Server) {
 	proto.RegisterOrderServiceServer(grpc, server)
 }
 
-func (s* Server) GetOrderByID(ctx context.Context, req *proto.GetOrderByIDRequest) (*proto.GetOrderByIDResponse, error) {
+func (s *Server) GetOrderByID(ctx context.Context, req *proto.GetOrderByIDRequest) (*proto.GetOrderByIDResponse, error) {
 	orderID := req.OrderId
 	if orderID <= 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
 	}
 
-	orderData, err := s.Service.GetOrderByID(ctx, orderID)
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderData, err := s.Service.GetOrderByID(ctx, requester, orderID)
 	if errors.Is(err, apierrors.ErrOrderNotFound) {
 		return nil, status.Errorf(codes.NotFound, "order not found")
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
-	return &proto.GetOrderByIDResponse{Order: &proto.SingleOrder{
-		Id: orderData.ID,
-		UserId: orderData.UserID,
-		Products: func() []*proto.ProductData {
-			var products []*proto.ProductData
-			for _, p := range orderData.Products {
-				products = append(products, &proto.ProductData{
-					ProductId: p.ID,
-					Quantity: p.Quantity,
-				})
-			}
-			return products
-		}(),
-	}}, nil
+	return &proto.GetOrderByIDResponse{Order: toProtoOrder(orderData)}, nil
 }
 
 func (s *Server) GetOrdersByUserID(ctx context.Context, req *proto.GetOrdersByUserIDRequest) (*proto.GetOrdersByUserIDResponse, error) {
@@ -78,20 +85,7 @@ func (s *Server) GetOrdersByUserID(ctx context.Context, req *proto.GetOrdersByUs
 
 	var protoOrders []*proto.SingleOrder
 	for _, order := range orders {
-		protoOrders = append(protoOrders, &proto.SingleOrder{
-			Id: order.ID,
-			UserId: order.UserID,
-			Products: func() []*proto.ProductData {
-				var products []*proto.ProductData
-				for _, p := range order.Products {
-					products = append(products, &proto.ProductData{
-						ProductId: p.ID,
-						Quantity: p.Quantity,
-					})
-				}
-				return products
-			}(),
-		})
+		protoOrders = append(protoOrders, toProtoOrder(order))
 	}
 	return &proto.GetOrdersByUserIDResponse{Orders: protoOrders}, nil
 }
@@ -101,12 +95,104 @@ func (s *Server) DeleteOrder(ctx context.Context, req *proto.DeleteOrderRequest)
 	if orderID <= 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
 	}
-	
-	err := s.Service.DeleteOrder(ctx, orderID)
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Service.DeleteOrder(ctx, requester, orderID)
 	if errors.Is(err, apierrors.ErrOrderNotFound) {
 		return nil, status.Errorf(codes.NotFound, "order not found")
+	} else if errors.Is(err, apierrors.ErrInvalidStatusTransition) {
+		return nil, status.Errorf(codes.FailedPrecondition, "order can't be cancelled anymore")
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
 	return &proto.DeleteOrderResponse{}, nil
-}
\ No newline at end of file
+}
+
+func (s *Server) UpdateOrderStatus(ctx context.Context, req *proto.UpdateOrderStatusRequest) (*proto.UpdateOrderStatusResponse, error) {
+	orderID := req.OrderId
+	if orderID <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+	}
+	newStatus, ok := statusFromProto(req.Status)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown order status")
+	}
+
+	orderData, err := s.Service.UpdateOrderStatus(ctx, orderID, newStatus)
+	if errors.Is(err, apierrors.ErrOrderNotFound) {
+		return nil, status.Errorf(codes.NotFound, "order not found")
+	} else if errors.Is(err, apierrors.ErrInvalidOrderStatus) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown order status")
+	} else if errors.Is(err, apierrors.ErrInvalidStatusTransition) {
+		return nil, status.Errorf(codes.FailedPrecondition, "order can't move to status %s", newStatus)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+	return &proto.UpdateOrderStatusResponse{Order: toProtoOrder(orderData)}, nil
+}
+
+func requesterFromContext(ctx context.Context) (*order.Requester, error) {
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user ID not found")
+	}
+	roles, _ := ctx.Value("roles").([]string)
+	return &order.Requester{UserID: userID, Roles: roles}, nil
+}
+
+func statusFromProto(protoStatus proto.OrderStatus) (string, bool) {
+	for status, p := range orderStatuses {
+		if p == protoStatus {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+func toProtoOrder(orderData *order.Order) *proto.SingleOrder {
+	var products []*proto.ProductData
+	for _, p := range orderData.Products {
+		products = append(products, &proto.ProductData{
+			ProductId: p.ID,
+			Quantity:  p.Quantity,
+			UnitPrice: p.UnitPrice,
+			Currency:  p.Currency,
+			LineTotal: p.LineTotal(),
+		})
+	}
+	return &proto.SingleOrder{
+		Id:         orderData.ID,
+		UserId:     orderData.UserID,
+		Products:   products,
+		TotalPrice: orderData.Total(),
+		Currency:   orderData.Currency(),
+		Status:     orderStatuses[orderData.Status],
+		CreatedAt:  orderData.CreatedAt.Unix(),
+
+		ShippingAddress: toProtoAddress(orderData.ShippingAddress),
+		BillingAddress:  toProtoAddress(orderData.BillingAddress),
+
+		CouponCode: orderData.CouponCode,
+		Discount:   orderData.Discount,
+	}
+}
+
+func toProtoAddress(address *order.Address) *proto.Address {
+	if address == nil {
+		return nil
+	}
+	return &proto.Address{
+		RecipientName: address.RecipientName,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		City:          address.City,
+		Region:        address.Region,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		Phone:         address.Phone,
+	}
+}
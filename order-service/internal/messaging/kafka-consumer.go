@@ -3,36 +3,55 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 	"go.uber.org/zap"
 )
 
 type OrderService interface {
 	CreateOrder(ctx context.Context, order *order.Order) (int32, error)
-	GetOrderByID(ctx context.Context, orderID int32) (*order.Order, error)
-	GetOrderByUserID(ctx context.Context, userID int32) ([]*order.Order, error)
-	DeleteOrder(ctx context.Context, orderID int32) error
+	DeadLetterCheckout(ctx context.Context, letter *deadletter.DeadLetter) error
+}
+
+// RetryPolicy controls how often a checkout message is retried before it is dead-lettered.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff doubles the wait after every failed attempt, up to MaxBackoff.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.MaxBackoff)
 }
 
 type KafkaConsumer struct {
 	logger   *zap.SugaredLogger
 	Consumer *kafka.Consumer
 	Service  OrderService
+	retry    RetryPolicy
 	group    string
 	topic    string
 }
 
-// TODO: Implement Kafka consumer to listen for checkout messages and process them in the order service
-func NewKafkaConsumer(logger *zap.SugaredLogger, brokers string, group string, topic string, service OrderService) *KafkaConsumer {
+func NewKafkaConsumer(logger *zap.SugaredLogger, brokers string, group string, topic string, retry RetryPolicy, service OrderService) *KafkaConsumer {
 	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
-		"bootstrap.servers": brokers,
-		"group.id":          group,
-		"auto.offset.reset": "earliest",
+		"bootstrap.servers":  brokers,
+		"group.id":           group,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
 	})
 	if err != nil {
 		panic(err)
@@ -48,6 +67,7 @@ func NewKafkaConsumer(logger *zap.SugaredLogger, brokers string, group string, t
 		Consumer: consumer,
 		group:    group,
 		topic:    topic,
+		retry:    retry,
 		Service:  service,
 	}
 }
@@ -56,60 +76,143 @@ func (c *KafkaConsumer) Close() {
 	c.Consumer.Close()
 }
 
-// TODO: Implement message processing logic to create orders based on the received checkout messages
+// Poll processes checkout messages one at a time. The offset is committed once a message has
+// either produced an order or been dead-lettered, so nothing is dropped silently. A message that
+// could be neither is read again from its offset.
 func (c *KafkaConsumer) Poll() {
 	const op = "Order.Messaging.Poll"
 	for {
 		msg, err := c.Consumer.ReadMessage(-1) // blocks until a message is received
-		if err == nil {
-			c.logger.Debugw("Consumed message: ", "topic", msg.TopicPartition, "value", string(msg.Value), "op", op)
-
-			// Deserialize full checkout message (user_id + products)
-			var checkout products.CheckoutMessage
-			err := DeserializeFromJSON(msg.Value, &checkout)
-			if err != nil {
-				c.logger.Errorw("Failed to deserialize message", "error", err, "op", op)
-				continue
-			}
+		if err != nil {
+			c.logger.Errorw("Consumer error: ", "error", err, "op", op)
+			continue
+		}
+		c.logger.Debugw("Consumed message: ", "topic", msg.TopicPartition, "value", string(msg.Value), "op", op)
 
-			// Fallback: if key is set, prefer it for logging, but use JSON user_id for data
-			if msg.Key != nil {
-				if keyUserID, errConv := strconv.Atoi(string(msg.Key)); errConv == nil && int32(keyUserID) != checkout.UserID {
-					c.logger.Debugw("Kafka key user_id differs from payload user_id", "key_user_id", keyUserID, "payload_user_id", checkout.UserID, "op", op)
-				}
+		if !c.process(msg) {
+			if err := c.Consumer.Seek(msg.TopicPartition, 0); err != nil {
+				c.logger.Errorw("Failed to rewind to message", "error", err, "op", op)
 			}
+			continue
+		}
 
-			orderID, err := c.Service.CreateOrder(context.Background(), &order.Order{
-				UserID: checkout.UserID,
-				Products: func() []*order.ProductData {
-					var orderProducts []*order.ProductData
-					for _, p := range checkout.Products {
-						orderProducts = append(orderProducts, &order.ProductData{
-							ID:       p.ID,
-							Quantity: p.Quantity,
-						})
-					}
-					return orderProducts
-				}(),
-			})
-			if err != nil {
-				c.logger.Errorw("Failed to create order", "error", err, "op", op)
-			} else {
-				c.logger.Debugw("Order created successfully", "order_id", orderID, "op", op)
-			}
-			_, err = c.Consumer.CommitMessage(msg)
-			if err != nil {
-				c.logger.Errorw("Failed to commit message", "error", err, "op", op)
-			}
-			c.logger.Debugw("Message committed successfully", "topic", msg.TopicPartition, "op", op)
+		if _, err := c.Consumer.CommitMessage(msg); err != nil {
+			c.logger.Errorw("Failed to commit message", "error", err, "op", op)
 			continue
+		}
+		c.logger.Debugw("Message committed successfully", "topic", msg.TopicPartition, "op", op)
+	}
+}
 
-		} else {
-			c.logger.Errorw("Consumer error: ", "error", err, "op", op)
+// process reports whether the message is done with. Retries are bounded by the retry policy, so
+// the consumer doesn't stall long enough to be dropped from its group.
+func (c *KafkaConsumer) process(msg *kafka.Message) bool {
+	const op = "Order.Messaging.process"
+
+	// Deserialize full checkout message (user_id + products)
+	var checkout products.CheckoutMessage
+	err := DeserializeFromJSON(msg.Value, &checkout)
+	if err != nil {
+		c.logger.Errorw("Failed to deserialize message", "error", err, "op", op)
+		return c.deadLetter(msg, "", err, 1)
+	}
+
+	// Fallback: if key is set, prefer it for logging, but use JSON user_id for data
+	if msg.Key != nil {
+		if keyUserID, errConv := strconv.Atoi(string(msg.Key)); errConv == nil && int32(keyUserID) != checkout.UserID {
+			c.logger.Debugw("Kafka key user_id differs from payload user_id", "key_user_id", keyUserID, "payload_user_id", checkout.UserID, "op", op)
+		}
+	}
+
+	orderData := &order.Order{
+		EventID:         checkout.EventID,
+		UserID:          checkout.UserID,
+		ReservationID:   checkout.ReservationID,
+		ShippingAddress: toOrderAddress(checkout.ShippingAddress),
+		BillingAddress:  toOrderAddress(checkout.BillingAddress),
+		CouponCode:      checkout.CouponCode,
+		Discount:        checkout.Discount,
+	}
+	for _, p := range checkout.Products {
+		orderData.Products = append(orderData.Products, &order.ProductData{
+			ID:        p.ID,
+			Quantity:  p.Quantity,
+			UnitPrice: p.UnitPrice,
+			Currency:  p.Currency,
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		orderID, err := c.Service.CreateOrder(context.Background(), orderData)
+		if err == nil {
+			c.logger.Debugw("Order created successfully", "order_id", orderID, "op", op)
+			return true
 		}
+
+		if isPermanent(err) || attempt >= c.retry.MaxAttempts {
+			c.logger.Errorw("Failed to create order, giving up", "error", err, "attempt", attempt, "event_id", checkout.EventID, "op", op)
+			return c.deadLetter(msg, checkout.EventID, err, attempt)
+		}
+
+		backoff := c.retry.Backoff(attempt)
+		c.logger.Warnw("Failed to create order, retrying", "error", err, "attempt", attempt, "backoff", backoff, "event_id", checkout.EventID, "op", op)
+		time.Sleep(backoff)
 	}
 }
 
+// deadLetter reports whether the message was parked. Committing the offset without it would lose
+// the checkout, so a message that couldn't be parked is left to be read again.
+func (c *KafkaConsumer) deadLetter(msg *kafka.Message, eventID string, cause error, attempts int) bool {
+	const op = "Order.Messaging.deadLetter"
+
+	letter := &deadletter.DeadLetter{
+		EventID:  eventID,
+		Key:      string(msg.Key),
+		Payload:  msg.Value,
+		Error:    cause.Error(),
+		Attempts: int32(attempts),
+	}
+	for retry := 1; ; retry++ {
+		err := c.Service.DeadLetterCheckout(context.Background(), letter)
+		if err == nil {
+			return true
+		}
+		if retry >= c.retry.MaxAttempts {
+			c.logger.Errorw("Failed to dead-letter message, reading it again", "error", err, "attempt", retry, "op", op)
+			return false
+		}
+		backoff := c.retry.Backoff(retry)
+		c.logger.Errorw("Failed to dead-letter message, retrying", "error", err, "backoff", backoff, "op", op)
+		time.Sleep(backoff)
+	}
+}
+
+func toOrderAddress(a *products.AddressData) *order.Address {
+	if a == nil {
+		return nil
+	}
+	return &order.Address{
+		RecipientName: a.RecipientName,
+		Line1:         a.Line1,
+		Line2:         a.Line2,
+		City:          a.City,
+		Region:        a.Region,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+		Phone:         a.Phone,
+	}
+}
+
+// isPermanent reports errors that retrying the same message can't fix.
+func isPermanent(err error) bool {
+	return errors.Is(err, apierrors.ErrInvalidOrderData) ||
+		errors.Is(err, apierrors.ErrIncorrectID) ||
+		errors.Is(err, apierrors.ErrNotEnoughProduct) ||
+		errors.Is(err, apierrors.ErrPromotionNotFound) ||
+		errors.Is(err, apierrors.ErrPromotionLimitReached) ||
+		errors.Is(err, apierrors.ErrPromotionNotApplicable)
+}
+
 func DeserializeFromJSON(data []byte, v interface{}) error {
 	err := json.Unmarshal(data, v)
 	if err != nil {
@@ -0,0 +1,111 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+)
+
+type fakeOrderService struct {
+	createErrs    []error // returned by successive CreateOrder calls, nil once used up
+	deadLetterErr error
+	creates       int
+	deadLetters   int
+}
+
+func (f *fakeOrderService) CreateOrder(ctx context.Context, o *order.Order) (int32, error) {
+	f.creates++
+	if f.creates <= len(f.createErrs) && f.createErrs[f.creates-1] != nil {
+		return 0, f.createErrs[f.creates-1]
+	}
+	return 1, nil
+}
+
+func (f *fakeOrderService) DeadLetterCheckout(ctx context.Context, letter *deadletter.DeadLetter) error {
+	f.deadLetters++
+	return f.deadLetterErr
+}
+
+func checkoutMessage(t *testing.T) *kafka.Message {
+	t.Helper()
+	payload, err := json.Marshal(&products.CheckoutMessage{
+		EventID:       "evt-1",
+		UserID:        1,
+		ReservationID: 3,
+		Products:      []products.ProductIDQuantity{{ID: 1, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &kafka.Message{Key: []byte("1"), Value: payload}
+}
+
+func newTestConsumer(service OrderService) *KafkaConsumer {
+	return &KafkaConsumer{
+		logger:  zap.NewNop().Sugar(),
+		Service: service,
+		retry:   RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	}
+}
+
+func TestProcessRetriesTransientErrors(t *testing.T) {
+	transient := errors.New("connection refused")
+	service := &fakeOrderService{createErrs: []error{transient, transient}}
+
+	if done := newTestConsumer(service).process(checkoutMessage(t)); !done {
+		t.Fatal("process() = false, want the order created on the third attempt")
+	}
+	if service.creates != 3 || service.deadLetters != 0 {
+		t.Errorf("creates = %d, dead letters = %d, want 3 and 0", service.creates, service.deadLetters)
+	}
+}
+
+func TestProcessDeadLettersPermanentErrors(t *testing.T) {
+	service := &fakeOrderService{createErrs: []error{apierrors.ErrNotEnoughProduct}}
+
+	if done := newTestConsumer(service).process(checkoutMessage(t)); !done {
+		t.Fatal("process() = false, want the message parked")
+	}
+	if service.creates != 1 || service.deadLetters != 1 {
+		t.Errorf("creates = %d, dead letters = %d, want 1 and 1", service.creates, service.deadLetters)
+	}
+}
+
+// A dead-letter store that is down must not hold the poll loop forever, the message is read again instead.
+func TestProcessGivesUpDeadLetteringAfterMaxAttempts(t *testing.T) {
+	service := &fakeOrderService{
+		createErrs:    []error{apierrors.ErrInvalidOrderData},
+		deadLetterErr: errors.New("dead-letter topic unavailable"),
+	}
+
+	if done := newTestConsumer(service).process(checkoutMessage(t)); done {
+		t.Fatal("process() = true, want the message left uncommitted")
+	}
+	if service.deadLetters != 3 {
+		t.Errorf("dead-letter attempts = %d, want 3", service.deadLetters)
+	}
+}
+
+// Coupons are redeemed after the stock is committed. A coupon that can't be redeemed has cancelled
+// the order already, retrying would only hammer products-service.
+func TestProcessDeadLettersFailedRedemptions(t *testing.T) {
+	for _, err := range []error{apierrors.ErrPromotionNotFound, apierrors.ErrPromotionLimitReached} {
+		service := &fakeOrderService{createErrs: []error{err}}
+
+		if done := newTestConsumer(service).process(checkoutMessage(t)); !done {
+			t.Fatalf("%v: process() = false, want the message parked", err)
+		}
+		if service.creates != 1 || service.deadLetters != 1 {
+			t.Errorf("%v: creates = %d, dead letters = %d, want 1 and 1", err, service.creates, service.deadLetters)
+		}
+	}
+}
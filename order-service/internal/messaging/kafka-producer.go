@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+)
+
+type KafkaProducer struct {
+	logger   *zap.SugaredLogger
+	Producer *kafka.Producer
+}
+
+func NewKafkaProducer(logger *zap.SugaredLogger, brokers string) *KafkaProducer {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": brokers,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return &KafkaProducer{
+		logger:   logger,
+		Producer: producer,
+	}
+}
+
+// Publish blocks until the broker acknowledges the message or ctx is done.
+func (p *KafkaProducer) Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error {
+	const op = "Order.Messaging.Publish"
+
+	kafkaHeaders := make([]kafka.Header, 0, len(headers))
+	for k, v := range headers {
+		kafkaHeaders = append(kafkaHeaders, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	// not closed on purpose: the delivery report may still arrive after ctx is done
+	deliveryChan := make(chan kafka.Event, 1)
+
+	err := p.Producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:     []byte(key),
+		Value:   payload,
+		Headers: kafkaHeaders,
+	}, deliveryChan)
+	if err != nil {
+		p.logger.Errorw("Failed to produce message", "error", err, "topic", topic, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	select {
+	case e := <-deliveryChan:
+		m := e.(*kafka.Message)
+		if m.TopicPartition.Error != nil {
+			p.logger.Errorw("Failed to deliver message", "error", m.TopicPartition.Error, "topic", topic, "op", op)
+			return apierrors.ErrUnknown
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (p *KafkaProducer) Close() {
+	p.Producer.Flush(5000)
+	p.Producer.Close()
+}
+
+// NewEventID returns a random ID consumers use to drop redelivered events.
+func NewEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,135 @@
+package messaging
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+)
+
+// eventUserDeleted is published by sso-service once an account is gone.
+const eventUserDeleted = "user.deleted"
+
+type UserAnonymizer interface {
+	AnonymizeUser(ctx context.Context, userID int32) error
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error
+}
+
+// UserEventsConsumer anonymizes the orders of every user deleted in sso-service. Events it can't
+// handle go to the dead-letter topic.
+type UserEventsConsumer struct {
+	logger          *zap.SugaredLogger
+	Consumer        *kafka.Consumer
+	anonymizer      UserAnonymizer
+	retry           RetryPolicy
+	publisher       Publisher
+	deadLetterTopic string
+}
+
+func NewUserEventsConsumer(logger *zap.SugaredLogger, brokers string, group string, topic string, retry RetryPolicy, anonymizer UserAnonymizer, publisher Publisher, deadLetterTopic string) *UserEventsConsumer {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  brokers,
+		"group.id":           group,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = consumer.SubscribeTopics([]string{topic}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return &UserEventsConsumer{
+		logger:          logger,
+		Consumer:        consumer,
+		anonymizer:      anonymizer,
+		retry:           retry,
+		publisher:       publisher,
+		deadLetterTopic: deadLetterTopic,
+	}
+}
+
+func (c *UserEventsConsumer) Close() {
+	c.Consumer.Close()
+}
+
+// Poll handles user events one at a time and commits each once it is done with it. An event that
+// was neither handled nor dead-lettered is read again from its offset.
+func (c *UserEventsConsumer) Poll() {
+	const op = "Order.Messaging.UserEventsConsumer.Poll"
+	for {
+		msg, err := c.Consumer.ReadMessage(-1) // blocks until a message is received
+		if err != nil {
+			c.logger.Errorw("Consumer error: ", "error", err, "op", op)
+			continue
+		}
+
+		if !c.process(msg) {
+			if err := c.Consumer.Seek(msg.TopicPartition, 0); err != nil {
+				c.logger.Errorw("Failed to rewind to message", "error", err, "op", op)
+			}
+			continue
+		}
+
+		if _, err := c.Consumer.CommitMessage(msg); err != nil {
+			c.logger.Errorw("Failed to commit message", "error", err, "op", op)
+		}
+	}
+}
+
+// process reports whether the event is done with.
+func (c *UserEventsConsumer) process(msg *kafka.Message) bool {
+	const op = "Order.Messaging.UserEventsConsumer.process"
+
+	var event struct {
+		EventID string `json:"event_id"`
+		Type    string `json:"type"`
+		UserID  int32  `json:"user_id"`
+	}
+	if err := DeserializeFromJSON(msg.Value, &event); err != nil {
+		c.logger.Errorw("Failed to deserialize user event", "error", err, "value", string(msg.Value), "op", op)
+		return c.deadLetter(msg, "", err, 1)
+	}
+	if event.Type != eventUserDeleted {
+		return true
+	}
+
+	// the user asked to be forgotten, an event that keeps failing is parked rather than skipped
+	for attempt := 1; ; attempt++ {
+		err := c.anonymizer.AnonymizeUser(context.Background(), event.UserID)
+		if err == nil {
+			return true
+		}
+		if attempt >= c.retry.MaxAttempts {
+			c.logger.Errorw("Failed to anonymize deleted user, giving up", "error", err, "user_id", event.UserID, "attempt", attempt, "event_id", event.EventID, "op", op)
+			return c.deadLetter(msg, event.EventID, err, attempt)
+		}
+		backoff := c.retry.Backoff(attempt)
+		c.logger.Warnw("Failed to anonymize deleted user, retrying", "error", err, "user_id", event.UserID, "attempt", attempt, "backoff", backoff, "event_id", event.EventID, "op", op)
+		time.Sleep(backoff)
+	}
+}
+
+// deadLetter reports whether the event went to the dead-letter topic.
+func (c *UserEventsConsumer) deadLetter(msg *kafka.Message, eventID string, cause error, attempts int) bool {
+	const op = "Order.Messaging.UserEventsConsumer.deadLetter"
+
+	err := c.publisher.Publish(context.Background(), c.deadLetterTopic, string(msg.Key), msg.Value, map[string]string{
+		"error":    cause.Error(),
+		"attempts": strconv.Itoa(attempts),
+		"event_id": eventID,
+	})
+	if err != nil {
+		c.logger.Errorw("Failed to dead-letter user event, reading it again", "error", err, "event_id", eventID, "op", op)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+)
+
+type failingAnonymizer struct {
+	calls int
+}
+
+func (a *failingAnonymizer) AnonymizeUser(ctx context.Context, userID int32) error {
+	a.calls++
+	return errors.New("database unavailable")
+}
+
+type recordingPublisher struct {
+	err     error
+	topics  []string
+	headers []map[string]string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error {
+	p.topics = append(p.topics, topic)
+	p.headers = append(p.headers, headers)
+	return p.err
+}
+
+func TestUserEventsConsumerDeadLettersAfterMaxAttempts(t *testing.T) {
+	anonymizer := &failingAnonymizer{}
+	publisher := &recordingPublisher{}
+	c := &UserEventsConsumer{
+		logger:          zap.NewNop().Sugar(),
+		anonymizer:      anonymizer,
+		retry:           RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		publisher:       publisher,
+		deadLetterTopic: "user-events-dlt",
+	}
+	msg := &kafka.Message{Value: []byte(`{"event_id":"evt-9","type":"user.deleted","user_id":5}`)}
+
+	if !c.process(msg) {
+		t.Fatal("process() = false, want the event parked on the dead-letter topic")
+	}
+	if anonymizer.calls != 4 {
+		t.Errorf("AnonymizeUser called %d times, want 4", anonymizer.calls)
+	}
+	if len(publisher.topics) != 1 || publisher.topics[0] != "user-events-dlt" {
+		t.Fatalf("published to %v, want the dead-letter topic once", publisher.topics)
+	}
+	if h := publisher.headers[0]; h["event_id"] != "evt-9" || h["attempts"] != "4" {
+		t.Errorf("dead letter headers = %v", h)
+	}
+
+	// with the dead-letter topic down the offset must stay where it is
+	publisher.err = errors.New("broker unavailable")
+	if c.process(msg) {
+		t.Error("process() = true although the event was neither handled nor parked")
+	}
+}
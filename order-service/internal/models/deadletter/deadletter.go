@@ -0,0 +1,20 @@
+package deadletter
+
+import "time"
+
+// DeadLetter is a checkout message the consumer gave up on.
+type DeadLetter struct {
+	ID         int32
+	EventID    string
+	Key        string
+	Payload    []byte
+	Error      string
+	Attempts   int32
+	CreatedAt  time.Time
+	ReplayedAt *time.Time // nil until an admin replays the message
+}
+
+type ListFilter struct {
+	Limit           uint64
+	IncludeReplayed bool
+}
@@ -1,18 +1,60 @@
 package order
 
-const (
-	StatusCreated   = "created"
-	StatusCancelled = "cancelled"
-)
+import "time"
 
 type ProductData struct {
-	ID       int32
-	Quantity int32
+	ID        int32
+	Quantity  int32
+	UnitPrice int64 // minor units of Currency at the time of purchase
+	Currency  string
+}
+
+func (p *ProductData) LineTotal() int64 {
+	return p.UnitPrice * int64(p.Quantity)
+}
+
+// DeletedUserID replaces the owner of orders whose user deleted their account. User ids start
+// at 1, so it never matches a real user.
+const DeletedUserID int32 = 0
+
+// Address is the snapshot of an address book entry taken at checkout.
+type Address struct {
+	RecipientName string `json:"recipient_name"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code,omitempty"`
+	Country       string `json:"country"`
+	Phone         string `json:"phone,omitempty"`
 }
 
 type Order struct {
-	ID       int32
-	UserID   int32
-	Status   string
-	Products []*ProductData
+	ID              int32
+	UserID          int32
+	Status          string
+	ReservationID   int32  // stock reservation in products-service made at checkout
+	EventID         string // checkout event the order was created from
+	CreatedAt       time.Time
+	Products        []*ProductData
+	ShippingAddress *Address // nil for orders placed before checkout took an address
+	BillingAddress  *Address
+	CouponCode      string // empty if no coupon was applied
+	Discount        int64  // minor units of Currency, taken off the line totals
+}
+
+func (o *Order) Total() int64 {
+	var total int64
+	for _, p := range o.Products {
+		total += p.LineTotal()
+	}
+	return total - o.Discount
+}
+
+// Currency of the order, checkout only lets through carts with a single currency.
+func (o *Order) Currency() string {
+	if len(o.Products) == 0 {
+		return ""
+	}
+	return o.Products[0].Currency
 }
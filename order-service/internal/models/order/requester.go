@@ -0,0 +1,18 @@
+package order
+
+import "github.com/sabirkekw/ecommerce_go/pkg/auth"
+
+// Requester is the authenticated caller an order is read or changed on behalf of.
+type Requester struct {
+	UserID int32
+	Roles  []string
+}
+
+func (r *Requester) IsAdmin() bool {
+	return auth.HasRole(r.Roles, auth.RoleAdmin)
+}
+
+// CanAccess reports whether the requester owns the order or is an admin.
+func (r *Requester) CanAccess(o *Order) bool {
+	return r.IsAdmin() || o.UserID == r.UserID
+}
@@ -0,0 +1,100 @@
+package order
+
+import "testing"
+
+var allStatuses = []string{
+	StatusPending, StatusPaid, StatusFulfilled, StatusShipped, StatusDelivered, StatusCancelled, StatusRefunded,
+}
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{StatusPending, StatusPaid}:       true,
+		{StatusPending, StatusCancelled}:  true,
+		{StatusPaid, StatusFulfilled}:     true,
+		{StatusPaid, StatusRefunded}:      true,
+		{StatusFulfilled, StatusShipped}:  true,
+		{StatusFulfilled, StatusRefunded}: true,
+		{StatusShipped, StatusDelivered}:  true,
+		{StatusDelivered, StatusRefunded}: true,
+	}
+
+	// every pair of statuses, so a transition added to the table without a test fails here
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			want := allowed[[2]string{from, to}]
+			t.Run(from+"->"+to, func(t *testing.T) {
+				if got := CanTransition(from, to); got != want {
+					t.Errorf("CanTransition(%q, %q) = %v, want %v", from, to, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestCanTransitionUnknownStatus(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+	}{
+		{from: "unknown", to: StatusPaid},
+		{from: StatusPending, to: "unknown"},
+		{from: "", to: StatusCancelled},
+		{from: StatusPending, to: ""},
+		{from: "PENDING", to: StatusPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if CanTransition(tt.from, tt.to) {
+				t.Errorf("CanTransition(%q, %q) = true, want false", tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestIsValidStatus(t *testing.T) {
+	for _, status := range allStatuses {
+		if !IsValidStatus(status) {
+			t.Errorf("IsValidStatus(%q) = false, want true", status)
+		}
+	}
+	for _, status := range []string{"", "unknown", "Pending", "canceled"} {
+		if IsValidStatus(status) {
+			t.Errorf("IsValidStatus(%q) = true, want false", status)
+		}
+	}
+}
+
+func TestFinalStatuses(t *testing.T) {
+	for _, status := range []string{StatusCancelled, StatusRefunded} {
+		for _, to := range allStatuses {
+			if CanTransition(status, to) {
+				t.Errorf("CanTransition(%q, %q) = true, %s is final", status, to, status)
+			}
+		}
+	}
+}
+
+func TestReturnsStock(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{status: StatusPending},
+		{status: StatusPaid},
+		{status: StatusFulfilled},
+		{status: StatusShipped},
+		{status: StatusDelivered},
+		{status: StatusCancelled, want: true},
+		{status: StatusRefunded, want: true},
+		{status: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := ReturnsStock(tt.status); got != tt.want {
+				t.Errorf("ReturnsStock(%q) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
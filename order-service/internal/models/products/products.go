@@ -1,11 +1,30 @@
 package products
 
 type ProductIDQuantity struct {
-	ID       int32 `json:"id"`
-	Quantity int32 `json:"quantity"`
+	ID        int32  `json:"id"`
+	Quantity  int32  `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+	Currency  string `json:"currency"`
+}
+
+type AddressData struct {
+	RecipientName string `json:"recipient_name"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2"`
+	City          string `json:"city"`
+	Region        string `json:"region"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+	Phone         string `json:"phone"`
 }
 
 type CheckoutMessage struct {
-	UserID   int32               `json:"user_id"`
-	Products []ProductIDQuantity `json:"products"`
+	EventID         string              `json:"event_id"`
+	UserID          int32               `json:"user_id"`
+	ReservationID   int32               `json:"reservation_id"`
+	Products        []ProductIDQuantity `json:"products"`
+	ShippingAddress *AddressData        `json:"shipping_address"`
+	BillingAddress  *AddressData        `json:"billing_address"`
+	CouponCode      string              `json:"coupon_code"`
+	Discount        int64               `json:"discount"`
 }
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+func (r *Repository) CreateDeadLetter(ctx context.Context, letter *deadletter.DeadLetter) (int32, error) {
+	const op = "Order.Repository.CreateDeadLetter"
+
+	query := r.builder.Insert("dead_letters").
+		Columns("event_id", "message_key", "payload", "error", "attempts").
+		Values(letter.EventID, letter.Key, letter.Payload, letter.Error, letter.Attempts).
+		Suffix("RETURNING id")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	var id int32
+	if err := r.db.QueryRowContext(ctx, sqlStr, args...).Scan(&id); err != nil {
+		r.log.Errorw("failed to insert dead letter", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	return id, nil
+}
+
+func (r *Repository) GetDeadLetter(ctx context.Context, id int32) (*deadletter.DeadLetter, error) {
+	const op = "Order.Repository.GetDeadLetter"
+
+	query := r.builder.Select(deadLetterColumns...).
+		From("dead_letters").
+		Where(sq.Eq{"id": id})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	letter, err := scanDeadLetter(r.db.QueryRowContext(ctx, sqlStr, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrDeadLetterNotFound
+	} else if err != nil {
+		r.log.Errorw("failed to read dead letter", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	return letter, nil
+}
+
+// ListDeadLetters returns the oldest dead letters first.
+func (r *Repository) ListDeadLetters(ctx context.Context, filter *deadletter.ListFilter) ([]*deadletter.DeadLetter, error) {
+	const op = "Order.Repository.ListDeadLetters"
+
+	query := r.builder.Select(deadLetterColumns...).
+		From("dead_letters").
+		OrderBy("id").
+		Limit(filter.Limit)
+	if !filter.IncludeReplayed {
+		query = query.Where(sq.Eq{"replayed_at": nil})
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		r.log.Errorw("failed to query dead letters", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var letters []*deadletter.DeadLetter
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			r.log.Errorw("failed to scan dead letter", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		letters = append(letters, letter)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Errorw("failed to iterate dead letters", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	return letters, nil
+}
+
+// MarkDeadLetterReplayed fails with apierrors.ErrDeadLetterReplayed if someone replayed the letter first.
+func (r *Repository) MarkDeadLetterReplayed(ctx context.Context, id int32) error {
+	const op = "Order.Repository.MarkDeadLetterReplayed"
+
+	query := r.builder.Update("dead_letters").
+		Set("replayed_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "replayed_at": nil})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		r.log.Errorw("failed to update dead letter", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Errorw("failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrDeadLetterReplayed
+	}
+
+	return nil
+}
+
+// ReopenDeadLetter undoes MarkDeadLetterReplayed for a replay that didn't go through.
+func (r *Repository) ReopenDeadLetter(ctx context.Context, id int32) error {
+	const op = "Order.Repository.ReopenDeadLetter"
+
+	query := r.builder.Update("dead_letters").
+		Set("replayed_at", nil).
+		Where(sq.Eq{"id": id})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := r.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		r.log.Errorw("failed to update dead letter", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	return nil
+}
+
+var deadLetterColumns = []string{"id", "event_id", "message_key", "payload", "error", "attempts", "created_at", "replayed_at"}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDeadLetter(row rowScanner) (*deadletter.DeadLetter, error) {
+	var (
+		letter     deadletter.DeadLetter
+		replayedAt sql.NullTime
+	)
+	if err := row.Scan(
+		&letter.ID,
+		&letter.EventID,
+		&letter.Key,
+		&letter.Payload,
+		&letter.Error,
+		&letter.Attempts,
+		&letter.CreatedAt,
+		&replayedAt,
+	); err != nil {
+		return nil, err
+	}
+	if replayedAt.Valid {
+		letter.ReplayedAt = &replayedAt.Time
+	}
+	return &letter, nil
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
@@ -24,6 +27,8 @@ func New(db *sql.DB, log *zap.SugaredLogger) *Repository {
 	}
 }
 
+// CreateOrder records order.EventID along with the order. If the event was already processed,
+// it returns the ID of the order created back then and apierrors.ErrEventProcessed.
 func (r *Repository) CreateOrder(ctx context.Context, order *order.Order) (int32, error) {
 	const op = "Order.Repository.CreateOrder"
 
@@ -34,10 +39,21 @@ func (r *Repository) CreateOrder(ctx context.Context, order *order.Order) (int32
 	}
 	defer tx.Rollback()
 
+	shippingAddress, err := encodeAddress(order.ShippingAddress)
+	if err != nil {
+		r.log.Errorw("failed to encode shipping address", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	billingAddress, err := encodeAddress(order.BillingAddress)
+	if err != nil {
+		r.log.Errorw("failed to encode billing address", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
 	var orderID int32
 	err = tx.QueryRowContext(ctx,
-		`INSERT INTO orders (user_id, status) VALUES ($1, $2) RETURNING id`,
-		order.UserID, order.Status,
+		`INSERT INTO orders (user_id, status, reservation_id, shipping_address, billing_address, coupon_code, discount) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		order.UserID, order.Status, order.ReservationID, shippingAddress, billingAddress, sql.NullString{String: order.CouponCode, Valid: order.CouponCode != ""}, order.Discount,
 	).Scan(&orderID)
 	if err != nil {
 		r.log.Errorw("failed to insert order", "error", err, "op", op)
@@ -46,14 +62,41 @@ func (r *Repository) CreateOrder(ctx context.Context, order *order.Order) (int32
 
 	for _, p := range order.Products {
 		if _, err := tx.ExecContext(ctx,
-			`INSERT INTO order_products (order_id, product_id, quantity) VALUES ($1, $2, $3)`,
-			orderID, p.ID, p.Quantity,
+			`INSERT INTO order_products (order_id, product_id, quantity, unit_price, currency) VALUES ($1, $2, $3, $4, $5)`,
+			orderID, p.ID, p.Quantity, p.UnitPrice, p.Currency,
 		); err != nil {
 			r.log.Errorw("failed to insert order product", "error", err, "op", op)
 			return 0, apierrors.ErrUnknown
 		}
 	}
 
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO processed_events (event_id, order_id) VALUES ($1, $2) ON CONFLICT (event_id) DO NOTHING`,
+		order.EventID, orderID,
+	)
+	if err != nil {
+		r.log.Errorw("failed to record processed event", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Errorw("failed to get affected rows", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		// rolling back the duplicate order, the one from the first delivery stays
+		var existingOrderID int32
+		if err := tx.QueryRowContext(ctx,
+			`SELECT order_id FROM processed_events WHERE event_id = $1`,
+			order.EventID,
+		).Scan(&existingOrderID); err != nil {
+			r.log.Errorw("failed to read processed event", "error", err, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+		r.log.Debugw("event already processed", "event_id", order.EventID, "order_id", existingOrderID, "op", op)
+		return existingOrderID, apierrors.ErrEventProcessed
+	}
+
 	if err := tx.Commit(); err != nil {
 		r.log.Errorw("failed to commit transaction", "error", err, "op", op)
 		return 0, apierrors.ErrUnknown
@@ -69,11 +112,19 @@ func (r *Repository) GetOrderByID(ctx context.Context, orderID int32) (*order.Or
 		"o.id",
 		"o.user_id",
 		"o.status",
+		"o.created_at",
+		"o.reservation_id",
+		"o.shipping_address",
+		"o.billing_address",
+		"o.coupon_code",
+		"o.discount",
 		"oi.product_id",
 		"oi.quantity",
+		"oi.unit_price",
+		"oi.currency",
 	).
 		From("orders o").
-		LeftJoin("order_items oi ON oi.order_id = o.id").
+		LeftJoin("order_products oi ON oi.order_id = o.id").
 		Where(sq.Eq{"o.id": orderID})
 
 	sqlStr, args, err := query.ToSql()
@@ -96,14 +147,22 @@ func (r *Repository) GetOrderByID(ctx context.Context, orderID int32) (*order.Or
 		found = true
 
 		var (
-			orderID   int32
-			userID    int32
-			status    string
-			productID *int32
-			quantity  *int32
+			orderID         int32
+			userID          int32
+			status          string
+			createdAt       time.Time
+			reservationID   sql.NullInt32
+			shippingAddress []byte
+			billingAddress  []byte
+			couponCode      sql.NullString
+			discount        int64
+			productID       *int32
+			quantity        *int32
+			unitPrice       *int64
+			currency        *string
 		)
 
-		if err := rows.Scan(&orderID, &userID, &status, &productID, &quantity); err != nil {
+		if err := rows.Scan(&orderID, &userID, &status, &createdAt, &reservationID, &shippingAddress, &billingAddress, &couponCode, &discount, &productID, &quantity, &unitPrice, &currency); err != nil {
 			return nil, apierrors.ErrUnknown
 		}
 
@@ -111,12 +170,26 @@ func (r *Repository) GetOrderByID(ctx context.Context, orderID int32) (*order.Or
 			orderData.ID = orderID
 			orderData.UserID = userID
 			orderData.Status = status
+			orderData.CreatedAt = createdAt
+			orderData.ReservationID = reservationID.Int32
+			orderData.CouponCode = couponCode.String
+			orderData.Discount = discount
+			if orderData.ShippingAddress, err = decodeAddress(shippingAddress); err != nil {
+				r.log.Errorw("failed to decode shipping address", "error", err, "order_id", orderID, "op", op)
+				return nil, apierrors.ErrUnknown
+			}
+			if orderData.BillingAddress, err = decodeAddress(billingAddress); err != nil {
+				r.log.Errorw("failed to decode billing address", "error", err, "order_id", orderID, "op", op)
+				return nil, apierrors.ErrUnknown
+			}
 		}
 
 		if productID != nil {
 			orderData.Products = append(orderData.Products, &order.ProductData{
-				ID:       *productID,
-				Quantity: *quantity,
+				ID:        *productID,
+				Quantity:  *quantity,
+				UnitPrice: *unitPrice,
+				Currency:  *currency,
 			})
 		}
 	}
@@ -132,16 +205,39 @@ func (r *Repository) GetOrderByID(ctx context.Context, orderID int32) (*order.Or
 	return &orderData, nil
 }
 
+// GetOrderIDByEventID returns the order created from the checkout event, apierrors.ErrOrderNotFound if
+// the event never produced one.
+func (r *Repository) GetOrderIDByEventID(ctx context.Context, eventID string) (int32, error) {
+	const op = "Order.Repository.GetOrderIDByEventID"
+
+	var orderID int32
+	err := r.db.QueryRowContext(ctx, `SELECT order_id FROM processed_events WHERE event_id = $1`, eventID).Scan(&orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, apierrors.ErrOrderNotFound
+	} else if err != nil {
+		r.log.Errorw("failed to read processed event", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return orderID, nil
+}
+
 func (r *Repository) GetOrdersByUserID(ctx context.Context, userID int32) ([]*order.Order, error) {
 	query := r.builder.Select(
 		"o.id",
 		"o.user_id",
 		"o.status",
+		"o.created_at",
+		"o.shipping_address",
+		"o.billing_address",
+		"o.coupon_code",
+		"o.discount",
 		"oi.product_id",
 		"oi.quantity",
+		"oi.unit_price",
+		"oi.currency",
 	).
 		From("orders o").
-		LeftJoin("order_items oi ON oi.order_id = o.id").
+		LeftJoin("order_products oi ON oi.order_id = o.id").
 		Where(sq.Eq{"o.user_id": userID})
 
 	sqlStr, args, err := query.ToSql()
@@ -159,31 +255,49 @@ func (r *Repository) GetOrdersByUserID(ctx context.Context, userID int32) ([]*or
 
 	for rows.Next() {
 		var (
-			orderID   int32
-			userID    int32
-			status    string
-			productID *int32
-			quantity  *int32
+			orderID         int32
+			userID          int32
+			status          string
+			createdAt       time.Time
+			shippingAddress []byte
+			billingAddress  []byte
+			couponCode      sql.NullString
+			discount        int64
+			productID       *int32
+			quantity        *int32
+			unitPrice       *int64
+			currency        *string
 		)
 
-		if err := rows.Scan(&orderID, &userID, &status, &productID, &quantity); err != nil {
+		if err := rows.Scan(&orderID, &userID, &status, &createdAt, &shippingAddress, &billingAddress, &couponCode, &discount, &productID, &quantity, &unitPrice, &currency); err != nil {
 			return nil, err
 		}
 
 		o, exists := ordersMap[orderID]
 		if !exists {
 			o = &order.Order{
-				ID:     orderID,
-				UserID: userID,
-				Status: status,
+				ID:         orderID,
+				UserID:     userID,
+				Status:     status,
+				CreatedAt:  createdAt,
+				CouponCode: couponCode.String,
+				Discount:   discount,
+			}
+			if o.ShippingAddress, err = decodeAddress(shippingAddress); err != nil {
+				return nil, err
+			}
+			if o.BillingAddress, err = decodeAddress(billingAddress); err != nil {
+				return nil, err
 			}
 			ordersMap[orderID] = o
 		}
 
 		if productID != nil {
 			o.Products = append(o.Products, &order.ProductData{
-				ID:       *productID,
-				Quantity: *quantity,
+				ID:        *productID,
+				Quantity:  *quantity,
+				UnitPrice: *unitPrice,
+				Currency:  *currency,
 			})
 		}
 	}
@@ -196,13 +310,15 @@ func (r *Repository) GetOrdersByUserID(ctx context.Context, userID int32) ([]*or
 	return orders, nil
 }
 
-func (r *Repository) DeleteOrder(ctx context.Context, orderID int32) error {
-	const op = "Order.Repository.DeleteOrder"
+// UpdateOrderStatus moves the order from one status to another. It fails with
+// apierrors.ErrInvalidStatusTransition if the order is no longer in the from status.
+func (r *Repository) UpdateOrderStatus(ctx context.Context, orderID int32, from string, to string) error {
+	const op = "Order.Repository.UpdateOrderStatus"
 
 	query := r.builder.
 		Update("orders").
-		Set("status", order.StatusCancelled).
-		Where(sq.Eq{"id": orderID})
+		Set("status", to).
+		Where(sq.Eq{"id": orderID, "status": from})
 
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
@@ -222,8 +338,133 @@ func (r *Repository) DeleteOrder(ctx context.Context, orderID int32) error {
 	}
 
 	if rowsAffected == 0 {
-		return apierrors.ErrOrderNotFound
+		r.log.Debugw("order status changed concurrently", "order_id", orderID, "from", from, "op", op)
+		return apierrors.ErrInvalidStatusTransition
 	}
 
 	return nil
 }
+
+// FindUnrestockedOrders returns up to limit cancelled or refunded orders whose stock hasn't gone back
+// to products-service yet, oldest first. Only ID and ReservationID are set.
+func (r *Repository) FindUnrestockedOrders(ctx context.Context, limit uint64) ([]*order.Order, error) {
+	const op = "Order.Repository.FindUnrestockedOrders"
+
+	query := r.builder.
+		Select("id", "reservation_id").
+		From("orders").
+		Where(sq.Eq{"restocked_at": nil, "status": []string{order.StatusCancelled, order.StatusRefunded}}).
+		OrderBy("id").
+		Limit(limit)
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		r.log.Errorw("failed to execute query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var orders []*order.Order
+	for rows.Next() {
+		var (
+			orderData     order.Order
+			reservationID sql.NullInt32
+		)
+		if err := rows.Scan(&orderData.ID, &reservationID); err != nil {
+			r.log.Errorw("failed to scan order", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		orderData.ReservationID = reservationID.Int32
+		orders = append(orders, &orderData)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Errorw("failed to iterate orders", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) MarkOrderRestocked(ctx context.Context, orderID int32) error {
+	const op = "Order.Repository.MarkOrderRestocked"
+
+	query := r.builder.
+		Update("orders").
+		Set("restocked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": orderID, "restocked_at": nil})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := r.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		r.log.Errorw("failed to execute query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	return nil
+}
+
+// AnonymizeUserOrders detaches the orders of a deleted user from them and drops the address
+// snapshots, which name the user. The orders themselves are kept for bookkeeping. Returns how many
+// orders were changed.
+func (r *Repository) AnonymizeUserOrders(ctx context.Context, userID int32) (int64, error) {
+	const op = "Order.Repository.AnonymizeUserOrders"
+
+	query := r.builder.
+		Update("orders").
+		Set("user_id", order.DeletedUserID).
+		Set("shipping_address", nil).
+		Set("billing_address", nil).
+		Where(sq.Eq{"user_id": userID})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		r.log.Errorw("failed to build query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	result, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		r.log.Errorw("failed to execute query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, apierrors.ErrUnknown
+	}
+	return rowsAffected, nil
+}
+
+// encodeAddress returns the JSON for a JSONB column, nil stays NULL.
+func encodeAddress(address *order.Address) (any, error) {
+	if address == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(address)
+	if err != nil {
+		return nil, err
+	}
+	// a string, lib/pq would send []byte as bytea
+	return string(data), nil
+}
+
+func decodeAddress(data []byte) (*order.Address, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var address order.Address
+	if err := json.Unmarshal(data, &address); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
@@ -0,0 +1,220 @@
+package orderservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/messaging"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/products"
+	productsProto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDeadLettersLimit = 50
+	maxDeadLettersLimit     = 500
+)
+
+// DeadLetterCheckout parks a checkout message the consumer gave up on: it goes to the dead-letter topic
+// and is recorded so admins can list and replay it. An order the message left pending is cancelled.
+func (s *Service) DeadLetterCheckout(ctx context.Context, letter *deadletter.DeadLetter) error {
+	const op = "Order.Service.DeadLetterCheckout"
+	s.logger.Warnw("dead-lettering checkout message", "event_id", letter.EventID, "attempts", letter.Attempts, "error", letter.Error, "op", op)
+
+	if err := s.cancelAbandoned(ctx, letter.EventID); err != nil {
+		return err
+	}
+
+	err := s.publisher.Publish(ctx, s.deadLetterTopic, letter.Key, letter.Payload, map[string]string{
+		"error":    letter.Error,
+		"attempts": strconv.Itoa(int(letter.Attempts)),
+		"event_id": letter.EventID,
+	})
+	if err != nil {
+		s.logger.Errorw("failed to publish dead letter", "error", err, "op", op)
+		return err
+	}
+
+	id, err := s.storage.CreateDeadLetter(ctx, letter)
+	if err != nil {
+		s.logger.Errorw("failed to store dead letter", "error", err, "op", op)
+		return err
+	}
+
+	s.logger.Debugw("checkout message dead-lettered", "dead_letter_id", id, "op", op)
+	return nil
+}
+
+func (s *Service) ListDeadLetters(ctx context.Context, filter *deadletter.ListFilter) ([]*deadletter.DeadLetter, error) {
+	const op = "Order.Service.ListDeadLetters"
+	s.logger.Debugw("listing dead letters", "limit", filter.Limit, "include_replayed", filter.IncludeReplayed, "op", op)
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultDeadLettersLimit
+	} else if filter.Limit > maxDeadLettersLimit {
+		filter.Limit = maxDeadLettersLimit
+	}
+
+	letters, err := s.storage.ListDeadLetters(ctx, filter)
+	if err != nil {
+		s.logger.Errorw("failed to list dead letters", "error", err, "op", op)
+		return nil, err
+	}
+	return letters, nil
+}
+
+// ReplayDeadLetter runs the checkout of a dead letter again. The reservation made at checkout has
+// expired by now, so the stock is reserved anew and the message goes back on the checkout topic under
+// a new event ID, the order the original event may have left behind was cancelled. Letters whose
+// checkout still has a live order aren't replayed, that order has to be cancelled first.
+func (s *Service) ReplayDeadLetter(ctx context.Context, id int32) error {
+	const op = "Order.Service.ReplayDeadLetter"
+	s.logger.Debugw("replaying dead letter", "dead_letter_id", id, "op", op)
+
+	letter, err := s.storage.GetDeadLetter(ctx, id)
+	if errors.Is(err, apierrors.ErrDeadLetterNotFound) {
+		s.logger.Debugw("dead letter not found", "dead_letter_id", id, "op", op)
+		return apierrors.ErrDeadLetterNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to get dead letter", "error", err, "dead_letter_id", id, "op", op)
+		return err
+	}
+	if letter.ReplayedAt != nil {
+		return apierrors.ErrDeadLetterReplayed
+	}
+
+	var checkout products.CheckoutMessage
+	if err := json.Unmarshal(letter.Payload, &checkout); err != nil || checkout.EventID == "" || len(checkout.Products) == 0 {
+		s.logger.Debugw("dead letter isn't a checkout message", "error", err, "dead_letter_id", id, "op", op)
+		return apierrors.ErrDeadLetterNotReplayable
+	}
+	if err := s.checkNoLiveOrder(ctx, checkout.EventID); err != nil {
+		return err
+	}
+
+	// claiming the letter first, so concurrent replays can't both publish it
+	if err := s.storage.MarkDeadLetterReplayed(ctx, id); err != nil {
+		if !errors.Is(err, apierrors.ErrDeadLetterReplayed) {
+			s.logger.Errorw("failed to mark dead letter replayed", "error", err, "dead_letter_id", id, "op", op)
+		}
+		return err
+	}
+	if err := s.replayCheckout(ctx, letter, &checkout); err != nil {
+		if err := s.storage.ReopenDeadLetter(context.WithoutCancel(ctx), id); err != nil {
+			s.logger.Errorw("failed to reopen dead letter", "error", err, "dead_letter_id", id, "op", op)
+		}
+		return err
+	}
+
+	s.logger.Debugw("dead letter replayed", "dead_letter_id", id, "event_id", checkout.EventID, "op", op)
+	return nil
+}
+
+// cancelAbandoned cancels the order of eventID if it is still pending. Nothing retries its checkout
+// anymore, so its reservation would expire and the stock be sold again while the order stayed open.
+func (s *Service) cancelAbandoned(ctx context.Context, eventID string) error {
+	const op = "Order.Service.cancelAbandoned"
+	if eventID == "" {
+		return nil
+	}
+
+	orderID, err := s.storage.GetOrderIDByEventID(ctx, eventID)
+	if errors.Is(err, apierrors.ErrOrderNotFound) {
+		return nil
+	} else if err != nil {
+		s.logger.Errorw("failed to look up order of event", "error", err, "event_id", eventID, "op", op)
+		return err
+	}
+	orderData, err := s.storage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		s.logger.Errorw("failed to get order", "error", err, "order_id", orderID, "op", op)
+		return err
+	}
+	if orderData.Status != order.StatusPending {
+		return nil
+	}
+
+	s.logger.Warnw("cancelling order of dead-lettered checkout", "order_id", orderID, "event_id", eventID, "op", op)
+	return s.cancelPending(ctx, orderID, orderData.ReservationID)
+}
+
+// checkNoLiveOrder fails with apierrors.ErrDeadLetterOrderExists if the event produced an order that
+// wasn't cancelled.
+func (s *Service) checkNoLiveOrder(ctx context.Context, eventID string) error {
+	const op = "Order.Service.checkNoLiveOrder"
+
+	orderID, err := s.storage.GetOrderIDByEventID(ctx, eventID)
+	if errors.Is(err, apierrors.ErrOrderNotFound) {
+		return nil
+	} else if err != nil {
+		s.logger.Errorw("failed to look up order of event", "error", err, "event_id", eventID, "op", op)
+		return err
+	}
+	orderData, err := s.storage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		s.logger.Errorw("failed to get order", "error", err, "order_id", orderID, "op", op)
+		return err
+	}
+	if orderData.Status != order.StatusCancelled {
+		s.logger.Debugw("checkout already has an order", "order_id", orderID, "status", orderData.Status, "op", op)
+		return apierrors.ErrDeadLetterOrderExists
+	}
+	return nil
+}
+
+// replayCheckout reserves the stock of checkout and publishes it with the new reservation under a new
+// event ID. A reservation that couldn't be published is released.
+func (s *Service) replayCheckout(ctx context.Context, letter *deadletter.DeadLetter, checkout *products.CheckoutMessage) error {
+	const op = "Order.Service.replayCheckout"
+
+	items := make([]*productsProto.StockItem, 0, len(checkout.Products))
+	for _, p := range checkout.Products {
+		items = append(items, &productsProto.StockItem{
+			ProductId: p.ID,
+			Quantity:  p.Quantity,
+		})
+	}
+	reservationID, err := s.productsClient.ReserveStock(ctx, items)
+	if code := status.Code(err); code == codes.FailedPrecondition {
+		s.logger.Debugw("not enough stock to replay checkout", "dead_letter_id", letter.ID, "op", op)
+		return apierrors.ErrNotEnoughProduct
+	} else if code == codes.NotFound {
+		s.logger.Debugw("product of checkout no longer exists", "dead_letter_id", letter.ID, "op", op)
+		return apierrors.ErrProductNotFound
+	} else if err != nil {
+		s.logger.Errorw("failed to reserve stock", "error", err, "dead_letter_id", letter.ID, "op", op)
+		return err
+	}
+
+	replay := *checkout
+	replay.ReservationID = reservationID
+	replay.EventID, err = messaging.NewEventID()
+	if err != nil {
+		s.logger.Errorw("failed to generate event ID", "error", err, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return apierrors.ErrUnknown
+	}
+	payload, err := json.Marshal(&replay)
+	if err != nil {
+		s.logger.Errorw("failed to serialize checkout message", "error", err, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return apierrors.ErrUnknown
+	}
+
+	err = s.publisher.Publish(ctx, s.checkoutTopic, letter.Key, payload, map[string]string{
+		"replay_of": checkout.EventID,
+	})
+	if err != nil {
+		s.logger.Errorw("failed to republish checkout message", "error", err, "dead_letter_id", letter.ID, "op", op)
+		s.releaseReservation(ctx, reservationID)
+		return err
+	}
+	s.logger.Debugw("checkout republished", "event_id", replay.EventID, "reservation_id", reservationID, "op", op)
+	return nil
+}
@@ -0,0 +1,125 @@
+package orderservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+)
+
+// fakeStorage keeps orders in memory. Methods a test doesn't set up panic through the nil Repository.
+type fakeStorage struct {
+	Repository
+	orders      map[int32]*order.Order
+	restocked   []int32
+	deadLetters []*deadletter.DeadLetter
+}
+
+func (f *fakeStorage) GetOrderIDByEventID(ctx context.Context, eventID string) (int32, error) {
+	for id, o := range f.orders {
+		if o.EventID == eventID {
+			return id, nil
+		}
+	}
+	return 0, apierrors.ErrOrderNotFound
+}
+
+func (f *fakeStorage) GetOrderByID(ctx context.Context, orderID int32) (*order.Order, error) {
+	o, ok := f.orders[orderID]
+	if !ok {
+		return nil, apierrors.ErrOrderNotFound
+	}
+	return o, nil
+}
+
+func (f *fakeStorage) UpdateOrderStatus(ctx context.Context, orderID int32, from string, to string) error {
+	o, ok := f.orders[orderID]
+	if !ok || o.Status != from {
+		return apierrors.ErrInvalidStatusTransition
+	}
+	o.Status = to
+	return nil
+}
+
+func (f *fakeStorage) MarkOrderRestocked(ctx context.Context, orderID int32) error {
+	f.restocked = append(f.restocked, orderID)
+	return nil
+}
+
+func (f *fakeStorage) CreateDeadLetter(ctx context.Context, letter *deadletter.DeadLetter) (int32, error) {
+	f.deadLetters = append(f.deadLetters, letter)
+	return int32(len(f.deadLetters)), nil
+}
+
+// fakeProducts records the calls made to products-service, in order.
+type fakeProducts struct {
+	ProductClient
+	calls     []string
+	commitErr error
+	redeemErr error
+}
+
+func (f *fakeProducts) RestockReservation(ctx context.Context, reservationID int32) error {
+	f.calls = append(f.calls, "restock")
+	return nil
+}
+
+type fakePublisher struct {
+	topics []string
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error {
+	f.topics = append(f.topics, topic)
+	return nil
+}
+
+func TestDeadLetterCheckoutCancelsPendingOrder(t *testing.T) {
+	storage := &fakeStorage{orders: map[int32]*order.Order{
+		7: {ID: 7, EventID: "evt-1", ReservationID: 3, Status: order.StatusPending},
+	}}
+	productsClient := &fakeProducts{}
+	publisher := &fakePublisher{}
+	s := NewService(storage, productsClient, publisher, "checkout", "checkout-dlt", zap.NewNop().Sugar())
+
+	err := s.DeadLetterCheckout(context.Background(), &deadletter.DeadLetter{EventID: "evt-1", Error: "commit timed out", Attempts: 5})
+	if err != nil {
+		t.Fatalf("DeadLetterCheckout() error = %v", err)
+	}
+
+	if got := storage.orders[7].Status; got != order.StatusCancelled {
+		t.Errorf("order status = %q, want %q", got, order.StatusCancelled)
+	}
+	if len(productsClient.calls) != 1 || len(storage.restocked) != 1 {
+		t.Errorf("stock of the cancelled order wasn't given back: calls %v, restocked %v", productsClient.calls, storage.restocked)
+	}
+	if len(publisher.topics) != 1 || publisher.topics[0] != "checkout-dlt" || len(storage.deadLetters) != 1 {
+		t.Errorf("message wasn't dead-lettered: topics %v, stored %d", publisher.topics, len(storage.deadLetters))
+	}
+}
+
+func TestDeadLetterCheckoutLeavesOtherOrders(t *testing.T) {
+	storage := &fakeStorage{orders: map[int32]*order.Order{
+		7: {ID: 7, EventID: "evt-1", ReservationID: 3, Status: order.StatusPaid},
+	}}
+	productsClient := &fakeProducts{}
+	s := NewService(storage, productsClient, &fakePublisher{}, "checkout", "checkout-dlt", zap.NewNop().Sugar())
+
+	for _, eventID := range []string{"evt-1", "evt-unknown", ""} {
+		if err := s.DeadLetterCheckout(context.Background(), &deadletter.DeadLetter{EventID: eventID}); err != nil {
+			t.Fatalf("DeadLetterCheckout(%q) error = %v", eventID, err)
+		}
+	}
+
+	if got := storage.orders[7].Status; got != order.StatusPaid {
+		t.Errorf("order status = %q, want it untouched", got)
+	}
+	if len(productsClient.calls) != 0 {
+		t.Errorf("products-service called for orders that aren't pending: %v", productsClient.calls)
+	}
+	if len(storage.deadLetters) != 3 {
+		t.Errorf("stored %d dead letters, want 3", len(storage.deadLetters))
+	}
+}
@@ -0,0 +1,60 @@
+package orderservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const restockBatchSize = 100
+
+// RestockOrders gives the stock of cancelled and refunded orders back to products-service every
+// interval until ctx is cancelled. It picks up the orders whose restock failed when their status changed.
+func (s *Service) RestockOrders(ctx context.Context, interval time.Duration) {
+	const op = "Order.Service.RestockOrders"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			orders, err := s.storage.FindUnrestockedOrders(ctx, restockBatchSize)
+			if err != nil {
+				s.logger.Errorw("failed to find orders to restock", "error", err, "op", op)
+				continue
+			}
+			for _, orderData := range orders {
+				if err := s.restock(ctx, orderData); err != nil {
+					s.logger.Errorw("failed to restock order", "error", err, "order_id", orderData.ID, "op", op)
+				}
+			}
+		}
+	}
+}
+
+// restock returns the products of the order to the stock. products-service restocks a reservation
+// once, so repeating it after a failure is safe.
+func (s *Service) restock(ctx context.Context, orderData *order.Order) error {
+	const op = "Order.Service.restock"
+
+	// orders from before stock reservations have nothing to give back
+	if orderData.ReservationID != 0 {
+		err := s.productsClient.RestockReservation(ctx, orderData.ReservationID)
+		if status.Code(err) == codes.NotFound {
+			s.logger.Warnw("reservation of order not found, nothing to restock", "order_id", orderData.ID, "reservation_id", orderData.ReservationID, "op", op)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if err := s.storage.MarkOrderRestocked(ctx, orderData.ID); err != nil {
+		return err
+	}
+	s.logger.Debugw("order restocked", "order_id", orderData.ID, "reservation_id", orderData.ReservationID, "op", op)
+	return nil
+}
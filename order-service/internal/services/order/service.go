@@ -4,55 +4,157 @@ import (
 	"context"
 	"errors"
 
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/deadletter"
 	order "github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
 	productsProto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Repository interface {
 	CreateOrder(ctx context.Context, order *order.Order) (int32, error)
 	GetOrderByID(ctx context.Context, orderID int32) (*order.Order, error)
+	GetOrderIDByEventID(ctx context.Context, eventID string) (int32, error)
 	GetOrdersByUserID(ctx context.Context, userID int32) ([]*order.Order, error)
-	DeleteOrder(ctx context.Context, orderID int32) error
+	UpdateOrderStatus(ctx context.Context, orderID int32, from string, to string) error
+	FindUnrestockedOrders(ctx context.Context, limit uint64) ([]*order.Order, error)
+	MarkOrderRestocked(ctx context.Context, orderID int32) error
+	CreateDeadLetter(ctx context.Context, letter *deadletter.DeadLetter) (int32, error)
+	GetDeadLetter(ctx context.Context, id int32) (*deadletter.DeadLetter, error)
+	ListDeadLetters(ctx context.Context, filter *deadletter.ListFilter) ([]*deadletter.DeadLetter, error)
+	MarkDeadLetterReplayed(ctx context.Context, id int32) error
+	ReopenDeadLetter(ctx context.Context, id int32) error
+	AnonymizeUserOrders(ctx context.Context, userID int32) (int64, error)
 }
 
 type ProductClient interface {
 	GetProductByID(ctx context.Context, id int32) (*productsProto.Product, error)
+	ReserveStock(ctx context.Context, items []*productsProto.StockItem) (int32, error)
+	CommitReservation(ctx context.Context, reservationID int32) error
+	ReleaseReservation(ctx context.Context, reservationID int32) error
+	RestockReservation(ctx context.Context, reservationID int32) error
+	RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key string, payload []byte, headers map[string]string) error
 }
 
 type Service struct {
-	storage        Repository
-	productsClient ProductClient // deprecated
-	logger         *zap.SugaredLogger
+	storage         Repository
+	productsClient  ProductClient
+	publisher       Publisher
+	checkoutTopic   string
+	deadLetterTopic string
+	logger          *zap.SugaredLogger
 }
 
-func NewService(storage Repository, client ProductClient, logger *zap.SugaredLogger) *Service {
+func NewService(storage Repository, client ProductClient, publisher Publisher, checkoutTopic string, deadLetterTopic string, logger *zap.SugaredLogger) *Service {
 	return &Service{
-		storage:        storage,
-		productsClient: client,
-		logger:         logger,
+		storage:         storage,
+		productsClient:  client,
+		publisher:       publisher,
+		checkoutTopic:   checkoutTopic,
+		deadLetterTopic: deadLetterTopic,
+		logger:          logger,
 	}
 }
 
-func (s *Service) CreateOrder(ctx context.Context, order *order.Order) (int32, error) {
+func (s *Service) CreateOrder(ctx context.Context, newOrder *order.Order) (int32, error) {
 	const op = "Order.Service.CreateOrder"
-	s.logger.Debugw("creating order", "user_id", order.UserID, "op", op)
+	s.logger.Debugw("creating order", "user_id", newOrder.UserID, "op", op)
+
+	if newOrder.ReservationID <= 0 {
+		s.logger.Debugw("checkout without stock reservation", "op", op)
+		return 0, apierrors.ErrInvalidOrderData
+	}
 
-	orderID, err := s.storage.CreateOrder(ctx, order)
-	if errors.Is(err, apierrors.ErrIncorrectID) {
+	if newOrder.EventID == "" {
+		s.logger.Debugw("checkout without event ID", "op", op)
+		return 0, apierrors.ErrInvalidOrderData
+	}
+
+	// orders always enter the lifecycle as pending, whatever the caller set
+	newOrder.Status = order.StatusPending
+
+	orderID, err := s.storage.CreateOrder(ctx, newOrder)
+	if errors.Is(err, apierrors.ErrEventProcessed) {
+		// redelivery: committing again is a no-op unless the first attempt crashed right before it
+		s.logger.Debugw("checkout event already processed", "event_id", newOrder.EventID, "order_id", orderID, "op", op)
+	} else if errors.Is(err, apierrors.ErrIncorrectID) {
 		s.logger.Debugw("incorrect ID", "error", err, "op", op)
+		s.releaseReservation(ctx, newOrder.ReservationID)
 		return 0, apierrors.ErrIncorrectID
 	} else if err != nil {
+		// the consumer retries transient failures, so the reservation is kept for the next attempt.
+		// If it gives up, the reservation expires and products-service returns the stock.
 		s.logger.Errorw("failed to create order in repository", "error", err, "op", op)
 		return 0, err
 	}
+
+	// the order only holds once its reserved stock is taken for good
+	err = s.productsClient.CommitReservation(ctx, newOrder.ReservationID)
+	if code := status.Code(err); code == codes.FailedPrecondition || code == codes.NotFound {
+		s.logger.Warnw("reservation can't be committed, cancelling order", "error", err, "order_id", orderID, "op", op)
+		if err := s.storage.UpdateOrderStatus(ctx, orderID, order.StatusPending, order.StatusCancelled); err != nil {
+			s.logger.Errorw("failed to cancel order", "error", err, "order_id", orderID, "op", op)
+		}
+		return 0, apierrors.ErrNotEnoughProduct
+	} else if err != nil {
+		s.logger.Errorw("failed to commit reservation", "error", err, "order_id", orderID, "op", op)
+		return 0, err
+	}
+
+	// the coupon is redeemed once the order holds, so an order that fails never uses it up. An order
+	// the coupon can't be redeemed for is cancelled and its stock goes back. Redelivered events are
+	// redeemed once, products-service keys them by event ID.
+	if newOrder.CouponCode != "" {
+		err = s.productsClient.RedeemPromotion(ctx, newOrder.CouponCode, newOrder.UserID, newOrder.EventID, newOrder.Discount)
+		if code := status.Code(err); code == codes.ResourceExhausted || code == codes.NotFound {
+			s.logger.Warnw("coupon can't be redeemed, cancelling order", "error", err, "order_id", orderID, "op", op)
+			s.cancelPending(ctx, orderID, newOrder.ReservationID)
+			if code == codes.NotFound {
+				return 0, apierrors.ErrPromotionNotFound
+			}
+			return 0, apierrors.ErrPromotionLimitReached
+		} else if err != nil {
+			s.logger.Errorw("failed to redeem coupon", "error", err, "order_id", orderID, "op", op)
+			return 0, err
+		}
+	}
+
 	s.logger.Debugw("order created successfully", "order_id", orderID, "op", op)
 	return orderID, nil
 }
-func (s *Service) GetOrderByID(ctx context.Context, orderID int32) (*order.Order, error) {
+
+// cancelPending cancels a pending order and gives its stock back, whether its reservation was
+// committed already or not. A failed restock is left to the restock sweeper.
+func (s *Service) cancelPending(ctx context.Context, orderID int32, reservationID int32) error {
+	const op = "Order.Service.cancelPending"
+	if err := s.storage.UpdateOrderStatus(ctx, orderID, order.StatusPending, order.StatusCancelled); err != nil {
+		s.logger.Errorw("failed to cancel order", "error", err, "order_id", orderID, "op", op)
+		return err
+	}
+	if err := s.restock(ctx, &order.Order{ID: orderID, ReservationID: reservationID}); err != nil {
+		s.logger.Errorw("failed to restock cancelled order, the sweeper retries", "error", err, "order_id", orderID, "op", op)
+	}
+	return nil
+}
+
+func (s *Service) releaseReservation(ctx context.Context, reservationID int32) {
+	const op = "Order.Service.releaseReservation"
+	if err := s.productsClient.ReleaseReservation(ctx, reservationID); err != nil {
+		s.logger.Errorw("failed to release reservation, it will expire on its own", "error", err, "reservation_id", reservationID, "op", op)
+	}
+}
+
+// GetOrderByID returns the order if the requester owns it or is an admin. Orders of other users
+// are reported as not found, so their IDs can't be probed.
+func (s *Service) GetOrderByID(ctx context.Context, requester *order.Requester, orderID int32) (*order.Order, error) {
 	const op = "Order.Service.GetOrderByID"
-	s.logger.Debugw("getting order by id", "order_id", orderID, "op", op)
+	s.logger.Debugw("getting order by id", "order_id", orderID, "user_id", requester.UserID, "op", op)
 
 	orderData, err := s.storage.GetOrderByID(ctx, orderID)
 	if errors.Is(err, apierrors.ErrOrderNotFound) {
@@ -62,6 +164,11 @@ func (s *Service) GetOrderByID(ctx context.Context, orderID int32) (*order.Order
 		s.logger.Errorw("failed to get order from repository", "error", err, "order_id", orderID, "op", op)
 		return nil, err
 	}
+
+	if !requester.CanAccess(orderData) {
+		s.logger.Warnw("access to another user's order denied", "order_id", orderID, "user_id", requester.UserID, "op", op)
+		return nil, apierrors.ErrOrderNotFound
+	}
 	return orderData, nil
 }
 func (s *Service) GetOrderByUserID(ctx context.Context, userID int32) ([]*order.Order, error) {
@@ -78,19 +185,78 @@ func (s *Service) GetOrderByUserID(ctx context.Context, userID int32) ([]*order.
 	}
 	return ordersData, nil
 }
-func (s *Service) DeleteOrder(ctx context.Context, orderID int32) error {
+
+// DeleteOrder cancels the order, which is only allowed while it is pending. Same ownership rules as GetOrderByID apply.
+func (s *Service) DeleteOrder(ctx context.Context, requester *order.Requester, orderID int32) error {
 	const op = "Order.Service.DeleteOrder"
-	s.logger.Debugw("deleting order", "order_id", orderID, "op", op)
+	s.logger.Debugw("cancelling order", "order_id", orderID, "user_id", requester.UserID, "op", op)
+
+	if _, err := s.GetOrderByID(ctx, requester, orderID); err != nil {
+		return err
+	}
+
+	if _, err := s.UpdateOrderStatus(ctx, orderID, order.StatusCancelled); err != nil {
+		return err
+	}
+
+	s.logger.Debugw("order cancelled successfully", "order_id", orderID, "op", op)
+	return nil
+}
 
-	err := s.storage.DeleteOrder(ctx, orderID)
+// UpdateOrderStatus moves the order along its lifecycle, rejecting transitions the state machine doesn't allow.
+func (s *Service) UpdateOrderStatus(ctx context.Context, orderID int32, newStatus string) (*order.Order, error) {
+	const op = "Order.Service.UpdateOrderStatus"
+	s.logger.Debugw("updating order status", "order_id", orderID, "status", newStatus, "op", op)
+
+	if !order.IsValidStatus(newStatus) {
+		return nil, apierrors.ErrInvalidOrderStatus
+	}
+
+	orderData, err := s.storage.GetOrderByID(ctx, orderID)
 	if errors.Is(err, apierrors.ErrOrderNotFound) {
 		s.logger.Debugw("order not found", "order_id", orderID, "op", op)
-		return apierrors.ErrOrderNotFound
+		return nil, apierrors.ErrOrderNotFound
 	} else if err != nil {
-		s.logger.Errorw("failed to delete order from repository", "error", err, "order_id", orderID, "op", op)
+		s.logger.Errorw("failed to get order from repository", "error", err, "order_id", orderID, "op", op)
+		return nil, err
+	}
+
+	if !order.CanTransition(orderData.Status, newStatus) {
+		s.logger.Debugw("status transition not allowed", "order_id", orderID, "from", orderData.Status, "to", newStatus, "op", op)
+		return nil, apierrors.ErrInvalidStatusTransition
+	}
+
+	err = s.storage.UpdateOrderStatus(ctx, orderID, orderData.Status, newStatus)
+	if errors.Is(err, apierrors.ErrInvalidStatusTransition) {
+		return nil, apierrors.ErrInvalidStatusTransition
+	} else if err != nil {
+		s.logger.Errorw("failed to update order status in repository", "error", err, "order_id", orderID, "op", op)
+		return nil, err
+	}
+
+	orderData.Status = newStatus
+	s.logger.Debugw("order status updated", "order_id", orderID, "status", newStatus, "op", op)
+
+	if order.ReturnsStock(newStatus) {
+		// RestockOrders retries whatever fails here
+		if err := s.restock(ctx, orderData); err != nil {
+			s.logger.Warnw("failed to restock order, will retry", "error", err, "order_id", orderID, "op", op)
+		}
+	}
+	return orderData, nil
+}
+
+// AnonymizeUser detaches the orders of a deleted user from their account. Running it twice is
+// harmless, so redelivered events need no deduplication.
+func (s *Service) AnonymizeUser(ctx context.Context, userID int32) error {
+	const op = "Order.Service.AnonymizeUser"
+
+	count, err := s.storage.AnonymizeUserOrders(ctx, userID)
+	if err != nil {
+		s.logger.Errorw("failed to anonymize user orders", "error", err, "user_id", userID, "op", op)
 		return err
 	}
 
-	s.logger.Debugw("order deleted successfully", "order_id", orderID, "op", op)
+	s.logger.Debugw("user orders anonymized", "user_id", userID, "orders", count, "op", op)
 	return nil
 }
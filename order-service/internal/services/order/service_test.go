@@ -0,0 +1,120 @@
+package orderservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sabirkekw/ecommerce_go/order-service/internal/models/order"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (f *fakeStorage) CreateOrder(ctx context.Context, o *order.Order) (int32, error) {
+	id := int32(len(f.orders) + 1)
+	stored := *o
+	stored.ID = id
+	f.orders[id] = &stored
+	return id, nil
+}
+
+func (f *fakeProducts) CommitReservation(ctx context.Context, reservationID int32) error {
+	f.calls = append(f.calls, "commit")
+	return f.commitErr
+}
+
+func (f *fakeProducts) RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error {
+	f.calls = append(f.calls, "redeem")
+	return f.redeemErr
+}
+
+// The coupon is redeemed only once the order holds its stock, and an order it can't be redeemed for
+// is cancelled with its stock given back.
+func TestCreateOrderRedeemsCouponAfterCommit(t *testing.T) {
+	tests := []struct {
+		name       string
+		coupon     string
+		commitErr  error
+		redeemErr  error
+		wantErr    error
+		wantCalls  []string
+		wantStatus string
+	}{
+		{
+			name:       "no coupon",
+			wantCalls:  []string{"commit"},
+			wantStatus: order.StatusPending,
+		},
+		{
+			name:       "coupon redeemed",
+			coupon:     "SPRING",
+			wantCalls:  []string{"commit", "redeem"},
+			wantStatus: order.StatusPending,
+		},
+		{
+			name:       "reservation gone",
+			coupon:     "SPRING",
+			commitErr:  status.Error(codes.FailedPrecondition, "reservation expired"),
+			wantErr:    apierrors.ErrNotEnoughProduct,
+			wantCalls:  []string{"commit"},
+			wantStatus: order.StatusCancelled,
+		},
+		{
+			name:       "coupon used up",
+			coupon:     "SPRING",
+			redeemErr:  status.Error(codes.ResourceExhausted, "promotion usage limit reached"),
+			wantErr:    apierrors.ErrPromotionLimitReached,
+			wantCalls:  []string{"commit", "redeem", "restock"},
+			wantStatus: order.StatusCancelled,
+		},
+		{
+			name:       "coupon deleted",
+			coupon:     "SPRING",
+			redeemErr:  status.Error(codes.NotFound, "promotion not found"),
+			wantErr:    apierrors.ErrPromotionNotFound,
+			wantCalls:  []string{"commit", "redeem", "restock"},
+			wantStatus: order.StatusCancelled,
+		},
+		{
+			// the consumer retries the event, redeeming it again is a no-op in products-service
+			name:       "products-service unavailable",
+			coupon:     "SPRING",
+			redeemErr:  status.Error(codes.Unavailable, "connection refused"),
+			wantCalls:  []string{"commit", "redeem"},
+			wantStatus: order.StatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &fakeStorage{orders: map[int32]*order.Order{}}
+			productsClient := &fakeProducts{commitErr: tt.commitErr, redeemErr: tt.redeemErr}
+			s := NewService(storage, productsClient, &fakePublisher{}, "checkout", "checkout-dlt", zap.NewNop().Sugar())
+
+			_, err := s.CreateOrder(context.Background(), &order.Order{
+				UserID: 1, EventID: "evt-1", ReservationID: 3, CouponCode: tt.coupon, Discount: 500,
+			})
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateOrder() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.redeemErr != nil:
+				if err == nil {
+					t.Error("CreateOrder() succeeded, want an error so the event is retried")
+				}
+			case err != nil:
+				t.Errorf("CreateOrder() error = %v", err)
+			}
+			if !reflect.DeepEqual(productsClient.calls, tt.wantCalls) {
+				t.Errorf("products-service calls = %v, want %v", productsClient.calls, tt.wantCalls)
+			}
+			if got := storage.orders[1].Status; got != tt.wantStatus {
+				t.Errorf("order status = %q, want %q", got, tt.wantStatus)
+			}
+		})
+	}
+}
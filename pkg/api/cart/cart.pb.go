@@ -23,11 +23,26 @@ const (
 )
 
 type CartProduct struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductName string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Quantity    int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// price in minor units at the moment the product was added
+	UnitPrice int64  `protobuf:"varint,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Currency  string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	// quantity at current_unit_price, 0 for deleted products
+	LineTotal int64 `protobuf:"varint,7,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	// price in products-service right now
+	CurrentUnitPrice int64 `protobuf:"varint,8,opt,name=current_unit_price,json=currentUnitPrice,proto3" json:"current_unit_price,omitempty"`
+	// stock left in products-service
+	AvailableQuantity int32 `protobuf:"varint,9,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"`
+	// the product is gone from the catalog and can't be checked out
+	ProductDeleted bool `protobuf:"varint,10,opt,name=product_deleted,json=productDeleted,proto3" json:"product_deleted,omitempty"`
+	// less stock is left than the cart asks for
+	InsufficientStock bool `protobuf:"varint,11,opt,name=insufficient_stock,json=insufficientStock,proto3" json:"insufficient_stock,omitempty"`
+	// current_unit_price differs from unit_price
+	PriceChanged  bool `protobuf:"varint,12,opt,name=price_changed,json=priceChanged,proto3" json:"price_changed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,9 +105,83 @@ func (x *CartProduct) GetDescription() string {
 	return ""
 }
 
+func (x *CartProduct) GetUnitPrice() int64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *CartProduct) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CartProduct) GetLineTotal() int64 {
+	if x != nil {
+		return x.LineTotal
+	}
+	return 0
+}
+
+func (x *CartProduct) GetCurrentUnitPrice() int64 {
+	if x != nil {
+		return x.CurrentUnitPrice
+	}
+	return 0
+}
+
+func (x *CartProduct) GetAvailableQuantity() int32 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
+func (x *CartProduct) GetProductDeleted() bool {
+	if x != nil {
+		return x.ProductDeleted
+	}
+	return false
+}
+
+func (x *CartProduct) GetInsufficientStock() bool {
+	if x != nil {
+		return x.InsufficientStock
+	}
+	return false
+}
+
+func (x *CartProduct) GetPriceChanged() bool {
+	if x != nil {
+		return x.PriceChanged
+	}
+	return false
+}
+
+// Cart is priced against products-service when it is read. All amounts are in minor units of
+// currency.
 type Cart struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*CartProduct         `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*CartProduct         `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	// same as grand_total, kept for older clients
+	TotalPrice int64  `protobuf:"varint,3,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Currency   string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	// line totals added up
+	Subtotal int64 `protobuf:"varint,5,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Discount int64 `protobuf:"varint,6,opt,name=discount,proto3" json:"discount,omitempty"`
+	// not computed yet, always 0
+	Tax int64 `protobuf:"varint,7,opt,name=tax,proto3" json:"tax,omitempty"`
+	// subtotal - discount + tax
+	GrandTotal int64 `protobuf:"varint,8,opt,name=grand_total,json=grandTotal,proto3" json:"grand_total,omitempty"`
+	// some product is deleted, out of stock or changed its price
+	HasStaleItems bool `protobuf:"varint,9,opt,name=has_stale_items,json=hasStaleItems,proto3" json:"has_stale_items,omitempty"`
+	// coupon on the cart, empty if there is none
+	CouponCode string `protobuf:"bytes,10,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	// false while the cart doesn't qualify for the coupon, discount is 0 then
+	CouponApplied bool `protobuf:"varint,11,opt,name=coupon_applied,json=couponApplied,proto3" json:"coupon_applied,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -134,6 +223,69 @@ func (x *Cart) GetProducts() []*CartProduct {
 	return nil
 }
 
+func (x *Cart) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *Cart) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Cart) GetSubtotal() int64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *Cart) GetDiscount() int64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *Cart) GetTax() int64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *Cart) GetGrandTotal() int64 {
+	if x != nil {
+		return x.GrandTotal
+	}
+	return 0
+}
+
+func (x *Cart) GetHasStaleItems() bool {
+	if x != nil {
+		return x.HasStaleItems
+	}
+	return false
+}
+
+func (x *Cart) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *Cart) GetCouponApplied() bool {
+	if x != nil {
+		return x.CouponApplied
+	}
+	return false
+}
+
 type AddToCartRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -230,27 +382,28 @@ func (x *AddToCartResponse) GetSuccess() bool {
 	return false
 }
 
-type RemoveFromCartRequest struct {
+type SetQuantityRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveFromCartRequest) Reset() {
-	*x = RemoveFromCartRequest{}
+func (x *SetQuantityRequest) Reset() {
+	*x = SetQuantityRequest{}
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveFromCartRequest) String() string {
+func (x *SetQuantityRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveFromCartRequest) ProtoMessage() {}
+func (*SetQuantityRequest) ProtoMessage() {}
 
-func (x *RemoveFromCartRequest) ProtoReflect() protoreflect.Message {
+func (x *SetQuantityRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -262,39 +415,46 @@ func (x *RemoveFromCartRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveFromCartRequest.ProtoReflect.Descriptor instead.
-func (*RemoveFromCartRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetQuantityRequest.ProtoReflect.Descriptor instead.
+func (*SetQuantityRequest) Descriptor() ([]byte, []int) {
 	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RemoveFromCartRequest) GetProductId() int32 {
+func (x *SetQuantityRequest) GetProductId() int32 {
 	if x != nil {
 		return x.ProductId
 	}
 	return 0
 }
 
-type RemoveFromCartResponse struct {
+func (x *SetQuantityRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type SetQuantityResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveFromCartResponse) Reset() {
-	*x = RemoveFromCartResponse{}
+func (x *SetQuantityResponse) Reset() {
+	*x = SetQuantityResponse{}
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveFromCartResponse) String() string {
+func (x *SetQuantityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveFromCartResponse) ProtoMessage() {}
+func (*SetQuantityResponse) ProtoMessage() {}
 
-func (x *RemoveFromCartResponse) ProtoReflect() protoreflect.Message {
+func (x *SetQuantityResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -306,38 +466,40 @@ func (x *RemoveFromCartResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveFromCartResponse.ProtoReflect.Descriptor instead.
-func (*RemoveFromCartResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetQuantityResponse.ProtoReflect.Descriptor instead.
+func (*SetQuantityResponse) Descriptor() ([]byte, []int) {
 	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RemoveFromCartResponse) GetSuccess() bool {
+func (x *SetQuantityResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-type GetCartRequest struct {
+type IncrementQuantityRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Delta         int32                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCartRequest) Reset() {
-	*x = GetCartRequest{}
+func (x *IncrementQuantityRequest) Reset() {
+	*x = IncrementQuantityRequest{}
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCartRequest) String() string {
+func (x *IncrementQuantityRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCartRequest) ProtoMessage() {}
+func (*IncrementQuantityRequest) ProtoMessage() {}
 
-func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
+func (x *IncrementQuantityRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -349,32 +511,46 @@ func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCartRequest.ProtoReflect.Descriptor instead.
-func (*GetCartRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use IncrementQuantityRequest.ProtoReflect.Descriptor instead.
+func (*IncrementQuantityRequest) Descriptor() ([]byte, []int) {
 	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{6}
 }
 
-type GetCartResponse struct {
+func (x *IncrementQuantityRequest) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *IncrementQuantityRequest) GetDelta() int32 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+type IncrementQuantityResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCartResponse) Reset() {
-	*x = GetCartResponse{}
+func (x *IncrementQuantityResponse) Reset() {
+	*x = IncrementQuantityResponse{}
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCartResponse) String() string {
+func (x *IncrementQuantityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCartResponse) ProtoMessage() {}
+func (*IncrementQuantityResponse) ProtoMessage() {}
 
-func (x *GetCartResponse) ProtoReflect() protoreflect.Message {
+func (x *IncrementQuantityResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -386,38 +562,40 @@ func (x *GetCartResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCartResponse.ProtoReflect.Descriptor instead.
-func (*GetCartResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use IncrementQuantityResponse.ProtoReflect.Descriptor instead.
+func (*IncrementQuantityResponse) Descriptor() ([]byte, []int) {
 	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetCartResponse) GetCart() *Cart {
+func (x *IncrementQuantityResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Cart
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type CheckoutRequest struct {
+type CartItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckoutRequest) Reset() {
-	*x = CheckoutRequest{}
+func (x *CartItem) Reset() {
+	*x = CartItem{}
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckoutRequest) String() string {
+func (x *CartItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckoutRequest) ProtoMessage() {}
+func (*CartItem) ProtoMessage() {}
 
-func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
+func (x *CartItem) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -429,32 +607,47 @@ func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckoutRequest.ProtoReflect.Descriptor instead.
-func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
 	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{8}
 }
 
-type CheckoutResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+func (x *CartItem) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CartItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type AddItemsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// max 100
+	Items         []*CartItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckoutResponse) Reset() {
-	*x = CheckoutResponse{}
+func (x *AddItemsRequest) Reset() {
+	*x = AddItemsRequest{}
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckoutResponse) String() string {
+func (x *AddItemsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckoutResponse) ProtoMessage() {}
+func (*AddItemsRequest) ProtoMessage() {}
 
-func (x *CheckoutResponse) ProtoReflect() protoreflect.Message {
+func (x *AddItemsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_api_cart_cart_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -466,53 +659,852 @@ func (x *CheckoutResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckoutResponse.ProtoReflect.Descriptor instead.
-func (*CheckoutResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddItemsRequest.ProtoReflect.Descriptor instead.
+func (*AddItemsRequest) Descriptor() ([]byte, []int) {
 	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *CheckoutResponse) GetSuccess() bool {
+func (x *AddItemsRequest) GetItems() []*CartItem {
 	if x != nil {
-		return x.Success
+		return x.Items
 	}
-	return false
+	return nil
 }
 
-var File_pkg_api_cart_cart_proto protoreflect.FileDescriptor
+type AddItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_pkg_api_cart_cart_proto_rawDesc = "" +
-	"\n" +
-	"\x17pkg/api/cart/cart.proto\x1a pkg/google/api/annotations.proto\"~\n" +
-	"\vCartProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12!\n" +
-	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12 \n" +
-	"\vdescription\x18\x04 \x01(\tR\vdescription\"0\n" +
-	"\x04Cart\x12(\n" +
-	"\bproducts\x18\x02 \x03(\v2\f.CartProductR\bproducts\"M\n" +
-	"\x10AddToCartRequest\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\"-\n" +
-	"\x11AddToCartResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"6\n" +
-	"\x15RemoveFromCartRequest\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x01 \x01(\x05R\tproductId\"2\n" +
-	"\x16RemoveFromCartResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x10\n" +
-	"\x0eGetCartRequest\",\n" +
-	"\x0fGetCartResponse\x12\x19\n" +
-	"\x04cart\x18\x01 \x01(\v2\x05.CartR\x04cart\"\x11\n" +
-	"\x0fCheckoutRequest\",\n" +
-	"\x10CheckoutResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xd4\x02\n" +
+func (x *AddItemsResponse) Reset() {
+	*x = AddItemsResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItemsResponse) ProtoMessage() {}
+
+func (x *AddItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItemsResponse.ProtoReflect.Descriptor instead.
+func (*AddItemsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddItemsResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+type ClearCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearCartRequest) Reset() {
+	*x = ClearCartRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearCartRequest) ProtoMessage() {}
+
+func (x *ClearCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearCartRequest.ProtoReflect.Descriptor instead.
+func (*ClearCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{11}
+}
+
+type ClearCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearCartResponse) Reset() {
+	*x = ClearCartResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearCartResponse) ProtoMessage() {}
+
+func (x *ClearCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearCartResponse.ProtoReflect.Descriptor instead.
+func (*ClearCartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClearCartResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RemoveFromCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveFromCartRequest) Reset() {
+	*x = RemoveFromCartRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveFromCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveFromCartRequest) ProtoMessage() {}
+
+func (x *RemoveFromCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveFromCartRequest.ProtoReflect.Descriptor instead.
+func (*RemoveFromCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RemoveFromCartRequest) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+type RemoveFromCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveFromCartResponse) Reset() {
+	*x = RemoveFromCartResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveFromCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveFromCartResponse) ProtoMessage() {}
+
+func (x *RemoveFromCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveFromCartResponse.ProtoReflect.Descriptor instead.
+func (*RemoveFromCartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RemoveFromCartResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type GetCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartRequest) Reset() {
+	*x = GetCartRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartRequest) ProtoMessage() {}
+
+func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartRequest.ProtoReflect.Descriptor instead.
+func (*GetCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{15}
+}
+
+type GetCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartResponse) Reset() {
+	*x = GetCartResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartResponse) ProtoMessage() {}
+
+func (x *GetCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartResponse.ProtoReflect.Descriptor instead.
+func (*GetCartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetCartResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+type CheckoutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// address book entries in sso-service, 0 picks the default of the user
+	ShippingAddressId int64 `protobuf:"varint,1,opt,name=shipping_address_id,json=shippingAddressId,proto3" json:"shipping_address_id,omitempty"`
+	BillingAddressId  int64 `protobuf:"varint,2,opt,name=billing_address_id,json=billingAddressId,proto3" json:"billing_address_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CheckoutRequest) Reset() {
+	*x = CheckoutRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutRequest) ProtoMessage() {}
+
+func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CheckoutRequest) GetShippingAddressId() int64 {
+	if x != nil {
+		return x.ShippingAddressId
+	}
+	return 0
+}
+
+func (x *CheckoutRequest) GetBillingAddressId() int64 {
+	if x != nil {
+		return x.BillingAddressId
+	}
+	return 0
+}
+
+type CheckoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutResponse) Reset() {
+	*x = CheckoutResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutResponse) ProtoMessage() {}
+
+func (x *CheckoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutResponse.ProtoReflect.Descriptor instead.
+func (*CheckoutResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CheckoutResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type CreateGuestCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGuestCartRequest) Reset() {
+	*x = CreateGuestCartRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGuestCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGuestCartRequest) ProtoMessage() {}
+
+func (x *CreateGuestCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGuestCartRequest.ProtoReflect.Descriptor instead.
+func (*CreateGuestCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{19}
+}
+
+type CreateGuestCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CartToken     string                 `protobuf:"bytes,1,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGuestCartResponse) Reset() {
+	*x = CreateGuestCartResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGuestCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGuestCartResponse) ProtoMessage() {}
+
+func (x *CreateGuestCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGuestCartResponse.ProtoReflect.Descriptor instead.
+func (*CreateGuestCartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateGuestCartResponse) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+type MergeCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CartToken     string                 `protobuf:"bytes,1,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeCartRequest) Reset() {
+	*x = MergeCartRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartRequest) ProtoMessage() {}
+
+func (x *MergeCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartRequest.ProtoReflect.Descriptor instead.
+func (*MergeCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MergeCartRequest) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+type MergeCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeCartResponse) Reset() {
+	*x = MergeCartResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartResponse) ProtoMessage() {}
+
+func (x *MergeCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartResponse.ProtoReflect.Descriptor instead.
+func (*MergeCartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MergeCartResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+type ApplyCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyCouponRequest) Reset() {
+	*x = ApplyCouponRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyCouponRequest) ProtoMessage() {}
+
+func (x *ApplyCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyCouponRequest.ProtoReflect.Descriptor instead.
+func (*ApplyCouponRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ApplyCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ApplyCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyCouponResponse) Reset() {
+	*x = ApplyCouponResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyCouponResponse) ProtoMessage() {}
+
+func (x *ApplyCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyCouponResponse.ProtoReflect.Descriptor instead.
+func (*ApplyCouponResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ApplyCouponResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+type RemoveCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCouponRequest) Reset() {
+	*x = RemoveCouponRequest{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCouponRequest) ProtoMessage() {}
+
+func (x *RemoveCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCouponRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCouponRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{25}
+}
+
+type RemoveCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCouponResponse) Reset() {
+	*x = RemoveCouponResponse{}
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCouponResponse) ProtoMessage() {}
+
+func (x *RemoveCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_cart_cart_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCouponResponse.ProtoReflect.Descriptor instead.
+func (*RemoveCouponResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_cart_cart_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RemoveCouponResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+var File_pkg_api_cart_cart_proto protoreflect.FileDescriptor
+
+const file_pkg_api_cart_cart_proto_rawDesc = "" +
+	"\n" +
+	"\x17pkg/api/cart/cart.proto\x1a pkg/google/api/annotations.proto\"\xb2\x03\n" +
+	"\vCartProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12!\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x05 \x01(\x03R\tunitPrice\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"line_total\x18\a \x01(\x03R\tlineTotal\x12,\n" +
+	"\x12current_unit_price\x18\b \x01(\x03R\x10currentUnitPrice\x12-\n" +
+	"\x12available_quantity\x18\t \x01(\x05R\x11availableQuantity\x12'\n" +
+	"\x0fproduct_deleted\x18\n" +
+	" \x01(\bR\x0eproductDeleted\x12-\n" +
+	"\x12insufficient_stock\x18\v \x01(\bR\x11insufficientStock\x12#\n" +
+	"\rprice_changed\x18\f \x01(\bR\fpriceChanged\"\xc8\x02\n" +
+	"\x04Cart\x12(\n" +
+	"\bproducts\x18\x02 \x03(\v2\f.CartProductR\bproducts\x12\x1f\n" +
+	"\vtotal_price\x18\x03 \x01(\x03R\n" +
+	"totalPrice\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bsubtotal\x18\x05 \x01(\x03R\bsubtotal\x12\x1a\n" +
+	"\bdiscount\x18\x06 \x01(\x03R\bdiscount\x12\x10\n" +
+	"\x03tax\x18\a \x01(\x03R\x03tax\x12\x1f\n" +
+	"\vgrand_total\x18\b \x01(\x03R\n" +
+	"grandTotal\x12&\n" +
+	"\x0fhas_stale_items\x18\t \x01(\bR\rhasStaleItems\x12\x1f\n" +
+	"\vcoupon_code\x18\n" +
+	" \x01(\tR\n" +
+	"couponCode\x12%\n" +
+	"\x0ecoupon_applied\x18\v \x01(\bR\rcouponApplied\"M\n" +
+	"\x10AddToCartRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"-\n" +
+	"\x11AddToCartResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"O\n" +
+	"\x12SetQuantityRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"/\n" +
+	"\x13SetQuantityResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"O\n" +
+	"\x18IncrementQuantityRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x05R\x05delta\"5\n" +
+	"\x19IncrementQuantityResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"E\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"2\n" +
+	"\x0fAddItemsRequest\x12\x1f\n" +
+	"\x05items\x18\x01 \x03(\v2\t.CartItemR\x05items\"-\n" +
+	"\x10AddItemsResponse\x12\x19\n" +
+	"\x04cart\x18\x01 \x01(\v2\x05.CartR\x04cart\"\x12\n" +
+	"\x10ClearCartRequest\"-\n" +
+	"\x11ClearCartResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"6\n" +
+	"\x15RemoveFromCartRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\"2\n" +
+	"\x16RemoveFromCartResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x10\n" +
+	"\x0eGetCartRequest\",\n" +
+	"\x0fGetCartResponse\x12\x19\n" +
+	"\x04cart\x18\x01 \x01(\v2\x05.CartR\x04cart\"o\n" +
+	"\x0fCheckoutRequest\x12.\n" +
+	"\x13shipping_address_id\x18\x01 \x01(\x03R\x11shippingAddressId\x12,\n" +
+	"\x12billing_address_id\x18\x02 \x01(\x03R\x10billingAddressId\",\n" +
+	"\x10CheckoutResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x18\n" +
+	"\x16CreateGuestCartRequest\"8\n" +
+	"\x17CreateGuestCartResponse\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x01 \x01(\tR\tcartToken\"1\n" +
+	"\x10MergeCartRequest\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x01 \x01(\tR\tcartToken\".\n" +
+	"\x11MergeCartResponse\x12\x19\n" +
+	"\x04cart\x18\x01 \x01(\v2\x05.CartR\x04cart\"(\n" +
+	"\x12ApplyCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"0\n" +
+	"\x13ApplyCouponResponse\x12\x19\n" +
+	"\x04cart\x18\x01 \x01(\v2\x05.CartR\x04cart\"\x15\n" +
+	"\x13RemoveCouponRequest\"1\n" +
+	"\x14RemoveCouponResponse\x12\x19\n" +
+	"\x04cart\x18\x01 \x01(\v2\x05.CartR\x04cart2\x96\b\n" +
 	"\vCartService\x12T\n" +
-	"\tAddToCart\x12\x11.AddToCartRequest\x1a\x12.AddToCartResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/cart/{product_id}\x12`\n" +
-	"\x0eRemoveFromCart\x12\x16.RemoveFromCartRequest\x1a\x17.RemoveFromCartResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/v1/cart/{product_id}\x12>\n" +
+	"\tAddToCart\x12\x11.AddToCartRequest\x1a\x12.AddToCartResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/cart/{product_id}\x12Z\n" +
+	"\vSetQuantity\x12\x13.SetQuantityRequest\x1a\x14.SetQuantityResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\x1a\x15/v1/cart/{product_id}\x12v\n" +
+	"\x11IncrementQuantity\x12\x19.IncrementQuantityRequest\x1a\x1a.IncrementQuantityResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/cart/{product_id}/increment\x12J\n" +
+	"\bAddItems\x12\x10.AddItemsRequest\x1a\x11.AddItemsResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/cart/items\x12`\n" +
+	"\x0eRemoveFromCart\x12\x16.RemoveFromCartRequest\x1a\x17.RemoveFromCartResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/v1/cart/{product_id}\x12D\n" +
+	"\tClearCart\x12\x11.ClearCartRequest\x1a\x12.ClearCartResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"*\b/v1/cart\x12>\n" +
 	"\aGetCart\x12\x0f.GetCartRequest\x1a\x10.GetCartResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
 	"\x12\b/v1/cart\x12M\n" +
-	"\bCheckout\x12\x10.CheckoutRequest\x1a\x11.CheckoutResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/cart/checkoutB5Z3github.com/sabirkekw/ecommerce_go/pkg/api/cart;cartb\x06proto3"
+	"\bCheckout\x12\x10.CheckoutRequest\x1a\x11.CheckoutResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/cart/checkout\x12_\n" +
+	"\x0fCreateGuestCart\x12\x17.CreateGuestCartRequest\x1a\x18.CreateGuestCartResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/cart/guest\x12M\n" +
+	"\tMergeCart\x12\x11.MergeCartRequest\x1a\x12.MergeCartResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/cart/merge\x12T\n" +
+	"\vApplyCoupon\x12\x13.ApplyCouponRequest\x1a\x14.ApplyCouponResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/cart/coupon\x12T\n" +
+	"\fRemoveCoupon\x12\x14.RemoveCouponRequest\x1a\x15.RemoveCouponResponse\"\x17\x82\xd3\xe4\x93\x02\x11*\x0f/v1/cart/couponB5Z3github.com/sabirkekw/ecommerce_go/pkg/api/cart;cartb\x06proto3"
 
 var (
 	file_pkg_api_cart_cart_proto_rawDescOnce sync.Once
@@ -526,35 +1518,73 @@ func file_pkg_api_cart_cart_proto_rawDescGZIP() []byte {
 	return file_pkg_api_cart_cart_proto_rawDescData
 }
 
-var file_pkg_api_cart_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_pkg_api_cart_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_pkg_api_cart_cart_proto_goTypes = []any{
-	(*CartProduct)(nil),            // 0: CartProduct
-	(*Cart)(nil),                   // 1: Cart
-	(*AddToCartRequest)(nil),       // 2: AddToCartRequest
-	(*AddToCartResponse)(nil),      // 3: AddToCartResponse
-	(*RemoveFromCartRequest)(nil),  // 4: RemoveFromCartRequest
-	(*RemoveFromCartResponse)(nil), // 5: RemoveFromCartResponse
-	(*GetCartRequest)(nil),         // 6: GetCartRequest
-	(*GetCartResponse)(nil),        // 7: GetCartResponse
-	(*CheckoutRequest)(nil),        // 8: CheckoutRequest
-	(*CheckoutResponse)(nil),       // 9: CheckoutResponse
+	(*CartProduct)(nil),               // 0: CartProduct
+	(*Cart)(nil),                      // 1: Cart
+	(*AddToCartRequest)(nil),          // 2: AddToCartRequest
+	(*AddToCartResponse)(nil),         // 3: AddToCartResponse
+	(*SetQuantityRequest)(nil),        // 4: SetQuantityRequest
+	(*SetQuantityResponse)(nil),       // 5: SetQuantityResponse
+	(*IncrementQuantityRequest)(nil),  // 6: IncrementQuantityRequest
+	(*IncrementQuantityResponse)(nil), // 7: IncrementQuantityResponse
+	(*CartItem)(nil),                  // 8: CartItem
+	(*AddItemsRequest)(nil),           // 9: AddItemsRequest
+	(*AddItemsResponse)(nil),          // 10: AddItemsResponse
+	(*ClearCartRequest)(nil),          // 11: ClearCartRequest
+	(*ClearCartResponse)(nil),         // 12: ClearCartResponse
+	(*RemoveFromCartRequest)(nil),     // 13: RemoveFromCartRequest
+	(*RemoveFromCartResponse)(nil),    // 14: RemoveFromCartResponse
+	(*GetCartRequest)(nil),            // 15: GetCartRequest
+	(*GetCartResponse)(nil),           // 16: GetCartResponse
+	(*CheckoutRequest)(nil),           // 17: CheckoutRequest
+	(*CheckoutResponse)(nil),          // 18: CheckoutResponse
+	(*CreateGuestCartRequest)(nil),    // 19: CreateGuestCartRequest
+	(*CreateGuestCartResponse)(nil),   // 20: CreateGuestCartResponse
+	(*MergeCartRequest)(nil),          // 21: MergeCartRequest
+	(*MergeCartResponse)(nil),         // 22: MergeCartResponse
+	(*ApplyCouponRequest)(nil),        // 23: ApplyCouponRequest
+	(*ApplyCouponResponse)(nil),       // 24: ApplyCouponResponse
+	(*RemoveCouponRequest)(nil),       // 25: RemoveCouponRequest
+	(*RemoveCouponResponse)(nil),      // 26: RemoveCouponResponse
 }
 var file_pkg_api_cart_cart_proto_depIdxs = []int32{
-	0, // 0: Cart.products:type_name -> CartProduct
-	1, // 1: GetCartResponse.cart:type_name -> Cart
-	2, // 2: CartService.AddToCart:input_type -> AddToCartRequest
-	4, // 3: CartService.RemoveFromCart:input_type -> RemoveFromCartRequest
-	6, // 4: CartService.GetCart:input_type -> GetCartRequest
-	8, // 5: CartService.Checkout:input_type -> CheckoutRequest
-	3, // 6: CartService.AddToCart:output_type -> AddToCartResponse
-	5, // 7: CartService.RemoveFromCart:output_type -> RemoveFromCartResponse
-	7, // 8: CartService.GetCart:output_type -> GetCartResponse
-	9, // 9: CartService.Checkout:output_type -> CheckoutResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: Cart.products:type_name -> CartProduct
+	8,  // 1: AddItemsRequest.items:type_name -> CartItem
+	1,  // 2: AddItemsResponse.cart:type_name -> Cart
+	1,  // 3: GetCartResponse.cart:type_name -> Cart
+	1,  // 4: MergeCartResponse.cart:type_name -> Cart
+	1,  // 5: ApplyCouponResponse.cart:type_name -> Cart
+	1,  // 6: RemoveCouponResponse.cart:type_name -> Cart
+	2,  // 7: CartService.AddToCart:input_type -> AddToCartRequest
+	4,  // 8: CartService.SetQuantity:input_type -> SetQuantityRequest
+	6,  // 9: CartService.IncrementQuantity:input_type -> IncrementQuantityRequest
+	9,  // 10: CartService.AddItems:input_type -> AddItemsRequest
+	13, // 11: CartService.RemoveFromCart:input_type -> RemoveFromCartRequest
+	11, // 12: CartService.ClearCart:input_type -> ClearCartRequest
+	15, // 13: CartService.GetCart:input_type -> GetCartRequest
+	17, // 14: CartService.Checkout:input_type -> CheckoutRequest
+	19, // 15: CartService.CreateGuestCart:input_type -> CreateGuestCartRequest
+	21, // 16: CartService.MergeCart:input_type -> MergeCartRequest
+	23, // 17: CartService.ApplyCoupon:input_type -> ApplyCouponRequest
+	25, // 18: CartService.RemoveCoupon:input_type -> RemoveCouponRequest
+	3,  // 19: CartService.AddToCart:output_type -> AddToCartResponse
+	5,  // 20: CartService.SetQuantity:output_type -> SetQuantityResponse
+	7,  // 21: CartService.IncrementQuantity:output_type -> IncrementQuantityResponse
+	10, // 22: CartService.AddItems:output_type -> AddItemsResponse
+	14, // 23: CartService.RemoveFromCart:output_type -> RemoveFromCartResponse
+	12, // 24: CartService.ClearCart:output_type -> ClearCartResponse
+	16, // 25: CartService.GetCart:output_type -> GetCartResponse
+	18, // 26: CartService.Checkout:output_type -> CheckoutResponse
+	20, // 27: CartService.CreateGuestCart:output_type -> CreateGuestCartResponse
+	22, // 28: CartService.MergeCart:output_type -> MergeCartResponse
+	24, // 29: CartService.ApplyCoupon:output_type -> ApplyCouponResponse
+	26, // 30: CartService.RemoveCoupon:output_type -> RemoveCouponResponse
+	19, // [19:31] is the sub-list for method output_type
+	7,  // [7:19] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_pkg_api_cart_cart_proto_init() }
@@ -568,7 +1598,7 @@ func file_pkg_api_cart_cart_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_api_cart_cart_proto_rawDesc), len(file_pkg_api_cart_cart_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,11 +22,87 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_PENDING     OrderStatus = 1
+	OrderStatus_ORDER_STATUS_PAID        OrderStatus = 2
+	OrderStatus_ORDER_STATUS_FULFILLED   OrderStatus = 3
+	OrderStatus_ORDER_STATUS_SHIPPED     OrderStatus = 4
+	OrderStatus_ORDER_STATUS_DELIVERED   OrderStatus = 5
+	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 6
+	OrderStatus_ORDER_STATUS_REFUNDED    OrderStatus = 7
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_PENDING",
+		2: "ORDER_STATUS_PAID",
+		3: "ORDER_STATUS_FULFILLED",
+		4: "ORDER_STATUS_SHIPPED",
+		5: "ORDER_STATUS_DELIVERED",
+		6: "ORDER_STATUS_CANCELLED",
+		7: "ORDER_STATUS_REFUNDED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_PENDING":     1,
+		"ORDER_STATUS_PAID":        2,
+		"ORDER_STATUS_FULFILLED":   3,
+		"ORDER_STATUS_SHIPPED":     4,
+		"ORDER_STATUS_DELIVERED":   5,
+		"ORDER_STATUS_CANCELLED":   6,
+		"ORDER_STATUS_REFUNDED":    7,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_api_order_order_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_pkg_api_order_order_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{0}
+}
+
 type SingleOrder struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Products      []*ProductData         `protobuf:"bytes,3,rep,name=products,proto3" json:"products,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId   int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Products []*ProductData         `protobuf:"bytes,3,rep,name=products,proto3" json:"products,omitempty"`
+	// line totals minus discount
+	TotalPrice int64       `protobuf:"varint,4,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Currency   string      `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status     OrderStatus `protobuf:"varint,6,opt,name=status,proto3,enum=OrderStatus" json:"status,omitempty"`
+	// unix seconds
+	CreatedAt int64 `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// copied from the address book at checkout, unset for older orders
+	ShippingAddress *Address `protobuf:"bytes,8,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	BillingAddress  *Address `protobuf:"bytes,9,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"`
+	// coupon applied at checkout, empty if there was none
+	CouponCode    string `protobuf:"bytes,10,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	Discount      int64  `protobuf:"varint,11,opt,name=discount,proto3" json:"discount,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,17 +158,178 @@ func (x *SingleOrder) GetProducts() []*ProductData {
 	return nil
 }
 
-type ProductData struct {
+func (x *SingleOrder) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *SingleOrder) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SingleOrder) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *SingleOrder) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *SingleOrder) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *SingleOrder) GetBillingAddress() *Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
+func (x *SingleOrder) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *SingleOrder) GetDiscount() int64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+type Address struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	RecipientName string                 `protobuf:"bytes,1,opt,name=recipient_name,json=recipientName,proto3" json:"recipient_name,omitempty"`
+	Line1         string                 `protobuf:"bytes,2,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,3,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	// ISO 3166-1 alpha-2
+	Country       string `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	Phone         string `protobuf:"bytes,8,opt,name=phone,proto3" json:"phone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetRecipientName() string {
+	if x != nil {
+		return x.RecipientName
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Address) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type ProductData struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// price in minor units the customer paid per item
+	UnitPrice     int64  `protobuf:"varint,3,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	LineTotal     int64  `protobuf:"varint,5,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductData) Reset() {
 	*x = ProductData{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[1]
+	mi := &file_pkg_api_order_order_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -104,7 +341,7 @@ func (x *ProductData) String() string {
 func (*ProductData) ProtoMessage() {}
 
 func (x *ProductData) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[1]
+	mi := &file_pkg_api_order_order_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -117,7 +354,7 @@ func (x *ProductData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductData.ProtoReflect.Descriptor instead.
 func (*ProductData) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{1}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ProductData) GetProductId() int32 {
@@ -134,6 +371,27 @@ func (x *ProductData) GetQuantity() int32 {
 	return 0
 }
 
+func (x *ProductData) GetUnitPrice() int64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *ProductData) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ProductData) GetLineTotal() int64 {
+	if x != nil {
+		return x.LineTotal
+	}
+	return 0
+}
+
 type GetOrdersByUserIDRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -142,7 +400,7 @@ type GetOrdersByUserIDRequest struct {
 
 func (x *GetOrdersByUserIDRequest) Reset() {
 	*x = GetOrdersByUserIDRequest{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[2]
+	mi := &file_pkg_api_order_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -154,7 +412,7 @@ func (x *GetOrdersByUserIDRequest) String() string {
 func (*GetOrdersByUserIDRequest) ProtoMessage() {}
 
 func (x *GetOrdersByUserIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[2]
+	mi := &file_pkg_api_order_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -167,7 +425,7 @@ func (x *GetOrdersByUserIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrdersByUserIDRequest.ProtoReflect.Descriptor instead.
 func (*GetOrdersByUserIDRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{2}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{3}
 }
 
 type GetOrdersByUserIDResponse struct {
@@ -179,7 +437,7 @@ type GetOrdersByUserIDResponse struct {
 
 func (x *GetOrdersByUserIDResponse) Reset() {
 	*x = GetOrdersByUserIDResponse{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[3]
+	mi := &file_pkg_api_order_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -191,7 +449,7 @@ func (x *GetOrdersByUserIDResponse) String() string {
 func (*GetOrdersByUserIDResponse) ProtoMessage() {}
 
 func (x *GetOrdersByUserIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[3]
+	mi := &file_pkg_api_order_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -204,7 +462,7 @@ func (x *GetOrdersByUserIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrdersByUserIDResponse.ProtoReflect.Descriptor instead.
 func (*GetOrdersByUserIDResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{3}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetOrdersByUserIDResponse) GetOrders() []*SingleOrder {
@@ -223,7 +481,7 @@ type GetOrderByIDRequest struct {
 
 func (x *GetOrderByIDRequest) Reset() {
 	*x = GetOrderByIDRequest{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[4]
+	mi := &file_pkg_api_order_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -235,7 +493,7 @@ func (x *GetOrderByIDRequest) String() string {
 func (*GetOrderByIDRequest) ProtoMessage() {}
 
 func (x *GetOrderByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[4]
+	mi := &file_pkg_api_order_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -248,7 +506,7 @@ func (x *GetOrderByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderByIDRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{4}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderByIDRequest) GetOrderId() int32 {
@@ -267,7 +525,7 @@ type GetOrderByIDResponse struct {
 
 func (x *GetOrderByIDResponse) Reset() {
 	*x = GetOrderByIDResponse{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[5]
+	mi := &file_pkg_api_order_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -279,7 +537,7 @@ func (x *GetOrderByIDResponse) String() string {
 func (*GetOrderByIDResponse) ProtoMessage() {}
 
 func (x *GetOrderByIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[5]
+	mi := &file_pkg_api_order_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -292,7 +550,7 @@ func (x *GetOrderByIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderByIDResponse.ProtoReflect.Descriptor instead.
 func (*GetOrderByIDResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{5}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetOrderByIDResponse) GetOrder() *SingleOrder {
@@ -311,7 +569,7 @@ type DeleteOrderRequest struct {
 
 func (x *DeleteOrderRequest) Reset() {
 	*x = DeleteOrderRequest{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[6]
+	mi := &file_pkg_api_order_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -323,7 +581,7 @@ func (x *DeleteOrderRequest) String() string {
 func (*DeleteOrderRequest) ProtoMessage() {}
 
 func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[6]
+	mi := &file_pkg_api_order_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -336,7 +594,7 @@ func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOrderRequest.ProtoReflect.Descriptor instead.
 func (*DeleteOrderRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{6}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteOrderRequest) GetOrderId() int32 {
@@ -354,7 +612,7 @@ type DeleteOrderResponse struct {
 
 func (x *DeleteOrderResponse) Reset() {
 	*x = DeleteOrderResponse{}
-	mi := &file_pkg_api_order_order_proto_msgTypes[7]
+	mi := &file_pkg_api_order_order_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -366,7 +624,7 @@ func (x *DeleteOrderResponse) String() string {
 func (*DeleteOrderResponse) ProtoMessage() {}
 
 func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_order_order_proto_msgTypes[7]
+	mi := &file_pkg_api_order_order_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -379,37 +637,469 @@ func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOrderResponse.ProtoReflect.Descriptor instead.
 func (*DeleteOrderResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{7}
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{8}
+}
+
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int32                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=OrderStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateOrderStatusRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type UpdateOrderStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *SingleOrder           `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusResponse) Reset() {
+	*x = UpdateOrderStatusResponse{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusResponse) ProtoMessage() {}
+
+func (x *UpdateOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateOrderStatusResponse) GetOrder() *SingleOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// DeadLetter is a checkout message the consumer failed to turn into an order.
+type DeadLetter struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId string                 `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// original checkout message as published by cart-service
+	Payload  []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Error    string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Attempts int32  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// unix seconds
+	CreatedAt int64 `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// unix seconds, 0 if not replayed yet
+	ReplayedAt    int64 `protobuf:"varint,7,opt,name=replayed_at,json=replayedAt,proto3" json:"replayed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeadLetter) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetReplayedAt() int64 {
+	if x != nil {
+		return x.ReplayedAt
+	}
+	return 0
+}
+
+type ListDeadLettersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// defaults to 50, capped at 500
+	Limit           uint32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	IncludeReplayed bool   `protobuf:"varint,2,opt,name=include_replayed,json=includeReplayed,proto3" json:"include_replayed,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListDeadLettersRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDeadLettersRequest) GetIncludeReplayed() bool {
+	if x != nil {
+		return x.IncludeReplayed
+	}
+	return false
+}
+
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+type ReplayDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeadLetterRequest) Reset() {
+	*x = ReplayDeadLetterRequest{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeadLetterRequest) ProtoMessage() {}
+
+func (x *ReplayDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReplayDeadLetterRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ReplayDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeadLetterResponse) Reset() {
+	*x = ReplayDeadLetterResponse{}
+	mi := &file_pkg_api_order_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeadLetterResponse) ProtoMessage() {}
+
+func (x *ReplayDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_order_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_order_order_proto_rawDescGZIP(), []int{15}
 }
 
 var File_pkg_api_order_order_proto protoreflect.FileDescriptor
 
 const file_pkg_api_order_order_proto_rawDesc = "" +
 	"\n" +
-	"\x19pkg/api/order/order.proto\x12\x03api\x1a pkg/google/api/annotations.proto\"d\n" +
+	"\x19pkg/api/order/order.proto\x1a pkg/google/api/annotations.proto\"\x87\x03\n" +
 	"\vSingleOrder\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12,\n" +
-	"\bproducts\x18\x03 \x03(\v2\x10.api.ProductDataR\bproducts\"H\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12(\n" +
+	"\bproducts\x18\x03 \x03(\v2\f.ProductDataR\bproducts\x12\x1f\n" +
+	"\vtotal_price\x18\x04 \x01(\x03R\n" +
+	"totalPrice\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12$\n" +
+	"\x06status\x18\x06 \x01(\x0e2\f.OrderStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x123\n" +
+	"\x10shipping_address\x18\b \x01(\v2\b.AddressR\x0fshippingAddress\x121\n" +
+	"\x0fbilling_address\x18\t \x01(\v2\b.AddressR\x0ebillingAddress\x12\x1f\n" +
+	"\vcoupon_code\x18\n" +
+	" \x01(\tR\n" +
+	"couponCode\x12\x1a\n" +
+	"\bdiscount\x18\v \x01(\x03R\bdiscount\"\xd9\x01\n" +
+	"\aAddress\x12%\n" +
+	"\x0erecipient_name\x18\x01 \x01(\tR\rrecipientName\x12\x14\n" +
+	"\x05line1\x18\x02 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x03 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12\x14\n" +
+	"\x05phone\x18\b \x01(\tR\x05phone\"\xa2\x01\n" +
 	"\vProductData\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\x1a\n" +
-	"\x18GetOrdersByUserIDRequest\"E\n" +
-	"\x19GetOrdersByUserIDResponse\x12(\n" +
-	"\x06orders\x18\x01 \x03(\v2\x10.api.SingleOrderR\x06orders\"0\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x03 \x01(\x03R\tunitPrice\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"line_total\x18\x05 \x01(\x03R\tlineTotal\"\x1a\n" +
+	"\x18GetOrdersByUserIDRequest\"A\n" +
+	"\x19GetOrdersByUserIDResponse\x12$\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.SingleOrderR\x06orders\"0\n" +
 	"\x13GetOrderByIDRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\x05R\aorderId\">\n" +
-	"\x14GetOrderByIDResponse\x12&\n" +
-	"\x05order\x18\x01 \x01(\v2\x10.api.SingleOrderR\x05order\"/\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\":\n" +
+	"\x14GetOrderByIDResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.SingleOrderR\x05order\"/\n" +
 	"\x12DeleteOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x05R\aorderId\"\x15\n" +
-	"\x13DeleteOrderResponse2\xbb\x02\n" +
-	"\fOrderService\x12f\n" +
-	"\x11GetOrdersByUserID\x12\x1d.api.GetOrdersByUserIDRequest\x1a\x1e.api.GetOrdersByUserIDResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
-	"/v1/orders\x12_\n" +
-	"\vDeleteOrder\x12\x17.api.DeleteOrderRequest\x1a\x18.api.DeleteOrderResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/v1/orders/{order_id}\x12b\n" +
-	"\fGetOrderByID\x12\x18.api.GetOrderByIDRequest\x1a\x19.api.GetOrderByIDResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/orders/{order_id}B7Z5github.com/sabirkekw/ecommerce_go/pkg/api/order;orderb\x06proto3"
+	"\x13DeleteOrderResponse\"[\n" +
+	"\x18UpdateOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x05R\aorderId\x12$\n" +
+	"\x06status\x18\x02 \x01(\x0e2\f.OrderStatusR\x06status\"?\n" +
+	"\x19UpdateOrderStatusResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.SingleOrderR\x05order\"\xc3\x01\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1f\n" +
+	"\vreplayed_at\x18\a \x01(\x03R\n" +
+	"replayedAt\"Y\n" +
+	"\x16ListDeadLettersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\rR\x05limit\x12)\n" +
+	"\x10include_replayed\x18\x02 \x01(\bR\x0fincludeReplayed\"I\n" +
+	"\x17ListDeadLettersResponse\x12.\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\v.DeadLetterR\vdeadLetters\")\n" +
+	"\x17ReplayDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x1a\n" +
+	"\x18ReplayDeadLetterResponse*\xe5\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ORDER_STATUS_PENDING\x10\x01\x12\x15\n" +
+	"\x11ORDER_STATUS_PAID\x10\x02\x12\x1a\n" +
+	"\x16ORDER_STATUS_FULFILLED\x10\x03\x12\x18\n" +
+	"\x14ORDER_STATUS_SHIPPED\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_DELIVERED\x10\x05\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x06\x12\x19\n" +
+	"\x15ORDER_STATUS_REFUNDED\x10\a2\xfe\x03\n" +
+	"\fOrderService\x12^\n" +
+	"\x11GetOrdersByUserID\x12\x19.GetOrdersByUserIDRequest\x1a\x1a.GetOrdersByUserIDResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/orders\x12W\n" +
+	"\vDeleteOrder\x12\x13.DeleteOrderRequest\x1a\x14.DeleteOrderResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/v1/orders/{order_id}\x12Z\n" +
+	"\fGetOrderByID\x12\x14.GetOrderByIDRequest\x1a\x15.GetOrderByIDResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/orders/{order_id}\x12J\n" +
+	"\x11UpdateOrderStatus\x12\x19.UpdateOrderStatusRequest\x1a\x1a.UpdateOrderStatusResponse\x12D\n" +
+	"\x0fListDeadLetters\x12\x17.ListDeadLettersRequest\x1a\x18.ListDeadLettersResponse\x12G\n" +
+	"\x10ReplayDeadLetter\x12\x18.ReplayDeadLetterRequest\x1a\x19.ReplayDeadLetterResponseB7Z5github.com/sabirkekw/ecommerce_go/pkg/api/order;orderb\x06proto3"
 
 var (
 	file_pkg_api_order_order_proto_rawDescOnce sync.Once
@@ -423,32 +1113,54 @@ func file_pkg_api_order_order_proto_rawDescGZIP() []byte {
 	return file_pkg_api_order_order_proto_rawDescData
 }
 
-var file_pkg_api_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_pkg_api_order_order_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_api_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_pkg_api_order_order_proto_goTypes = []any{
-	(*SingleOrder)(nil),               // 0: api.SingleOrder
-	(*ProductData)(nil),               // 1: api.ProductData
-	(*GetOrdersByUserIDRequest)(nil),  // 2: api.GetOrdersByUserIDRequest
-	(*GetOrdersByUserIDResponse)(nil), // 3: api.GetOrdersByUserIDResponse
-	(*GetOrderByIDRequest)(nil),       // 4: api.GetOrderByIDRequest
-	(*GetOrderByIDResponse)(nil),      // 5: api.GetOrderByIDResponse
-	(*DeleteOrderRequest)(nil),        // 6: api.DeleteOrderRequest
-	(*DeleteOrderResponse)(nil),       // 7: api.DeleteOrderResponse
+	(OrderStatus)(0),                  // 0: OrderStatus
+	(*SingleOrder)(nil),               // 1: SingleOrder
+	(*Address)(nil),                   // 2: Address
+	(*ProductData)(nil),               // 3: ProductData
+	(*GetOrdersByUserIDRequest)(nil),  // 4: GetOrdersByUserIDRequest
+	(*GetOrdersByUserIDResponse)(nil), // 5: GetOrdersByUserIDResponse
+	(*GetOrderByIDRequest)(nil),       // 6: GetOrderByIDRequest
+	(*GetOrderByIDResponse)(nil),      // 7: GetOrderByIDResponse
+	(*DeleteOrderRequest)(nil),        // 8: DeleteOrderRequest
+	(*DeleteOrderResponse)(nil),       // 9: DeleteOrderResponse
+	(*UpdateOrderStatusRequest)(nil),  // 10: UpdateOrderStatusRequest
+	(*UpdateOrderStatusResponse)(nil), // 11: UpdateOrderStatusResponse
+	(*DeadLetter)(nil),                // 12: DeadLetter
+	(*ListDeadLettersRequest)(nil),    // 13: ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),   // 14: ListDeadLettersResponse
+	(*ReplayDeadLetterRequest)(nil),   // 15: ReplayDeadLetterRequest
+	(*ReplayDeadLetterResponse)(nil),  // 16: ReplayDeadLetterResponse
 }
 var file_pkg_api_order_order_proto_depIdxs = []int32{
-	1, // 0: api.SingleOrder.products:type_name -> api.ProductData
-	0, // 1: api.GetOrdersByUserIDResponse.orders:type_name -> api.SingleOrder
-	0, // 2: api.GetOrderByIDResponse.order:type_name -> api.SingleOrder
-	2, // 3: api.OrderService.GetOrdersByUserID:input_type -> api.GetOrdersByUserIDRequest
-	6, // 4: api.OrderService.DeleteOrder:input_type -> api.DeleteOrderRequest
-	4, // 5: api.OrderService.GetOrderByID:input_type -> api.GetOrderByIDRequest
-	3, // 6: api.OrderService.GetOrdersByUserID:output_type -> api.GetOrdersByUserIDResponse
-	7, // 7: api.OrderService.DeleteOrder:output_type -> api.DeleteOrderResponse
-	5, // 8: api.OrderService.GetOrderByID:output_type -> api.GetOrderByIDResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3,  // 0: SingleOrder.products:type_name -> ProductData
+	0,  // 1: SingleOrder.status:type_name -> OrderStatus
+	2,  // 2: SingleOrder.shipping_address:type_name -> Address
+	2,  // 3: SingleOrder.billing_address:type_name -> Address
+	1,  // 4: GetOrdersByUserIDResponse.orders:type_name -> SingleOrder
+	1,  // 5: GetOrderByIDResponse.order:type_name -> SingleOrder
+	0,  // 6: UpdateOrderStatusRequest.status:type_name -> OrderStatus
+	1,  // 7: UpdateOrderStatusResponse.order:type_name -> SingleOrder
+	12, // 8: ListDeadLettersResponse.dead_letters:type_name -> DeadLetter
+	4,  // 9: OrderService.GetOrdersByUserID:input_type -> GetOrdersByUserIDRequest
+	8,  // 10: OrderService.DeleteOrder:input_type -> DeleteOrderRequest
+	6,  // 11: OrderService.GetOrderByID:input_type -> GetOrderByIDRequest
+	10, // 12: OrderService.UpdateOrderStatus:input_type -> UpdateOrderStatusRequest
+	13, // 13: OrderService.ListDeadLetters:input_type -> ListDeadLettersRequest
+	15, // 14: OrderService.ReplayDeadLetter:input_type -> ReplayDeadLetterRequest
+	5,  // 15: OrderService.GetOrdersByUserID:output_type -> GetOrdersByUserIDResponse
+	9,  // 16: OrderService.DeleteOrder:output_type -> DeleteOrderResponse
+	7,  // 17: OrderService.GetOrderByID:output_type -> GetOrderByIDResponse
+	11, // 18: OrderService.UpdateOrderStatus:output_type -> UpdateOrderStatusResponse
+	14, // 19: OrderService.ListDeadLetters:output_type -> ListDeadLettersResponse
+	16, // 20: OrderService.ReplayDeadLetter:output_type -> ReplayDeadLetterResponse
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_pkg_api_order_order_proto_init() }
@@ -461,13 +1173,14 @@ func file_pkg_api_order_order_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_api_order_order_proto_rawDesc), len(file_pkg_api_order_order_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_api_order_order_proto_goTypes,
 		DependencyIndexes: file_pkg_api_order_order_proto_depIdxs,
+		EnumInfos:         file_pkg_api_order_order_proto_enumTypes,
 		MessageInfos:      file_pkg_api_order_order_proto_msgTypes,
 	}.Build()
 	File_pkg_api_order_order_proto = out.File
@@ -22,6 +22,122 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED   SortOrder = 0
+	SortOrder_SORT_ORDER_ID_ASC        SortOrder = 1
+	SortOrder_SORT_ORDER_ID_DESC       SortOrder = 2
+	SortOrder_SORT_ORDER_NAME_ASC      SortOrder = 3
+	SortOrder_SORT_ORDER_NAME_DESC     SortOrder = 4
+	SortOrder_SORT_ORDER_QUANTITY_ASC  SortOrder = 5
+	SortOrder_SORT_ORDER_QUANTITY_DESC SortOrder = 6
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ID_ASC",
+		2: "SORT_ORDER_ID_DESC",
+		3: "SORT_ORDER_NAME_ASC",
+		4: "SORT_ORDER_NAME_DESC",
+		5: "SORT_ORDER_QUANTITY_ASC",
+		6: "SORT_ORDER_QUANTITY_DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED":   0,
+		"SORT_ORDER_ID_ASC":        1,
+		"SORT_ORDER_ID_DESC":       2,
+		"SORT_ORDER_NAME_ASC":      3,
+		"SORT_ORDER_NAME_DESC":     4,
+		"SORT_ORDER_QUANTITY_ASC":  5,
+		"SORT_ORDER_QUANTITY_DESC": 6,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_api_products_products_proto_enumTypes[0].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_pkg_api_products_products_proto_enumTypes[0]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{0}
+}
+
+type PromotionKind int32
+
+const (
+	PromotionKind_PROMOTION_KIND_UNSPECIFIED PromotionKind = 0
+	// percent_off percent off the subtotal
+	PromotionKind_PROMOTION_KIND_PERCENTAGE PromotionKind = 1
+	// amount_off off the subtotal
+	PromotionKind_PROMOTION_KIND_FIXED_AMOUNT PromotionKind = 2
+	// get_quantity of product_id free for every buy_quantity bought
+	PromotionKind_PROMOTION_KIND_BUY_X_GET_Y PromotionKind = 3
+)
+
+// Enum value maps for PromotionKind.
+var (
+	PromotionKind_name = map[int32]string{
+		0: "PROMOTION_KIND_UNSPECIFIED",
+		1: "PROMOTION_KIND_PERCENTAGE",
+		2: "PROMOTION_KIND_FIXED_AMOUNT",
+		3: "PROMOTION_KIND_BUY_X_GET_Y",
+	}
+	PromotionKind_value = map[string]int32{
+		"PROMOTION_KIND_UNSPECIFIED":  0,
+		"PROMOTION_KIND_PERCENTAGE":   1,
+		"PROMOTION_KIND_FIXED_AMOUNT": 2,
+		"PROMOTION_KIND_BUY_X_GET_Y":  3,
+	}
+)
+
+func (x PromotionKind) Enum() *PromotionKind {
+	p := new(PromotionKind)
+	*p = x
+	return p
+}
+
+func (x PromotionKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PromotionKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_api_products_products_proto_enumTypes[1].Descriptor()
+}
+
+func (PromotionKind) Type() protoreflect.EnumType {
+	return &file_pkg_api_products_products_proto_enumTypes[1]
+}
+
+func (x PromotionKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PromotionKind.Descriptor instead.
+func (PromotionKind) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{1}
+}
+
 type GetProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -110,15 +226,112 @@ func (x *GetProductResponse) GetProduct() *Product {
 	return nil
 }
 
-type ListProductsRequest struct {
+type GetProductsByIDsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// max 100
+	Ids           []int32 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsByIDsRequest) Reset() {
+	*x = GetProductsByIDsRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsByIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsByIDsRequest) ProtoMessage() {}
+
+func (x *GetProductsByIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsByIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProductsByIDsRequest) GetIds() []int32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetProductsByIDsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsByIDsResponse) Reset() {
+	*x = GetProductsByIDsResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsByIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsByIDsResponse) ProtoMessage() {}
+
+func (x *GetProductsByIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsByIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetProductsByIDsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type ListProductsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// max 100, defaults to 20
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous response
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// case-insensitive substring of the product name
+	NameFilter    string    `protobuf:"bytes,3,opt,name=name_filter,json=nameFilter,proto3" json:"name_filter,omitempty"`
+	InStockOnly   bool      `protobuf:"varint,4,opt,name=in_stock_only,json=inStockOnly,proto3" json:"in_stock_only,omitempty"`
+	SortOrder     SortOrder `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,proto3,enum=SortOrder" json:"sort_order,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_pkg_api_products_products_proto_msgTypes[2]
+	mi := &file_pkg_api_products_products_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -130,7 +343,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_products_products_proto_msgTypes[2]
+	mi := &file_pkg_api_products_products_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -143,19 +356,56 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{2}
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetNameFilter() string {
+	if x != nil {
+		return x.NameFilter
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetInStockOnly() bool {
+	if x != nil {
+		return x.InStockOnly
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
 }
 
 type ListProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	// empty when there are no more pages
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListProductsResponse) Reset() {
 	*x = ListProductsResponse{}
-	mi := &file_pkg_api_products_products_proto_msgTypes[3]
+	mi := &file_pkg_api_products_products_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -167,7 +417,7 @@ func (x *ListProductsResponse) String() string {
 func (*ListProductsResponse) ProtoMessage() {}
 
 func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_products_products_proto_msgTypes[3]
+	mi := &file_pkg_api_products_products_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -180,7 +430,7 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
 func (*ListProductsResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{3}
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListProductsResponse) GetProducts() []*Product {
@@ -190,6 +440,101 @@ func (x *ListProductsResponse) GetProducts() []*Product {
 	return nil
 }
 
+func (x *ListProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CreateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateProductRequest) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type CreateProductResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CreatedProduct *Product               `protobuf:"bytes,1,opt,name=createdProduct,proto3" json:"createdProduct,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateProductResponse) Reset() {
+	*x = CreateProductResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductResponse) ProtoMessage() {}
+
+func (x *CreateProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductResponse.ProtoReflect.Descriptor instead.
+func (*CreateProductResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateProductResponse) GetCreatedProduct() *Product {
+	if x != nil {
+		return x.CreatedProduct
+	}
+	return nil
+}
+
 type UpdateProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -200,7 +545,7 @@ type UpdateProductRequest struct {
 
 func (x *UpdateProductRequest) Reset() {
 	*x = UpdateProductRequest{}
-	mi := &file_pkg_api_products_products_proto_msgTypes[4]
+	mi := &file_pkg_api_products_products_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -212,7 +557,7 @@ func (x *UpdateProductRequest) String() string {
 func (*UpdateProductRequest) ProtoMessage() {}
 
 func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_products_products_proto_msgTypes[4]
+	mi := &file_pkg_api_products_products_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -225,7 +570,7 @@ func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
 func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{4}
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateProductRequest) GetId() int32 {
@@ -251,7 +596,7 @@ type UpdateProductResponse struct {
 
 func (x *UpdateProductResponse) Reset() {
 	*x = UpdateProductResponse{}
-	mi := &file_pkg_api_products_products_proto_msgTypes[5]
+	mi := &file_pkg_api_products_products_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -263,7 +608,7 @@ func (x *UpdateProductResponse) String() string {
 func (*UpdateProductResponse) ProtoMessage() {}
 
 func (x *UpdateProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_products_products_proto_msgTypes[5]
+	mi := &file_pkg_api_products_products_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -276,7 +621,7 @@ func (x *UpdateProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProductResponse.ProtoReflect.Descriptor instead.
 func (*UpdateProductResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{5}
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UpdateProductResponse) GetUpdatedProduct() *Product {
@@ -286,31 +631,28 @@ func (x *UpdateProductResponse) GetUpdatedProduct() *Product {
 	return nil
 }
 
-type Product struct {
+type DeleteProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) Reset() {
-	*x = Product{}
-	mi := &file_pkg_api_products_products_proto_msgTypes[6]
+func (x *DeleteProductRequest) Reset() {
+	*x = DeleteProductRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Product) String() string {
+func (x *DeleteProductRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Product) ProtoMessage() {}
+func (*DeleteProductRequest) ProtoMessage() {}
 
-func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_api_products_products_proto_msgTypes[6]
+func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -321,65 +663,1293 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Product.ProtoReflect.Descriptor instead.
-func (*Product) Descriptor() ([]byte, []int) {
-	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Product) GetId() int32 {
+func (x *DeleteProductRequest) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *Product) GetProductName() string {
-	if x != nil {
-		return x.ProductName
-	}
-	return ""
+type DeleteProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) GetQuantity() int32 {
-	if x != nil {
-		return x.Quantity
-	}
-	return 0
+func (x *DeleteProductResponse) Reset() {
+	*x = DeleteProductResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Product) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
+func (x *DeleteProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductResponse) ProtoMessage() {}
+
+func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProductResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{11}
+}
+
+type Product struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductName string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Quantity    int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// in minor units of currency, e.g. cents
+	Price int64 `protobuf:"varint,5,opt,name=price,proto3" json:"price,omitempty"`
+	// ISO 4217 code, e.g. USD
+	Currency      string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Product) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Product) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *Product) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Product) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type StockItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int32                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockItem) Reset() {
+	*x = StockItem{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockItem) ProtoMessage() {}
+
+func (x *StockItem) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StockItem) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int32                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	// unix seconds, the reservation is released automatically afterwards
+	ExpiresAt     int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReserveStockResponse) GetReservationId() int32 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+func (x *ReserveStockResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int32                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CommitReservationRequest) GetReservationId() int32 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+type CommitReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationResponse) Reset() {
+	*x = CommitReservationResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationResponse) ProtoMessage() {}
+
+func (x *CommitReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationResponse.ProtoReflect.Descriptor instead.
+func (*CommitReservationResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{17}
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int32                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReleaseReservationRequest) GetReservationId() int32 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+type ReleaseReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationResponse) Reset() {
+	*x = ReleaseReservationResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationResponse) ProtoMessage() {}
+
+func (x *ReleaseReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{19}
+}
+
+type RestockReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int32                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockReservationRequest) Reset() {
+	*x = RestockReservationRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockReservationRequest) ProtoMessage() {}
+
+func (x *RestockReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockReservationRequest.ProtoReflect.Descriptor instead.
+func (*RestockReservationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RestockReservationRequest) GetReservationId() int32 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+type RestockReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockReservationResponse) Reset() {
+	*x = RestockReservationResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockReservationResponse) ProtoMessage() {}
+
+func (x *RestockReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockReservationResponse.ProtoReflect.Descriptor instead.
+func (*RestockReservationResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{21}
+}
+
+type Promotion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// coupon code, case-insensitive
+	Code       string        `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Kind       PromotionKind `protobuf:"varint,3,opt,name=kind,proto3,enum=PromotionKind" json:"kind,omitempty"`
+	PercentOff int32         `protobuf:"varint,4,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`
+	// in minor units of currency
+	AmountOff int64 `protobuf:"varint,5,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`
+	// ISO 4217 code, required with amount_off or min_subtotal. Carts in other currencies don't qualify
+	Currency    string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	ProductId   int32  `protobuf:"varint,7,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	BuyQuantity int32  `protobuf:"varint,8,opt,name=buy_quantity,json=buyQuantity,proto3" json:"buy_quantity,omitempty"`
+	GetQuantity int32  `protobuf:"varint,9,opt,name=get_quantity,json=getQuantity,proto3" json:"get_quantity,omitempty"`
+	// in minor units of currency, 0 for none
+	MinSubtotal int64 `protobuf:"varint,10,opt,name=min_subtotal,json=minSubtotal,proto3" json:"min_subtotal,omitempty"`
+	// orders a user can place with the coupon, 0 for no limit
+	MaxRedemptionsPerUser int32 `protobuf:"varint,11,opt,name=max_redemptions_per_user,json=maxRedemptionsPerUser,proto3" json:"max_redemptions_per_user,omitempty"`
+	// unix seconds, 0 for none
+	StartsAt int64 `protobuf:"varint,12,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt   int64 `protobuf:"varint,13,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	// set once the promotion is disabled
+	DisabledAt    int64 `protobuf:"varint,14,opt,name=disabled_at,json=disabledAt,proto3" json:"disabled_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Promotion) Reset() {
+	*x = Promotion{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Promotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Promotion) ProtoMessage() {}
+
+func (x *Promotion) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Promotion.ProtoReflect.Descriptor instead.
+func (*Promotion) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Promotion) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Promotion) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Promotion) GetKind() PromotionKind {
+	if x != nil {
+		return x.Kind
+	}
+	return PromotionKind_PROMOTION_KIND_UNSPECIFIED
+}
+
+func (x *Promotion) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *Promotion) GetAmountOff() int64 {
+	if x != nil {
+		return x.AmountOff
+	}
+	return 0
+}
+
+func (x *Promotion) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Promotion) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *Promotion) GetBuyQuantity() int32 {
+	if x != nil {
+		return x.BuyQuantity
+	}
+	return 0
+}
+
+func (x *Promotion) GetGetQuantity() int32 {
+	if x != nil {
+		return x.GetQuantity
+	}
+	return 0
+}
+
+func (x *Promotion) GetMinSubtotal() int64 {
+	if x != nil {
+		return x.MinSubtotal
+	}
+	return 0
+}
+
+func (x *Promotion) GetMaxRedemptionsPerUser() int32 {
+	if x != nil {
+		return x.MaxRedemptionsPerUser
+	}
+	return 0
+}
+
+func (x *Promotion) GetStartsAt() int64 {
+	if x != nil {
+		return x.StartsAt
+	}
+	return 0
+}
+
+func (x *Promotion) GetEndsAt() int64 {
+	if x != nil {
+		return x.EndsAt
+	}
+	return 0
+}
+
+func (x *Promotion) GetDisabledAt() int64 {
+	if x != nil {
+		return x.DisabledAt
+	}
+	return 0
+}
+
+type CreatePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotion     *Promotion             `protobuf:"bytes,1,opt,name=promotion,proto3" json:"promotion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromotionRequest) Reset() {
+	*x = CreatePromotionRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromotionRequest) ProtoMessage() {}
+
+func (x *CreatePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromotionRequest.ProtoReflect.Descriptor instead.
+func (*CreatePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreatePromotionRequest) GetPromotion() *Promotion {
+	if x != nil {
+		return x.Promotion
+	}
+	return nil
+}
+
+type CreatePromotionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotion     *Promotion             `protobuf:"bytes,1,opt,name=promotion,proto3" json:"promotion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromotionResponse) Reset() {
+	*x = CreatePromotionResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromotionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromotionResponse) ProtoMessage() {}
+
+func (x *CreatePromotionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromotionResponse.ProtoReflect.Descriptor instead.
+func (*CreatePromotionResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreatePromotionResponse) GetPromotion() *Promotion {
+	if x != nil {
+		return x.Promotion
+	}
+	return nil
+}
+
+type ListPromotionsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IncludeDisabled bool                   `protobuf:"varint,1,opt,name=include_disabled,json=includeDisabled,proto3" json:"include_disabled,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListPromotionsRequest) Reset() {
+	*x = ListPromotionsRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsRequest) ProtoMessage() {}
+
+func (x *ListPromotionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsRequest.ProtoReflect.Descriptor instead.
+func (*ListPromotionsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListPromotionsRequest) GetIncludeDisabled() bool {
+	if x != nil {
+		return x.IncludeDisabled
+	}
+	return false
+}
+
+type ListPromotionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotions    []*Promotion           `protobuf:"bytes,1,rep,name=promotions,proto3" json:"promotions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsResponse) Reset() {
+	*x = ListPromotionsResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsResponse) ProtoMessage() {}
+
+func (x *ListPromotionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsResponse.ProtoReflect.Descriptor instead.
+func (*ListPromotionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListPromotionsResponse) GetPromotions() []*Promotion {
+	if x != nil {
+		return x.Promotions
+	}
+	return nil
+}
+
+type DisablePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisablePromotionRequest) Reset() {
+	*x = DisablePromotionRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisablePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisablePromotionRequest) ProtoMessage() {}
+
+func (x *DisablePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisablePromotionRequest.ProtoReflect.Descriptor instead.
+func (*DisablePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DisablePromotionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DisablePromotionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisablePromotionResponse) Reset() {
+	*x = DisablePromotionResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisablePromotionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisablePromotionResponse) ProtoMessage() {}
+
+func (x *DisablePromotionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisablePromotionResponse.ProtoReflect.Descriptor instead.
+func (*DisablePromotionResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{28}
+}
+
+type GetPromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPromotionRequest) Reset() {
+	*x = GetPromotionRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPromotionRequest) ProtoMessage() {}
+
+func (x *GetPromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPromotionRequest.ProtoReflect.Descriptor instead.
+func (*GetPromotionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetPromotionRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GetPromotionRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetPromotionResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Promotion *Promotion             `protobuf:"bytes,1,opt,name=promotion,proto3" json:"promotion,omitempty"`
+	// how many orders the user has placed with the coupon
+	UserRedemptions int32 `protobuf:"varint,2,opt,name=user_redemptions,json=userRedemptions,proto3" json:"user_redemptions,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetPromotionResponse) Reset() {
+	*x = GetPromotionResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPromotionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPromotionResponse) ProtoMessage() {}
+
+func (x *GetPromotionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPromotionResponse.ProtoReflect.Descriptor instead.
+func (*GetPromotionResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetPromotionResponse) GetPromotion() *Promotion {
+	if x != nil {
+		return x.Promotion
+	}
+	return nil
+}
+
+func (x *GetPromotionResponse) GetUserRedemptions() int32 {
+	if x != nil {
+		return x.UserRedemptions
+	}
+	return 0
+}
+
+type RedeemPromotionRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Code   string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	UserId int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id of the checkout event, redeeming the same event twice is a no-op
+	EventId       string `protobuf:"bytes,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Discount      int64  `protobuf:"varint,4,opt,name=discount,proto3" json:"discount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeemPromotionRequest) Reset() {
+	*x = RedeemPromotionRequest{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeemPromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemPromotionRequest) ProtoMessage() {}
+
+func (x *RedeemPromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemPromotionRequest.ProtoReflect.Descriptor instead.
+func (*RedeemPromotionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RedeemPromotionRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RedeemPromotionRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RedeemPromotionRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
 	return ""
 }
 
+func (x *RedeemPromotionRequest) GetDiscount() int64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+type RedeemPromotionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeemPromotionResponse) Reset() {
+	*x = RedeemPromotionResponse{}
+	mi := &file_pkg_api_products_products_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeemPromotionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemPromotionResponse) ProtoMessage() {}
+
+func (x *RedeemPromotionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_products_products_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemPromotionResponse.ProtoReflect.Descriptor instead.
+func (*RedeemPromotionResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_products_products_proto_rawDescGZIP(), []int{32}
+}
+
 var File_pkg_api_products_products_proto protoreflect.FileDescriptor
 
 const file_pkg_api_products_products_proto_rawDesc = "" +
 	"\n" +
-	"\x1fpkg/api/products/products.proto\x12\bproducts\x1a pkg/google/api/annotations.proto\"#\n" +
+	"\x1fpkg/api/products/products.proto\x1a pkg/google/api/annotations.proto\"#\n" +
 	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"A\n" +
-	"\x12GetProductResponse\x12+\n" +
-	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct\"\x15\n" +
-	"\x13ListProductsRequest\"E\n" +
-	"\x14ListProductsResponse\x12-\n" +
-	"\bproducts\x18\x01 \x03(\v2\x11.products.ProductR\bproducts\"S\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"8\n" +
+	"\x12GetProductResponse\x12\"\n" +
+	"\aproduct\x18\x01 \x01(\v2\b.ProductR\aproduct\"+\n" +
+	"\x17GetProductsByIDsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x05R\x03ids\"@\n" +
+	"\x18GetProductsByIDsResponse\x12$\n" +
+	"\bproducts\x18\x01 \x03(\v2\b.ProductR\bproducts\"\xc1\x01\n" +
+	"\x13ListProductsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1f\n" +
+	"\vname_filter\x18\x03 \x01(\tR\n" +
+	"nameFilter\x12\"\n" +
+	"\rin_stock_only\x18\x04 \x01(\bR\vinStockOnly\x12)\n" +
+	"\n" +
+	"sort_order\x18\x05 \x01(\x0e2\n" +
+	".SortOrderR\tsortOrder\"d\n" +
+	"\x14ListProductsResponse\x12$\n" +
+	"\bproducts\x18\x01 \x03(\v2\b.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\":\n" +
+	"\x14CreateProductRequest\x12\"\n" +
+	"\aproduct\x18\x01 \x01(\v2\b.ProductR\aproduct\"I\n" +
+	"\x15CreateProductResponse\x120\n" +
+	"\x0ecreatedProduct\x18\x01 \x01(\v2\b.ProductR\x0ecreatedProduct\"J\n" +
 	"\x14UpdateProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12+\n" +
-	"\aproduct\x18\x02 \x01(\v2\x11.products.ProductR\aproduct\"R\n" +
-	"\x15UpdateProductResponse\x129\n" +
-	"\x0eupdatedProduct\x18\x01 \x01(\v2\x11.products.ProductR\x0eupdatedProduct\"z\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\"\n" +
+	"\aproduct\x18\x02 \x01(\v2\b.ProductR\aproduct\"I\n" +
+	"\x15UpdateProductResponse\x120\n" +
+	"\x0eupdatedProduct\x18\x01 \x01(\v2\b.ProductR\x0eupdatedProduct\"&\n" +
+	"\x14DeleteProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x17\n" +
+	"\x15DeleteProductResponse\"\xac\x01\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12!\n" +
 	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
 	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12 \n" +
-	"\vdescription\x18\x04 \x01(\tR\vdescription2\xce\x02\n" +
-	"\x0fProductsService\x12f\n" +
-	"\x0eGetProductByID\x12\x1b.products.GetProductRequest\x1a\x1c.products.GetProductResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/products/{id}\x12c\n" +
-	"\fListProducts\x12\x1d.products.ListProductsRequest\x1a\x1e.products.ListProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/products\x12n\n" +
-	"\rUpdateProduct\x12\x1e.products.UpdateProductRequest\x1a\x1f.products.UpdateProductResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\x1a\x11/v1/products/{id}B=Z;github.com/sabirkekw/ecommerce_go/pkg/api/products;productsb\x06proto3"
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x03R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\"F\n" +
+	"\tStockItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x05R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"7\n" +
+	"\x13ReserveStockRequest\x12 \n" +
+	"\x05items\x18\x01 \x03(\v2\n" +
+	".StockItemR\x05items\"\\\n" +
+	"\x14ReserveStockResponse\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x05R\rreservationId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"A\n" +
+	"\x18CommitReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x05R\rreservationId\"\x1b\n" +
+	"\x19CommitReservationResponse\"B\n" +
+	"\x19ReleaseReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x05R\rreservationId\"\x1c\n" +
+	"\x1aReleaseReservationResponse\"B\n" +
+	"\x19RestockReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x05R\rreservationId\"\x1c\n" +
+	"\x1aRestockReservationResponse\"\xc7\x03\n" +
+	"\tPromotion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\"\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x0e.PromotionKindR\x04kind\x12\x1f\n" +
+	"\vpercent_off\x18\x04 \x01(\x05R\n" +
+	"percentOff\x12\x1d\n" +
+	"\n" +
+	"amount_off\x18\x05 \x01(\x03R\tamountOff\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\a \x01(\x05R\tproductId\x12!\n" +
+	"\fbuy_quantity\x18\b \x01(\x05R\vbuyQuantity\x12!\n" +
+	"\fget_quantity\x18\t \x01(\x05R\vgetQuantity\x12!\n" +
+	"\fmin_subtotal\x18\n" +
+	" \x01(\x03R\vminSubtotal\x127\n" +
+	"\x18max_redemptions_per_user\x18\v \x01(\x05R\x15maxRedemptionsPerUser\x12\x1b\n" +
+	"\tstarts_at\x18\f \x01(\x03R\bstartsAt\x12\x17\n" +
+	"\aends_at\x18\r \x01(\x03R\x06endsAt\x12\x1f\n" +
+	"\vdisabled_at\x18\x0e \x01(\x03R\n" +
+	"disabledAt\"B\n" +
+	"\x16CreatePromotionRequest\x12(\n" +
+	"\tpromotion\x18\x01 \x01(\v2\n" +
+	".PromotionR\tpromotion\"C\n" +
+	"\x17CreatePromotionResponse\x12(\n" +
+	"\tpromotion\x18\x01 \x01(\v2\n" +
+	".PromotionR\tpromotion\"B\n" +
+	"\x15ListPromotionsRequest\x12)\n" +
+	"\x10include_disabled\x18\x01 \x01(\bR\x0fincludeDisabled\"D\n" +
+	"\x16ListPromotionsResponse\x12*\n" +
+	"\n" +
+	"promotions\x18\x01 \x03(\v2\n" +
+	".PromotionR\n" +
+	"promotions\")\n" +
+	"\x17DisablePromotionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1a\n" +
+	"\x18DisablePromotionResponse\"B\n" +
+	"\x13GetPromotionRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"k\n" +
+	"\x14GetPromotionResponse\x12(\n" +
+	"\tpromotion\x18\x01 \x01(\v2\n" +
+	".PromotionR\tpromotion\x12)\n" +
+	"\x10user_redemptions\x18\x02 \x01(\x05R\x0fuserRedemptions\"|\n" +
+	"\x16RedeemPromotionRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x19\n" +
+	"\bevent_id\x18\x03 \x01(\tR\aeventId\x12\x1a\n" +
+	"\bdiscount\x18\x04 \x01(\x03R\bdiscount\"\x19\n" +
+	"\x17RedeemPromotionResponse*\xc4\x01\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SORT_ORDER_ID_ASC\x10\x01\x12\x16\n" +
+	"\x12SORT_ORDER_ID_DESC\x10\x02\x12\x17\n" +
+	"\x13SORT_ORDER_NAME_ASC\x10\x03\x12\x18\n" +
+	"\x14SORT_ORDER_NAME_DESC\x10\x04\x12\x1b\n" +
+	"\x17SORT_ORDER_QUANTITY_ASC\x10\x05\x12\x1c\n" +
+	"\x18SORT_ORDER_QUANTITY_DESC\x10\x06*\x8f\x01\n" +
+	"\rPromotionKind\x12\x1e\n" +
+	"\x1aPROMOTION_KIND_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19PROMOTION_KIND_PERCENTAGE\x10\x01\x12\x1f\n" +
+	"\x1bPROMOTION_KIND_FIXED_AMOUNT\x10\x02\x12\x1e\n" +
+	"\x1aPROMOTION_KIND_BUY_X_GET_Y\x10\x032\xe9\t\n" +
+	"\x0fProductsService\x12T\n" +
+	"\x0eGetProductByID\x12\x12.GetProductRequest\x1a\x13.GetProductResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/products/{id}\x12Q\n" +
+	"\fListProducts\x12\x14.ListProductsRequest\x1a\x15.ListProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/products\x12W\n" +
+	"\rCreateProduct\x12\x15.CreateProductRequest\x1a\x16.CreateProductResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/products\x12\\\n" +
+	"\rUpdateProduct\x12\x15.UpdateProductRequest\x1a\x16.UpdateProductResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\x1a\x11/v1/products/{id}\x12Y\n" +
+	"\rDeleteProduct\x12\x15.DeleteProductRequest\x1a\x16.DeleteProductResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/products/{id}\x12G\n" +
+	"\x10GetProductsByIDs\x12\x18.GetProductsByIDsRequest\x1a\x19.GetProductsByIDsResponse\x12;\n" +
+	"\fReserveStock\x12\x14.ReserveStockRequest\x1a\x15.ReserveStockResponse\x12J\n" +
+	"\x11CommitReservation\x12\x19.CommitReservationRequest\x1a\x1a.CommitReservationResponse\x12M\n" +
+	"\x12ReleaseReservation\x12\x1a.ReleaseReservationRequest\x1a\x1b.ReleaseReservationResponse\x12M\n" +
+	"\x12RestockReservation\x12\x1a.RestockReservationRequest\x1a\x1b.RestockReservationResponse\x12_\n" +
+	"\x0fCreatePromotion\x12\x17.CreatePromotionRequest\x1a\x18.CreatePromotionResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/promotions\x12Y\n" +
+	"\x0eListPromotions\x12\x16.ListPromotionsRequest\x1a\x17.ListPromotionsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/promotions\x12l\n" +
+	"\x10DisablePromotion\x12\x18.DisablePromotionRequest\x1a\x19.DisablePromotionResponse\"#\x82\xd3\xe4\x93\x02\x1d\"\x1b/v1/promotions/{id}/disable\x12;\n" +
+	"\fGetPromotion\x12\x14.GetPromotionRequest\x1a\x15.GetPromotionResponse\x12D\n" +
+	"\x0fRedeemPromotion\x12\x17.RedeemPromotionRequest\x1a\x18.RedeemPromotionResponseB=Z;github.com/sabirkekw/ecommerce_go/pkg/api/products;productsb\x06proto3"
 
 var (
 	file_pkg_api_products_products_proto_rawDescOnce sync.Once
@@ -393,32 +1963,95 @@ func file_pkg_api_products_products_proto_rawDescGZIP() []byte {
 	return file_pkg_api_products_products_proto_rawDescData
 }
 
-var file_pkg_api_products_products_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_pkg_api_products_products_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pkg_api_products_products_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_pkg_api_products_products_proto_goTypes = []any{
-	(*GetProductRequest)(nil),     // 0: products.GetProductRequest
-	(*GetProductResponse)(nil),    // 1: products.GetProductResponse
-	(*ListProductsRequest)(nil),   // 2: products.ListProductsRequest
-	(*ListProductsResponse)(nil),  // 3: products.ListProductsResponse
-	(*UpdateProductRequest)(nil),  // 4: products.UpdateProductRequest
-	(*UpdateProductResponse)(nil), // 5: products.UpdateProductResponse
-	(*Product)(nil),               // 6: products.Product
+	(SortOrder)(0),                     // 0: SortOrder
+	(PromotionKind)(0),                 // 1: PromotionKind
+	(*GetProductRequest)(nil),          // 2: GetProductRequest
+	(*GetProductResponse)(nil),         // 3: GetProductResponse
+	(*GetProductsByIDsRequest)(nil),    // 4: GetProductsByIDsRequest
+	(*GetProductsByIDsResponse)(nil),   // 5: GetProductsByIDsResponse
+	(*ListProductsRequest)(nil),        // 6: ListProductsRequest
+	(*ListProductsResponse)(nil),       // 7: ListProductsResponse
+	(*CreateProductRequest)(nil),       // 8: CreateProductRequest
+	(*CreateProductResponse)(nil),      // 9: CreateProductResponse
+	(*UpdateProductRequest)(nil),       // 10: UpdateProductRequest
+	(*UpdateProductResponse)(nil),      // 11: UpdateProductResponse
+	(*DeleteProductRequest)(nil),       // 12: DeleteProductRequest
+	(*DeleteProductResponse)(nil),      // 13: DeleteProductResponse
+	(*Product)(nil),                    // 14: Product
+	(*StockItem)(nil),                  // 15: StockItem
+	(*ReserveStockRequest)(nil),        // 16: ReserveStockRequest
+	(*ReserveStockResponse)(nil),       // 17: ReserveStockResponse
+	(*CommitReservationRequest)(nil),   // 18: CommitReservationRequest
+	(*CommitReservationResponse)(nil),  // 19: CommitReservationResponse
+	(*ReleaseReservationRequest)(nil),  // 20: ReleaseReservationRequest
+	(*ReleaseReservationResponse)(nil), // 21: ReleaseReservationResponse
+	(*RestockReservationRequest)(nil),  // 22: RestockReservationRequest
+	(*RestockReservationResponse)(nil), // 23: RestockReservationResponse
+	(*Promotion)(nil),                  // 24: Promotion
+	(*CreatePromotionRequest)(nil),     // 25: CreatePromotionRequest
+	(*CreatePromotionResponse)(nil),    // 26: CreatePromotionResponse
+	(*ListPromotionsRequest)(nil),      // 27: ListPromotionsRequest
+	(*ListPromotionsResponse)(nil),     // 28: ListPromotionsResponse
+	(*DisablePromotionRequest)(nil),    // 29: DisablePromotionRequest
+	(*DisablePromotionResponse)(nil),   // 30: DisablePromotionResponse
+	(*GetPromotionRequest)(nil),        // 31: GetPromotionRequest
+	(*GetPromotionResponse)(nil),       // 32: GetPromotionResponse
+	(*RedeemPromotionRequest)(nil),     // 33: RedeemPromotionRequest
+	(*RedeemPromotionResponse)(nil),    // 34: RedeemPromotionResponse
 }
 var file_pkg_api_products_products_proto_depIdxs = []int32{
-	6, // 0: products.GetProductResponse.product:type_name -> products.Product
-	6, // 1: products.ListProductsResponse.products:type_name -> products.Product
-	6, // 2: products.UpdateProductRequest.product:type_name -> products.Product
-	6, // 3: products.UpdateProductResponse.updatedProduct:type_name -> products.Product
-	0, // 4: products.ProductsService.GetProductByID:input_type -> products.GetProductRequest
-	2, // 5: products.ProductsService.ListProducts:input_type -> products.ListProductsRequest
-	4, // 6: products.ProductsService.UpdateProduct:input_type -> products.UpdateProductRequest
-	1, // 7: products.ProductsService.GetProductByID:output_type -> products.GetProductResponse
-	3, // 8: products.ProductsService.ListProducts:output_type -> products.ListProductsResponse
-	5, // 9: products.ProductsService.UpdateProduct:output_type -> products.UpdateProductResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	14, // 0: GetProductResponse.product:type_name -> Product
+	14, // 1: GetProductsByIDsResponse.products:type_name -> Product
+	0,  // 2: ListProductsRequest.sort_order:type_name -> SortOrder
+	14, // 3: ListProductsResponse.products:type_name -> Product
+	14, // 4: CreateProductRequest.product:type_name -> Product
+	14, // 5: CreateProductResponse.createdProduct:type_name -> Product
+	14, // 6: UpdateProductRequest.product:type_name -> Product
+	14, // 7: UpdateProductResponse.updatedProduct:type_name -> Product
+	15, // 8: ReserveStockRequest.items:type_name -> StockItem
+	1,  // 9: Promotion.kind:type_name -> PromotionKind
+	24, // 10: CreatePromotionRequest.promotion:type_name -> Promotion
+	24, // 11: CreatePromotionResponse.promotion:type_name -> Promotion
+	24, // 12: ListPromotionsResponse.promotions:type_name -> Promotion
+	24, // 13: GetPromotionResponse.promotion:type_name -> Promotion
+	2,  // 14: ProductsService.GetProductByID:input_type -> GetProductRequest
+	6,  // 15: ProductsService.ListProducts:input_type -> ListProductsRequest
+	8,  // 16: ProductsService.CreateProduct:input_type -> CreateProductRequest
+	10, // 17: ProductsService.UpdateProduct:input_type -> UpdateProductRequest
+	12, // 18: ProductsService.DeleteProduct:input_type -> DeleteProductRequest
+	4,  // 19: ProductsService.GetProductsByIDs:input_type -> GetProductsByIDsRequest
+	16, // 20: ProductsService.ReserveStock:input_type -> ReserveStockRequest
+	18, // 21: ProductsService.CommitReservation:input_type -> CommitReservationRequest
+	20, // 22: ProductsService.ReleaseReservation:input_type -> ReleaseReservationRequest
+	22, // 23: ProductsService.RestockReservation:input_type -> RestockReservationRequest
+	25, // 24: ProductsService.CreatePromotion:input_type -> CreatePromotionRequest
+	27, // 25: ProductsService.ListPromotions:input_type -> ListPromotionsRequest
+	29, // 26: ProductsService.DisablePromotion:input_type -> DisablePromotionRequest
+	31, // 27: ProductsService.GetPromotion:input_type -> GetPromotionRequest
+	33, // 28: ProductsService.RedeemPromotion:input_type -> RedeemPromotionRequest
+	3,  // 29: ProductsService.GetProductByID:output_type -> GetProductResponse
+	7,  // 30: ProductsService.ListProducts:output_type -> ListProductsResponse
+	9,  // 31: ProductsService.CreateProduct:output_type -> CreateProductResponse
+	11, // 32: ProductsService.UpdateProduct:output_type -> UpdateProductResponse
+	13, // 33: ProductsService.DeleteProduct:output_type -> DeleteProductResponse
+	5,  // 34: ProductsService.GetProductsByIDs:output_type -> GetProductsByIDsResponse
+	17, // 35: ProductsService.ReserveStock:output_type -> ReserveStockResponse
+	19, // 36: ProductsService.CommitReservation:output_type -> CommitReservationResponse
+	21, // 37: ProductsService.ReleaseReservation:output_type -> ReleaseReservationResponse
+	23, // 38: ProductsService.RestockReservation:output_type -> RestockReservationResponse
+	26, // 39: ProductsService.CreatePromotion:output_type -> CreatePromotionResponse
+	28, // 40: ProductsService.ListPromotions:output_type -> ListPromotionsResponse
+	30, // 41: ProductsService.DisablePromotion:output_type -> DisablePromotionResponse
+	32, // 42: ProductsService.GetPromotion:output_type -> GetPromotionResponse
+	34, // 43: ProductsService.RedeemPromotion:output_type -> RedeemPromotionResponse
+	29, // [29:44] is the sub-list for method output_type
+	14, // [14:29] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_pkg_api_products_products_proto_init() }
@@ -431,13 +2064,14 @@ func file_pkg_api_products_products_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_api_products_products_proto_rawDesc), len(file_pkg_api_products_products_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      2,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_api_products_products_proto_goTypes,
 		DependencyIndexes: file_pkg_api_products_products_proto_depIdxs,
+		EnumInfos:         file_pkg_api_products_products_proto_enumTypes,
 		MessageInfos:      file_pkg_api_products_products_proto_msgTypes,
 	}.Build()
 	File_pkg_api_products_products_proto = out.File
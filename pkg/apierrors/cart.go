@@ -3,7 +3,12 @@ package apierrors
 import "errors"
 
 var (
-	ErrFailedToCheckout = errors.New("Failed to checkout")
-	ErrFailedToGetCart  = errors.New("Failed to get cart")
-	ErrEmptyCart        = errors.New("Cart is empty")
+	ErrFailedToCheckout  = errors.New("Failed to checkout")
+	ErrFailedToGetCart   = errors.New("Failed to get cart")
+	ErrEmptyCart         = errors.New("Cart is empty")
+	ErrCurrencyMismatch  = errors.New("Product currency differs from cart currency")
+	ErrGuestCartNotFound = errors.New("Guest cart not found")
+	ErrTooManyGuestCarts = errors.New("Too many guest carts")
+	ErrProductNotInCart  = errors.New("Product is not in the cart")
+	ErrCartChanged       = errors.New("Cart changed during checkout")
 )
@@ -6,4 +6,15 @@ var (
 	ErrNotEnoughProduct = errors.New("failed to create order: product not found")
 	ErrOrderNotFound    = errors.New("order not found")
 	ErrInvalidOrderData = errors.New("invalid order data")
+	ErrEventProcessed   = errors.New("event already processed")
+
+	ErrInvalidOrderStatus      = errors.New("invalid order status")
+	ErrInvalidStatusTransition = errors.New("order status transition not allowed")
+
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrDeadLetterReplayed = errors.New("dead letter already replayed")
+	// ErrDeadLetterNotReplayable means the payload isn't a checkout message
+	ErrDeadLetterNotReplayable = errors.New("dead letter can't be replayed")
+	// ErrDeadLetterOrderExists means the checkout already has an order that wasn't cancelled
+	ErrDeadLetterOrderExists = errors.New("dead letter checkout already has an order")
 )
@@ -3,9 +3,17 @@ package apierrors
 import "errors"
 
 var (
-	ErrProductNotFound     = errors.New("Product not found")
-	ErrFailedToReadProduct = errors.New("Failed to read product")
-	ErrIncorrectID         = errors.New("Incorrect ID")
-	ErrUnknown             = errors.New("Unknown error")
+	ErrProductNotFound       = errors.New("Product not found")
+	ErrFailedToReadProduct   = errors.New("Failed to read product")
+	ErrIncorrectID           = errors.New("Incorrect ID")
+	ErrUnknown               = errors.New("Unknown error")
 	ErrFailedToUpdateProduct = errors.New("Failed to update product")
+	ErrProductAlreadyExists  = errors.New("Product already exists")
+	ErrFailedToCreateProduct = errors.New("Failed to create product")
+	ErrFailedToDeleteProduct = errors.New("Failed to delete product")
+	ErrInvalidPageToken      = errors.New("Invalid page token")
+	ErrReservationNotFound   = errors.New("Reservation not found")
+	ErrReservationExpired    = errors.New("Reservation expired")
+	ErrReservationReleased   = errors.New("Reservation already released")
+	ErrReservationCommitted  = errors.New("Reservation already committed")
 )
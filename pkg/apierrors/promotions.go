@@ -0,0 +1,12 @@
+package apierrors
+
+import "errors"
+
+var (
+	ErrInvalidPromotion       = errors.New("Invalid promotion")
+	ErrPromotionNotFound      = errors.New("Promotion not found")
+	ErrPromotionExists        = errors.New("Promotion code already exists")
+	ErrPromotionNotApplicable = errors.New("Promotion doesn't apply to the cart")
+	ErrPromotionLimitReached  = errors.New("Promotion usage limit reached")
+	ErrFailedToReadPromotion  = errors.New("Failed to read promotion")
+)
@@ -5,7 +5,33 @@ import "errors"
 var (
 	ErrTokenExpired       = errors.New("token expired")
 	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenRevoked       = errors.New("token revoked")
 	ErrNoUser             = errors.New("user not found")
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+
+	ErrInvalidUserToken = errors.New("invalid or expired token")
+	ErrEmailNotVerified = errors.New("email not verified")
+
+	ErrTOTPAlreadyEnabled    = errors.New("totp already enabled")
+	ErrTOTPNotEnrolled       = errors.New("totp not enrolled")
+	ErrInvalidSecondFactor   = errors.New("invalid second factor code")
+	ErrInvalidLoginChallenge = errors.New("invalid or expired login challenge")
+
+	ErrAddressNotFound   = errors.New("address not found")
+	ErrInvalidAddress    = errors.New("invalid address")
+	ErrTooManyAddresses  = errors.New("too many addresses")
+	ErrNoShippingAddress = errors.New("no shipping address")
+
+	ErrInvalidClient = errors.New("invalid client credentials")
+
+	ErrInvalidRedirectURI          = errors.New("redirect uri not registered")
+	ErrInvalidAuthorizationRequest = errors.New("invalid authorization request")
+	ErrInvalidGrant                = errors.New("invalid or expired authorization code")
 )
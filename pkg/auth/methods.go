@@ -0,0 +1,23 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Authentication methods sso-service lists in the amr claim of access tokens, see RFC 8176.
+const (
+	MethodPassword = "pwd"
+	MethodMFA      = "mfa"
+)
+
+// HasSecondFactor reports whether the token was issued after a second factor was checked.
+func HasSecondFactor(claims jwt.MapClaims) bool {
+	methods, ok := claims["amr"].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, m := range methods {
+		if method, ok := m.(string); ok && method == MethodMFA {
+			return true
+		}
+	}
+	return false
+}
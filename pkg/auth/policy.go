@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"github.com/sabirkekw/ecommerce_go/pkg/api/cart"
+	"github.com/sabirkekw/ecommerce_go/pkg/api/order"
+	"github.com/sabirkekw/ecommerce_go/pkg/api/products"
+)
+
+// Rule says who may call an RPC.
+type Rule struct {
+	// Public RPCs are served without a token.
+	Public bool
+	// Roles the caller needs at least one of. Empty means any authenticated caller.
+	Roles []string
+	// Service RPCs are internal and need a token issued to a service account, users are refused.
+	Service bool
+	// SecondFactor RPCs also need a token from a login confirmed with 2FA.
+	SecondFactor bool
+	// Guest RPCs also serve callers without a token that present a guest cart token instead.
+	Guest bool
+}
+
+func (r Rule) Allows(roles []string) bool {
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, role := range r.Roles {
+		if HasRole(roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy maps full gRPC method names to their rules.
+type Policy map[string]Rule
+
+// Rule returns the rule for method. RPCs missing from the policy need an authenticated caller with any role.
+func (p Policy) Rule(method string) Rule {
+	return p[method]
+}
+
+// DefaultPolicy is consulted by the interceptors of every service.
+var DefaultPolicy = Policy{
+	products.ProductsService_GetProductByID_FullMethodName:   {Public: true},
+	products.ProductsService_ListProducts_FullMethodName:     {Public: true},
+	products.ProductsService_GetProductsByIDs_FullMethodName: {Public: true},
+	products.ProductsService_CreateProduct_FullMethodName:    {Roles: []string{RoleAdmin}, SecondFactor: true},
+	products.ProductsService_UpdateProduct_FullMethodName:    {Roles: []string{RoleAdmin}, SecondFactor: true},
+	products.ProductsService_DeleteProduct_FullMethodName:    {Roles: []string{RoleAdmin}, SecondFactor: true},
+	products.ProductsService_CreatePromotion_FullMethodName:  {Roles: []string{RoleAdmin}, SecondFactor: true},
+	products.ProductsService_ListPromotions_FullMethodName:   {Roles: []string{RoleAdmin}, SecondFactor: true},
+	products.ProductsService_DisablePromotion_FullMethodName: {Roles: []string{RoleAdmin}, SecondFactor: true},
+	// called by cart and order services on behalf of the system, not exposed through the gateway
+	products.ProductsService_ReserveStock_FullMethodName:       {Service: true},
+	products.ProductsService_CommitReservation_FullMethodName:  {Service: true},
+	products.ProductsService_ReleaseReservation_FullMethodName: {Service: true},
+	products.ProductsService_RestockReservation_FullMethodName: {Service: true},
+	products.ProductsService_GetPromotion_FullMethodName:       {Service: true},
+	products.ProductsService_RedeemPromotion_FullMethodName:    {Service: true},
+
+	cart.CartService_AddToCart_FullMethodName:         {Guest: true},
+	cart.CartService_SetQuantity_FullMethodName:       {Guest: true},
+	cart.CartService_IncrementQuantity_FullMethodName: {Guest: true},
+	cart.CartService_AddItems_FullMethodName:          {Guest: true},
+	cart.CartService_RemoveFromCart_FullMethodName:    {Guest: true},
+	cart.CartService_ClearCart_FullMethodName:         {Guest: true},
+	cart.CartService_GetCart_FullMethodName:           {Guest: true},
+	cart.CartService_CreateGuestCart_FullMethodName:   {Public: true},
+
+	order.OrderService_UpdateOrderStatus_FullMethodName: {Roles: []string{RoleAdmin, RoleSupport}},
+	order.OrderService_ListDeadLetters_FullMethodName:   {Roles: []string{RoleAdmin, RoleSupport}},
+	order.OrderService_ReplayDeadLetter_FullMethodName:  {Roles: []string{RoleAdmin}},
+}
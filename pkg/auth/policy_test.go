@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/pkg/api/products"
+)
+
+func TestRuleAllows(t *testing.T) {
+	anyone := Rule{}
+	if !anyone.Allows(nil) {
+		t.Error("rule without roles refused a caller without roles")
+	}
+
+	staff := Rule{Roles: []string{RoleAdmin, RoleSupport}}
+	if !staff.Allows([]string{RoleCustomer, RoleSupport}) {
+		t.Error("staff rule refused a caller with the support role")
+	}
+	if staff.Allows([]string{RoleCustomer}) {
+		t.Error("staff rule allowed a customer")
+	}
+	if staff.Allows(nil) {
+		t.Error("staff rule allowed a caller without roles")
+	}
+}
+
+// Stock RPCs move inventory without checking who the user is, a user token must never reach them.
+func TestDefaultPolicyStockRPCsNeedService(t *testing.T) {
+	for _, method := range []string{
+		products.ProductsService_ReserveStock_FullMethodName,
+		products.ProductsService_CommitReservation_FullMethodName,
+		products.ProductsService_ReleaseReservation_FullMethodName,
+	} {
+		rule := DefaultPolicy.Rule(method)
+		if rule.Public || !rule.Service {
+			t.Errorf("%s: got %+v, want a service-only rule", method, rule)
+		}
+	}
+}
+
+func TestDefaultPolicyUnknownMethod(t *testing.T) {
+	rule := DefaultPolicy.Rule("/unknown.Service/Method")
+	if rule.Public || rule.Service || len(rule.Roles) != 0 {
+		t.Errorf("got %+v, want the zero rule that any authenticated caller passes", rule)
+	}
+}
+
+func TestRolesFromClaims(t *testing.T) {
+	claims := jwt.MapClaims{"roles": []interface{}{RoleAdmin, 42, RoleSupport}}
+	roles := RolesFromClaims(claims)
+	if len(roles) != 2 || roles[0] != RoleAdmin || roles[1] != RoleSupport {
+		t.Errorf("RolesFromClaims() = %v, want [%s %s]", roles, RoleAdmin, RoleSupport)
+	}
+
+	if roles := RolesFromClaims(jwt.MapClaims{"sub": "1"}); roles != nil {
+		t.Errorf("RolesFromClaims() without the claim = %v, want nil", roles)
+	}
+}
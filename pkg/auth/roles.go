@@ -0,0 +1,35 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+	RoleSupport  = "support"
+)
+
+// RolesFromClaims reads the roles claim sso-service puts into access tokens.
+// Tokens without it carry no roles.
+func RolesFromClaims(claims jwt.MapClaims) []string {
+	rawRoles, ok := claims["roles"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	roles := make([]string, 0, len(rawRoles))
+	for _, r := range rawRoles {
+		if role, ok := r.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func HasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// ServiceFromClaims returns the client ID of the service account a token was issued to through
+// the client credentials grant. Tokens of users have none.
+func ServiceFromClaims(claims jwt.MapClaims) (string, bool) {
+	clientID, ok := claims["client_id"].(string)
+	return clientID, ok && clientID != ""
+}
@@ -0,0 +1,110 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRefetchInterval limits refetches caused by tokens with unknown key IDs.
+const minRefetchInterval = 10 * time.Second
+
+var ErrUnknownKey = errors.New("unknown key id")
+
+// Cache keeps the JWKS document of sso-service and refetches it once ttl has passed.
+type Cache struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]ed25519.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func NewCache(url string, ttl time.Duration) *Cache {
+	return &Cache{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Keyfunc verifies EdDSA tokens against the key named by their kid header. A kid the cache
+// doesn't know yet triggers a refetch, so keys sso-service just rotated in are picked up.
+func (c *Cache) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, ErrUnknownKey
+	}
+	return c.key(kid)
+}
+
+func (c *Cache) key(kid string) (ed25519.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, known := c.keys[kid]
+	recentlyAttempted := time.Since(c.attemptedAt) < minRefetchInterval
+	if known && (time.Since(c.fetchedAt) < c.ttl || recentlyAttempted) {
+		return key, nil
+	}
+	if !known && recentlyAttempted {
+		return nil, ErrUnknownKey
+	}
+
+	c.attemptedAt = time.Now()
+	if err := c.fetch(); err != nil {
+		// sso-service being down shouldn't lock everyone out while the cached keys are still around
+		if known {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	key, known = c.keys[kid]
+	if !known {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (c *Cache) fetch() error {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		publicKey, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = publicKey
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,51 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	KeyType   = "OKP"
+	Curve     = "Ed25519"
+	Algorithm = "EdDSA"
+)
+
+var ErrUnsupportedKey = errors.New("unsupported key")
+
+// JWK is a public key as published by sso-service, see RFC 8037 for the OKP key type.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	X   string `json:"x"`
+}
+
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewJWK(kid string, publicKey ed25519.PublicKey) JWK {
+	return JWK{
+		Kty: KeyType,
+		Crv: Curve,
+		Kid: kid,
+		Use: "sig",
+		Alg: Algorithm,
+		X:   base64.RawURLEncoding.EncodeToString(publicKey),
+	}
+}
+
+func (k JWK) PublicKey() (ed25519.PublicKey, error) {
+	if k.Kty != KeyType || k.Crv != Curve {
+		return nil, ErrUnsupportedKey
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, ErrUnsupportedKey
+	}
+	return ed25519.PublicKey(x), nil
+}
@@ -0,0 +1,153 @@
+// Package promotions holds the rules of coupons. products-service stores the promotions and counts
+// their redemptions, cart-service computes discounts with them, so both agree on what a coupon is worth.
+package promotions
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+type Kind string
+
+const (
+	// Percentage takes PercentOff percent off the subtotal.
+	Percentage Kind = "percentage"
+	// FixedAmount takes AmountOff off the subtotal.
+	FixedAmount Kind = "fixed_amount"
+	// BuyXGetY gives GetQuantity of ProductID for free for every BuyQuantity bought.
+	BuyXGetY Kind = "buy_x_get_y"
+)
+
+// Promotion is a discount customers get by entering its coupon code. Amounts are in minor units of
+// Currency.
+type Promotion struct {
+	ID   int64
+	Code string
+	Kind Kind
+
+	PercentOff int32
+	AmountOff  int64
+	// Currency of AmountOff and MinSubtotal, carts in other currencies don't qualify
+	Currency string
+
+	ProductID   int32
+	BuyQuantity int32
+	GetQuantity int32
+
+	// MinSubtotal the cart needs before the discount, 0 for none
+	MinSubtotal int64
+	// MaxRedemptionsPerUser counts orders placed with the coupon, 0 for no limit
+	MaxRedemptionsPerUser int32
+
+	StartsAt   *time.Time
+	EndsAt     *time.Time
+	DisabledAt *time.Time
+}
+
+// Line is a cart or order line the discount is computed from.
+type Line struct {
+	ProductID int32
+	Quantity  int32
+	UnitPrice int64
+}
+
+// NormalizeCode makes codes case-insensitive.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks a promotion before it is stored. apierrors.ErrInvalidPromotion means it makes no sense.
+func (p *Promotion) Validate() error {
+	if p.Code == "" || len(p.Code) > 64 {
+		return apierrors.ErrInvalidPromotion
+	}
+	switch p.Kind {
+	case Percentage:
+		if p.PercentOff <= 0 || p.PercentOff > 100 {
+			return apierrors.ErrInvalidPromotion
+		}
+	case FixedAmount:
+		if p.AmountOff <= 0 || p.Currency == "" {
+			return apierrors.ErrInvalidPromotion
+		}
+	case BuyXGetY:
+		if p.ProductID <= 0 || p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return apierrors.ErrInvalidPromotion
+		}
+	default:
+		return apierrors.ErrInvalidPromotion
+	}
+	if p.MinSubtotal < 0 || p.MaxRedemptionsPerUser < 0 {
+		return apierrors.ErrInvalidPromotion
+	}
+	if p.MinSubtotal > 0 && p.Currency == "" {
+		return apierrors.ErrInvalidPromotion
+	}
+	if p.Currency != "" && len(p.Currency) != 3 {
+		return apierrors.ErrInvalidPromotion
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return apierrors.ErrInvalidPromotion
+	}
+	return nil
+}
+
+// Active reports whether the coupon can be used at now.
+func (p *Promotion) Active(now time.Time) bool {
+	if p.DisabledAt != nil {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// LimitReached reports whether a user who redeemed the coupon redemptions times can't use it again.
+func (p *Promotion) LimitReached(redemptions int32) bool {
+	return p.MaxRedemptionsPerUser > 0 && redemptions >= p.MaxRedemptionsPerUser
+}
+
+// Discount returns what the promotion takes off lines in currency at now. It never exceeds the
+// subtotal. apierrors.ErrPromotionNotApplicable means the cart doesn't qualify.
+func (p *Promotion) Discount(lines []Line, currency string, now time.Time) (int64, error) {
+	if !p.Active(now) {
+		return 0, apierrors.ErrPromotionNotApplicable
+	}
+	if p.Currency != "" && p.Currency != currency {
+		return 0, apierrors.ErrPromotionNotApplicable
+	}
+
+	var subtotal int64
+	for _, line := range lines {
+		subtotal += line.UnitPrice * int64(line.Quantity)
+	}
+	if subtotal == 0 || subtotal < p.MinSubtotal {
+		return 0, apierrors.ErrPromotionNotApplicable
+	}
+
+	var discount int64
+	switch p.Kind {
+	case Percentage:
+		discount = subtotal * int64(p.PercentOff) / 100
+	case FixedAmount:
+		discount = p.AmountOff
+	case BuyXGetY:
+		for _, line := range lines {
+			if line.ProductID != p.ProductID {
+				continue
+			}
+			free := line.Quantity / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+			discount += int64(free) * line.UnitPrice
+		}
+		if discount == 0 {
+			return 0, apierrors.ErrPromotionNotApplicable
+		}
+	}
+	return min(discount, subtotal), nil
+}
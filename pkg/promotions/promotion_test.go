@@ -0,0 +1,146 @@
+package promotions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+)
+
+func TestPromotionDiscount(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	lines := []Line{
+		{ProductID: 1, Quantity: 2, UnitPrice: 1000},
+		{ProductID: 2, Quantity: 1, UnitPrice: 500},
+	}
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		lines     []Line
+		currency  string
+		want      int64
+		wantErr   error
+	}{
+		{
+			name:      "percentage of subtotal",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10},
+			lines:     lines,
+			currency:  "USD",
+			want:      250,
+		},
+		{
+			name:      "percentage rounds down",
+			promotion: Promotion{Kind: Percentage, PercentOff: 33},
+			lines:     []Line{{ProductID: 1, Quantity: 1, UnitPrice: 10}},
+			currency:  "USD",
+			want:      3,
+		},
+		{
+			name:      "fixed amount",
+			promotion: Promotion{Kind: FixedAmount, AmountOff: 700, Currency: "USD"},
+			lines:     lines,
+			currency:  "USD",
+			want:      700,
+		},
+		{
+			name:      "fixed amount capped at subtotal",
+			promotion: Promotion{Kind: FixedAmount, AmountOff: 10000, Currency: "USD"},
+			lines:     lines,
+			currency:  "USD",
+			want:      2500,
+		},
+		{
+			name:      "fixed amount in another currency",
+			promotion: Promotion{Kind: FixedAmount, AmountOff: 700, Currency: "EUR"},
+			lines:     lines,
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "buy two get one",
+			promotion: Promotion{Kind: BuyXGetY, ProductID: 1, BuyQuantity: 2, GetQuantity: 1},
+			lines:     []Line{{ProductID: 1, Quantity: 7, UnitPrice: 100}, {ProductID: 2, Quantity: 3, UnitPrice: 50}},
+			currency:  "USD",
+			want:      200,
+		},
+		{
+			name:      "buy two get one without enough bought",
+			promotion: Promotion{Kind: BuyXGetY, ProductID: 1, BuyQuantity: 2, GetQuantity: 1},
+			lines:     []Line{{ProductID: 1, Quantity: 2, UnitPrice: 100}},
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "buy x get y without the product",
+			promotion: Promotion{Kind: BuyXGetY, ProductID: 3, BuyQuantity: 1, GetQuantity: 1},
+			lines:     lines,
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "min subtotal reached",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10, MinSubtotal: 2500, Currency: "USD"},
+			lines:     lines,
+			currency:  "USD",
+			want:      250,
+		},
+		{
+			name:      "min subtotal not reached",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10, MinSubtotal: 2501, Currency: "USD"},
+			lines:     lines,
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "empty cart",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10},
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "disabled",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10, DisabledAt: &before},
+			lines:     lines,
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "not started",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10, StartsAt: &after},
+			lines:     lines,
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "ended",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10, EndsAt: &now},
+			lines:     lines,
+			currency:  "USD",
+			wantErr:   apierrors.ErrPromotionNotApplicable,
+		},
+		{
+			name:      "within its period",
+			promotion: Promotion{Kind: Percentage, PercentOff: 10, StartsAt: &now, EndsAt: &after},
+			lines:     lines,
+			currency:  "USD",
+			want:      250,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.promotion.Discount(tt.lines, tt.currency, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Discount() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Discount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	pageSize = 500
+	// fullSyncInterval is how often the whole list is fetched again. Revocation IDs come from a sequence,
+	// a revocation whose transaction commits after a higher ID was seen is behind the cursor and only
+	// shows up in the next full sync.
+	fullSyncInterval = time.Minute
+)
+
+// List mirrors the revoked access tokens of sso-service, so a logged out token stops working
+// everywhere before it expires.
+type List struct {
+	logger *zap.SugaredLogger
+	client proto.AuthClient
+
+	mu           sync.RWMutex
+	revoked      map[string]time.Time
+	afterID      int64
+	lastFullSync time.Time
+}
+
+// New connects to sso-service, the feed is only served to service accounts so creds must carry a
+// service token.
+func New(logger *zap.SugaredLogger, ssoAddr string, creds credentials.PerRPCCredentials) (*List, error) {
+	conn, err := grpc.NewClient(ssoAddr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithPerRPCCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	return &List{
+		logger:  logger,
+		client:  proto.NewAuthClient(conn),
+		revoked: make(map[string]time.Time),
+	}, nil
+}
+
+// IsRevoked reports whether the token with the given jti was revoked. Tokens without a jti
+// predate revocation and are never reported.
+func (l *List) IsRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	expiresAt, ok := l.revoked[jti]
+	return ok && time.Now().Before(expiresAt)
+}
+
+// Run polls sso-service every interval until ctx is done. If sso-service is unreachable the
+// entries fetched so far stay in effect.
+func (l *List) Run(ctx context.Context, interval time.Duration) {
+	const op = "revocation.List.Run"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.sync(ctx); err != nil {
+			l.logger.Warnw("failed to sync revoked tokens", "error", err, "op", op)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync fetches the revocations after the cursor, or all unexpired ones once fullSyncInterval passed.
+// Entries are keyed by jti, fetching one twice is harmless.
+func (l *List) sync(ctx context.Context) error {
+	l.prune()
+
+	l.mu.RLock()
+	afterID := l.afterID
+	fullSync := time.Since(l.lastFullSync) >= fullSyncInterval
+	l.mu.RUnlock()
+	if fullSync {
+		afterID = 0
+	}
+	startedAt := time.Now()
+
+	for {
+		resp, err := l.client.ListRevokedTokens(ctx, &proto.ListRevokedTokensRequest{AfterId: afterID, Limit: pageSize})
+		if err != nil {
+			return err
+		}
+
+		l.mu.Lock()
+		for _, token := range resp.Tokens {
+			l.revoked[token.Jti] = time.Unix(token.ExpiresAt, 0)
+			afterID = max(afterID, token.Id)
+			l.afterID = max(l.afterID, token.Id)
+		}
+		l.mu.Unlock()
+
+		if len(resp.Tokens) < pageSize {
+			break
+		}
+	}
+
+	if fullSync {
+		l.mu.Lock()
+		l.lastFullSync = startedAt
+		l.mu.Unlock()
+	}
+	return nil
+}
+
+// prune drops entries whose tokens expired anyway.
+func (l *List) prune() {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for jti, expiresAt := range l.revoked {
+		if !now.Before(expiresAt) {
+			delete(l.revoked, jti)
+		}
+	}
+}
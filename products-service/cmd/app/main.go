@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/sabirkekw/ecommerce_go/pkg/clientcreds"
+	"github.com/sabirkekw/ecommerce_go/pkg/jwks"
 	"github.com/sabirkekw/ecommerce_go/pkg/logger"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	app "github.com/sabirkekw/ecommerce_go/products-service/internal/app"
 	"github.com/sabirkekw/ecommerce_go/products-service/internal/config"
 	"github.com/sabirkekw/ecommerce_go/products-service/internal/database/postgres"
@@ -27,15 +31,33 @@ func main() {
 		logger.Log.Fatalw("failed to connect to postgres", "error: ", err)
 	}
 
-	_, err = db.Exec("INSERT INTO products (product_name, quantity, description) VALUES ('test1', 10, 'test product1')")
-	_, err = db.Exec("INSERT INTO products (product_name, quantity, description) VALUES ('test2', 20, 'test product2')")
-	_, err = db.Exec("INSERT INTO products (product_name, quantity, description) VALUES ('test3', 30, 'test product3')")
+	_, err = db.Exec("INSERT INTO products (product_name, quantity, description, price, currency) VALUES ('test1', 10, 'test product1', 1000, 'USD')")
+	_, err = db.Exec("INSERT INTO products (product_name, quantity, description, price, currency) VALUES ('test2', 20, 'test product2', 2000, 'USD')")
+	_, err = db.Exec("INSERT INTO products (product_name, quantity, description, price, currency) VALUES ('test3', 30, 'test product3', 3000, 'USD')")
 
 	productsRepository := repository.New(db, logger.Log)
 
-	productsService := service.New(productsRepository, logger.Log)
+	productsService := service.New(productsRepository, cfg.Reservation.TTL, logger.Log)
 
-	application := app.New(logger.Log, cfg.HTTP.Port, cfg.GRPC.Port, productsService, cfg.JWTSecret, cfg.GRPC.Timeout)
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go productsService.ReleaseExpiredReservations(sweeperCtx, cfg.Reservation.SweepInterval)
+	logger.Log.Infow("Reservation sweeper started", "interval", cfg.Reservation.SweepInterval)
+
+	serviceCreds, err := clientcreds.New(logger.Log, cfg.Revocation.SSOAddr, cfg.ServiceAccount.ClientID, cfg.ServiceAccount.ClientSecret)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create service credentials", "error", err)
+	}
+
+	revocations, err := revocation.New(logger.Log, cfg.Revocation.SSOAddr, serviceCreds)
+	if err != nil {
+		logger.Log.Fatalw("Failed to create revocation list", "error", err)
+	}
+	revocationCtx, stopRevocation := context.WithCancel(context.Background())
+	defer stopRevocation()
+	go revocations.Run(revocationCtx, cfg.Revocation.PollInterval)
+
+	application := app.New(logger.Log, cfg.HTTP.Port, cfg.GRPC.Port, productsService, jwks.NewCache(cfg.JWKS.URL, cfg.JWKS.CacheTTL).Keyfunc, revocations, cfg.GRPC.Timeout)
 
 	go application.GRPCApp.Run()
 	logger.Log.Infow("Products gRPC server started", "port", cfg.GRPC.Port)
@@ -3,6 +3,8 @@ package app
 import (
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	grpcapp "github.com/sabirkekw/ecommerce_go/products-service/internal/app/grpc"
 	httpapp "github.com/sabirkekw/ecommerce_go/products-service/internal/app/http"
 	productsservice "github.com/sabirkekw/ecommerce_go/products-service/internal/grpc/server"
@@ -14,8 +16,8 @@ type App struct {
 	HTTPApp httpapp.HTTPApp
 }
 
-func New(logger *zap.SugaredLogger, HTTPPort int, GRPCPort int, productsService productsservice.ProductsService, jwtSecret string, timeout time.Duration) *App {
-	productsGRPCServer := grpcapp.New(logger, GRPCPort, productsService, jwtSecret, timeout)
+func New(logger *zap.SugaredLogger, HTTPPort int, GRPCPort int, productsService productsservice.ProductsService, keyfunc jwt.Keyfunc, revocations *revocation.List, timeout time.Duration) *App {
+	productsGRPCServer := grpcapp.New(logger, GRPCPort, productsService, keyfunc, revocations, timeout)
 	productsHTTPGateway := httpapp.New(logger, HTTPPort, GRPCPort)
 
 	return &App{
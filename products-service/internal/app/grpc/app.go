@@ -6,6 +6,8 @@ import (
 	"net"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"github.com/sabirkekw/ecommerce_go/products-service/internal/grpc/interceptor"
 	productsgrpc "github.com/sabirkekw/ecommerce_go/products-service/internal/grpc/server"
 	"go.uber.org/zap"
@@ -18,9 +20,11 @@ type GRPCApp struct {
 	Port   int
 }
 
-func New(logger *zap.SugaredLogger, port int, service productsgrpc.ProductsService, jwtSecret string, timeout time.Duration) *GRPCApp {
+func New(logger *zap.SugaredLogger, port int, service productsgrpc.ProductsService, keyfunc jwt.Keyfunc, revocations *revocation.List, timeout time.Duration) *GRPCApp {
 	wrappedTimeoutInterceptor := func(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		ctx = context.WithValue(ctx, "logger", logger)
+		ctx = context.WithValue(ctx, "keyfunc", keyfunc)
+		ctx = context.WithValue(ctx, "revocations", revocations)
 		ctx = context.WithValue(ctx, "timeout", timeout)
 		return interceptor.TimeoutInterceptor(ctx, req, serverInfo, handler)
 	}
@@ -28,6 +32,7 @@ func New(logger *zap.SugaredLogger, port int, service productsgrpc.ProductsServi
 	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 		wrappedTimeoutInterceptor,
 		interceptor.LogInterceptor,
+		interceptor.AuthInterceptor,
 	),
 	)
 
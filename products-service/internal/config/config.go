@@ -23,7 +23,23 @@ type Config struct {
 		Port    int           `yaml:"port"`
 		Timeout time.Duration `yaml:"timeout"`
 	} `yaml:"http"`
-	JWTSecret string `yaml:"jwt_secret"`
+	Reservation struct {
+		TTL           time.Duration `yaml:"ttl" env-default:"15m"`
+		SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1m"`
+	} `yaml:"reservation"`
+	JWKS struct {
+		URL      string        `yaml:"url" env:"JWKS_URL" env-default:"http://sso-service:8081/.well-known/jwks.json"`
+		CacheTTL time.Duration `yaml:"cache_ttl" env-default:"10m"`
+	} `yaml:"jwks"`
+	Revocation struct {
+		SSOAddr      string        `yaml:"sso_addr" env:"REVOCATION_SSO_ADDR" env-default:"sso-service:50051"`
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"5s"`
+	} `yaml:"revocation"`
+	ServiceAccount struct {
+		// identifies this service to the others, registered in sso-service
+		ClientID     string `yaml:"client_id" env:"SERVICE_CLIENT_ID" env-default:"products-service"`
+		ClientSecret string `yaml:"client_secret" env:"SERVICE_CLIENT_SECRET"`
+	} `yaml:"service_account"`
 }
 
 func MustLoad() *Config {
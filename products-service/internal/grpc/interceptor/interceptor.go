@@ -2,11 +2,17 @@ package interceptor
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/pkg/auth"
+	"github.com/sabirkekw/ecommerce_go/pkg/revocation"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -56,3 +62,90 @@ func TimeoutInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServ
 		return response.resp, response.err
 	}
 }
+
+// AuthInterceptor serves public RPCs as is. For the rest it validates the token, internal RPCs
+// need one issued to a service account and the others have the caller's roles checked against
+// the shared per-RPC policy.
+func AuthInterceptor(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	rule := auth.DefaultPolicy.Rule(serverInfo.FullMethod)
+	if rule.Public {
+		return handler(ctx, req)
+	}
+
+	logger, ok := ctx.Value("logger").(*zap.SugaredLogger)
+	if !ok {
+		panic("failed to recieve logger from context")
+	}
+	keyfunc, ok := ctx.Value("keyfunc").(jwt.Keyfunc)
+	if !ok {
+		panic("failed to recieve jwt keyfunc from context")
+	}
+	revocations, ok := ctx.Value("revocations").(*revocation.List)
+	if !ok {
+		panic("failed to recieve revocation list from context")
+	}
+
+	token, err := tokenFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "no token")
+	}
+
+	claims, err := parseClaims(token, keyfunc)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, status.Errorf(codes.Unauthenticated, "token expired")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	if jti, _ := claims["jti"].(string); revocations.IsRevoked(jti) {
+		return nil, status.Errorf(codes.Unauthenticated, "token revoked")
+	}
+
+	if rule.Service {
+		clientID, ok := auth.ServiceFromClaims(claims)
+		if !ok {
+			logger.Debugw("Service identity required", "method", serverInfo.FullMethod)
+			return nil, status.Errorf(codes.PermissionDenied, "service identity required")
+		}
+		logger.Debugw("Service call", "method", serverInfo.FullMethod, "client_id", clientID)
+		return handler(ctx, req)
+	}
+
+	roles := auth.RolesFromClaims(claims)
+	if !rule.Allows(roles) {
+		logger.Debugw("Permission denied", "method", serverInfo.FullMethod, "roles", roles)
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if rule.SecondFactor && !auth.HasSecondFactor(claims) {
+		logger.Debugw("Second factor required", "method", serverInfo.FullMethod)
+		return nil, status.Errorf(codes.PermissionDenied, "second factor required")
+	}
+
+	return handler(ctx, req)
+}
+
+func tokenFromContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errors.New("missing metadata")
+	}
+
+	token := md["authorization"]
+	if len(token) == 0 {
+		return "", errors.New("missing authorization header")
+	}
+
+	return strings.TrimPrefix(strings.Join(token, ""), "Bearer "), nil
+}
+
+func parseClaims(token string, keyfunc jwt.Keyfunc) (jwt.MapClaims, error) {
+	parsedToken, err := jwt.Parse(token, keyfunc)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims, nil
+}
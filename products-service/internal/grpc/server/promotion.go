@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var promotionKinds = map[proto.PromotionKind]promotions.Kind{
+	proto.PromotionKind_PROMOTION_KIND_PERCENTAGE:   promotions.Percentage,
+	proto.PromotionKind_PROMOTION_KIND_FIXED_AMOUNT: promotions.FixedAmount,
+	proto.PromotionKind_PROMOTION_KIND_BUY_X_GET_Y:  promotions.BuyXGetY,
+}
+
+func (s *Server) CreatePromotion(ctx context.Context, req *proto.CreatePromotionRequest) (*proto.CreatePromotionResponse, error) {
+	if req.Promotion == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "nil request")
+	}
+	kind, ok := promotionKinds[req.Promotion.Kind]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect promotion kind")
+	} else if req.Promotion.Currency != "" && !isCurrencyCode(req.Promotion.Currency) {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect currency")
+	}
+
+	promotion := &promotions.Promotion{
+		Code:                  req.Promotion.Code,
+		Kind:                  kind,
+		PercentOff:            req.Promotion.PercentOff,
+		AmountOff:             req.Promotion.AmountOff,
+		Currency:              req.Promotion.Currency,
+		ProductID:             req.Promotion.ProductId,
+		BuyQuantity:           req.Promotion.BuyQuantity,
+		GetQuantity:           req.Promotion.GetQuantity,
+		MinSubtotal:           req.Promotion.MinSubtotal,
+		MaxRedemptionsPerUser: req.Promotion.MaxRedemptionsPerUser,
+		StartsAt:              unixTime(req.Promotion.StartsAt),
+		EndsAt:                unixTime(req.Promotion.EndsAt),
+	}
+
+	created, err := s.Service.CreatePromotion(ctx, promotion)
+	if err != nil {
+		return nil, promotionError(err)
+	}
+	return &proto.CreatePromotionResponse{Promotion: promotionToProto(created)}, nil
+}
+
+func (s *Server) ListPromotions(ctx context.Context, req *proto.ListPromotionsRequest) (*proto.ListPromotionsResponse, error) {
+	list, err := s.Service.ListPromotions(ctx, req.IncludeDisabled)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	resp := &proto.ListPromotionsResponse{Promotions: make([]*proto.Promotion, 0, len(list))}
+	for _, promotion := range list {
+		resp.Promotions = append(resp.Promotions, promotionToProto(promotion))
+	}
+	return resp, nil
+}
+
+func (s *Server) DisablePromotion(ctx context.Context, req *proto.DisablePromotionRequest) (*proto.DisablePromotionResponse, error) {
+	if req.Id <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+	}
+
+	if err := s.Service.DisablePromotion(ctx, req.Id); err != nil {
+		return nil, promotionError(err)
+	}
+	return &proto.DisablePromotionResponse{}, nil
+}
+
+func (s *Server) GetPromotion(ctx context.Context, req *proto.GetPromotionRequest) (*proto.GetPromotionResponse, error) {
+	if req.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "code is required")
+	} else if req.UserId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect user ID")
+	}
+
+	promotion, redemptions, err := s.Service.GetPromotion(ctx, req.Code, req.UserId)
+	if err != nil {
+		return nil, promotionError(err)
+	}
+	return &proto.GetPromotionResponse{
+		Promotion:       promotionToProto(promotion),
+		UserRedemptions: redemptions,
+	}, nil
+}
+
+func (s *Server) RedeemPromotion(ctx context.Context, req *proto.RedeemPromotionRequest) (*proto.RedeemPromotionResponse, error) {
+	if req.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "code is required")
+	} else if req.UserId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect user ID")
+	} else if req.EventId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "event ID is required")
+	} else if req.Discount < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect discount")
+	}
+
+	if err := s.Service.RedeemPromotion(ctx, req.Code, req.UserId, req.EventId, req.Discount); err != nil {
+		return nil, promotionError(err)
+	}
+	return &proto.RedeemPromotionResponse{}, nil
+}
+
+func promotionError(err error) error {
+	switch {
+	case errors.Is(err, apierrors.ErrInvalidPromotion):
+		return status.Errorf(codes.InvalidArgument, "invalid promotion")
+	case errors.Is(err, apierrors.ErrPromotionExists):
+		return status.Errorf(codes.AlreadyExists, "promotion code already exists")
+	case errors.Is(err, apierrors.ErrProductNotFound):
+		return status.Errorf(codes.NotFound, "product not found")
+	case errors.Is(err, apierrors.ErrPromotionNotFound):
+		return status.Errorf(codes.NotFound, "promotion not found")
+	case errors.Is(err, apierrors.ErrPromotionLimitReached):
+		return status.Errorf(codes.ResourceExhausted, "promotion usage limit reached")
+	case errors.Is(err, apierrors.ErrPromotionNotApplicable):
+		return status.Errorf(codes.FailedPrecondition, "promotion is not active")
+	default:
+		return status.Errorf(codes.Internal, "internal server error")
+	}
+}
+
+func promotionToProto(promotion *promotions.Promotion) *proto.Promotion {
+	resp := &proto.Promotion{
+		Id:                    promotion.ID,
+		Code:                  promotion.Code,
+		PercentOff:            promotion.PercentOff,
+		AmountOff:             promotion.AmountOff,
+		Currency:              promotion.Currency,
+		ProductId:             promotion.ProductID,
+		BuyQuantity:           promotion.BuyQuantity,
+		GetQuantity:           promotion.GetQuantity,
+		MinSubtotal:           promotion.MinSubtotal,
+		MaxRedemptionsPerUser: promotion.MaxRedemptionsPerUser,
+		StartsAt:              timeUnix(promotion.StartsAt),
+		EndsAt:                timeUnix(promotion.EndsAt),
+		DisabledAt:            timeUnix(promotion.DisabledAt),
+	}
+	for protoKind, kind := range promotionKinds {
+		if kind == promotion.Kind {
+			resp.Kind = protoKind
+		}
+	}
+	return resp
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
+
+func timeUnix(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
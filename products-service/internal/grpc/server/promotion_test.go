@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/products-service/internal/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// redemptions stands in for the database behind the real service, so the codes order-service acts on
+// are checked through both layers.
+type redemptions struct {
+	service.Repository
+	err   error
+	codes []string
+}
+
+func (r *redemptions) RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error {
+	r.codes = append(r.codes, code)
+	return r.err
+}
+
+// order-service cancels the order on NotFound and ResourceExhausted and retries anything else, so
+// these codes are a contract.
+func TestRedeemPromotionCodes(t *testing.T) {
+	tests := map[string]struct {
+		storageErr error
+		want       codes.Code
+	}{
+		"redeemed":      {want: codes.OK},
+		"unknown code":  {storageErr: apierrors.ErrPromotionNotFound, want: codes.NotFound},
+		"limit reached": {storageErr: apierrors.ErrPromotionLimitReached, want: codes.ResourceExhausted},
+		"database down": {storageErr: apierrors.ErrUnknown, want: codes.Internal},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			storage := &redemptions{err: tt.storageErr}
+			s := New(service.New(storage, time.Minute, zap.NewNop().Sugar()), zap.NewNop().Sugar())
+
+			_, err := s.RedeemPromotion(context.Background(), &proto.RedeemPromotionRequest{
+				Code: " spring10 ", UserId: 1, EventId: "evt-1", Discount: 500,
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %s, want %s", got, tt.want)
+			}
+			if len(storage.codes) != 1 || storage.codes[0] != "SPRING10" {
+				t.Errorf("storage got codes %q, want the normalized code once", storage.codes)
+			}
+		})
+	}
+}
+
+func TestRedeemPromotionInvalidRequest(t *testing.T) {
+	storage := &redemptions{}
+	s := New(service.New(storage, time.Minute, zap.NewNop().Sugar()), zap.NewNop().Sugar())
+
+	for _, req := range []*proto.RedeemPromotionRequest{
+		{UserId: 1, EventId: "evt-1"},
+		{Code: "SPRING10", EventId: "evt-1"},
+		{Code: "SPRING10", UserId: 1},
+		{Code: "SPRING10", UserId: 1, EventId: "evt-1", Discount: -1},
+	} {
+		if _, err := s.RedeemPromotion(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("RedeemPromotion(%v) code = %s, want %s", req, status.Code(err), codes.InvalidArgument)
+		}
+	}
+	if len(storage.codes) != 0 {
+		t.Errorf("invalid requests reached storage: %q", storage.codes)
+	}
+}
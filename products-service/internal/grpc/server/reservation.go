@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ReserveStock(ctx context.Context, req *proto.ReserveStockRequest) (*proto.ReserveStockResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no items to reserve")
+	}
+
+	items := make([]*product.StockItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductId <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+		} else if item.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "incorrect quantity")
+		}
+		items = append(items, &product.StockItem{
+			ProductID: item.ProductId,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	reservationID, expiresAt, err := s.Service.ReserveStock(ctx, items)
+	if errors.Is(err, apierrors.ErrProductNotFound) {
+		return nil, status.Errorf(codes.NotFound, "product not found")
+	} else if errors.Is(err, apierrors.ErrNotEnoughProduct) {
+		return nil, status.Errorf(codes.FailedPrecondition, "not enough product")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	return &proto.ReserveStockResponse{
+		ReservationId: reservationID,
+		ExpiresAt:     expiresAt.Unix(),
+	}, nil
+}
+
+func (s *Server) CommitReservation(ctx context.Context, req *proto.CommitReservationRequest) (*proto.CommitReservationResponse, error) {
+	if req.ReservationId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect reservation ID")
+	}
+
+	if err := s.Service.CommitReservation(ctx, req.ReservationId); err != nil {
+		return nil, reservationError(err)
+	}
+	return &proto.CommitReservationResponse{}, nil
+}
+
+func (s *Server) ReleaseReservation(ctx context.Context, req *proto.ReleaseReservationRequest) (*proto.ReleaseReservationResponse, error) {
+	if req.ReservationId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect reservation ID")
+	}
+
+	if err := s.Service.ReleaseReservation(ctx, req.ReservationId); err != nil {
+		return nil, reservationError(err)
+	}
+	return &proto.ReleaseReservationResponse{}, nil
+}
+
+func (s *Server) RestockReservation(ctx context.Context, req *proto.RestockReservationRequest) (*proto.RestockReservationResponse, error) {
+	if req.ReservationId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect reservation ID")
+	}
+
+	if err := s.Service.RestockReservation(ctx, req.ReservationId); err != nil {
+		return nil, reservationError(err)
+	}
+	return &proto.RestockReservationResponse{}, nil
+}
+
+func reservationError(err error) error {
+	switch {
+	case errors.Is(err, apierrors.ErrReservationNotFound):
+		return status.Errorf(codes.NotFound, "reservation not found")
+	case errors.Is(err, apierrors.ErrReservationExpired):
+		return status.Errorf(codes.FailedPrecondition, "reservation expired")
+	case errors.Is(err, apierrors.ErrReservationReleased):
+		return status.Errorf(codes.FailedPrecondition, "reservation already released")
+	case errors.Is(err, apierrors.ErrReservationCommitted):
+		return status.Errorf(codes.FailedPrecondition, "reservation already committed")
+	default:
+		return status.Errorf(codes.Internal, "internal server error")
+	}
+}
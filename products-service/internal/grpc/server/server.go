@@ -3,9 +3,11 @@ package grpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	proto "github.com/sabirkekw/ecommerce_go/pkg/api/products"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
 	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -15,8 +17,30 @@ import (
 
 type ProductsService interface {
 	GetProductById(ctx context.Context, id int32) (*product.ProductData, error)
-	GetProducts(ctx context.Context) ([]*product.ProductData, error)
+	GetProductsByIDs(ctx context.Context, ids []int32) ([]*product.ProductData, error)
+	GetProducts(ctx context.Context, filter *product.ListFilter) ([]*product.ProductData, string, error)
+	CreateProduct(ctx context.Context, product *product.ProductData) (*product.ProductData, error)
 	UpdateProduct(ctx context.Context, id int32, product *product.ProductData) (*product.ProductData, error)
+	DeleteProduct(ctx context.Context, id int32) error
+	ReserveStock(ctx context.Context, items []*product.StockItem) (int32, time.Time, error)
+	CommitReservation(ctx context.Context, reservationID int32) error
+	ReleaseReservation(ctx context.Context, reservationID int32) error
+	RestockReservation(ctx context.Context, reservationID int32) error
+	CreatePromotion(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error)
+	ListPromotions(ctx context.Context, includeDisabled bool) ([]*promotions.Promotion, error)
+	DisablePromotion(ctx context.Context, id int64) error
+	GetPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, int32, error)
+	RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error
+}
+
+var sortOrders = map[proto.SortOrder]product.SortOrder{
+	proto.SortOrder_SORT_ORDER_UNSPECIFIED:   product.SortByIDAsc,
+	proto.SortOrder_SORT_ORDER_ID_ASC:        product.SortByIDAsc,
+	proto.SortOrder_SORT_ORDER_ID_DESC:       product.SortByIDDesc,
+	proto.SortOrder_SORT_ORDER_NAME_ASC:      product.SortByNameAsc,
+	proto.SortOrder_SORT_ORDER_NAME_DESC:     product.SortByNameDesc,
+	proto.SortOrder_SORT_ORDER_QUANTITY_ASC:  product.SortByQuantityAsc,
+	proto.SortOrder_SORT_ORDER_QUANTITY_DESC: product.SortByQuantityDesc,
 }
 
 type Server struct {
@@ -57,26 +81,120 @@ func (s *Server) GetProductByID(ctx context.Context, req *proto.GetProductReques
 			ProductName: product.ProductName,
 			Quantity:    product.Quantity,
 			Description: product.Description,
+			Price:       product.Price,
+			Currency:    product.Currency,
 		},
 	}, nil
 }
+func (s *Server) GetProductsByIDs(ctx context.Context, req *proto.GetProductsByIDsRequest) (*proto.GetProductsByIDsResponse, error) {
+	for _, id := range req.Ids {
+		if id <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+		}
+	}
+	if len(req.Ids) == 0 {
+		return &proto.GetProductsByIDsResponse{}, nil
+	}
+
+	products, err := s.Service.GetProductsByIDs(ctx, req.Ids)
+	if errors.Is(err, apierrors.ErrIncorrectID) {
+		return nil, status.Errorf(codes.InvalidArgument, "too many IDs")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	response := make([]*proto.Product, 0, len(products))
+	for _, product := range products {
+		response = append(response, &proto.Product{
+			Id:          product.ID,
+			ProductName: product.ProductName,
+			Quantity:    product.Quantity,
+			Description: product.Description,
+			Price:       product.Price,
+			Currency:    product.Currency,
+		})
+	}
+	return &proto.GetProductsByIDsResponse{
+		Products: response,
+	}, nil
+}
+
 func (s *Server) ListProducts(ctx context.Context, req *proto.ListProductsRequest) (*proto.ListProductsResponse, error) {
-	products, err := s.Service.GetProducts(ctx)
-	if err != nil {
+	if req.PageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect page size")
+	}
+	sortOrder, ok := sortOrders[req.SortOrder]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown sort order")
+	}
+
+	products, nextPageToken, err := s.Service.GetProducts(ctx, &product.ListFilter{
+		PageSize:     req.PageSize,
+		PageToken:    req.PageToken,
+		NameContains: req.NameFilter,
+		InStockOnly:  req.InStockOnly,
+		SortOrder:    sortOrder,
+	})
+	if errors.Is(err, apierrors.ErrInvalidPageToken) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
 
-	response := make([]*proto.Product, 0, 200)
+	response := make([]*proto.Product, 0, len(products))
 	for _, product := range products {
 		response = append(response, &proto.Product{
 			Id:          product.ID,
 			ProductName: product.ProductName,
 			Quantity:    product.Quantity,
 			Description: product.Description,
+			Price:       product.Price,
+			Currency:    product.Currency,
 		})
 	}
 	return &proto.ListProductsResponse{
-		Products: response,
+		Products:      response,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+func (s *Server) CreateProduct(ctx context.Context, req *proto.CreateProductRequest) (*proto.CreateProductResponse, error) {
+	if req.Product == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "nil request")
+	}
+	if req.Product.ProductName == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "product name is required")
+	} else if req.Product.Quantity < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect quantity")
+	} else if req.Product.Price < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect price")
+	} else if !isCurrencyCode(req.Product.Currency) {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect currency")
+	}
+
+	reqProduct := product.ProductData{
+		ProductName: req.Product.ProductName,
+		Quantity:    req.Product.Quantity,
+		Description: req.Product.Description,
+		Price:       req.Product.Price,
+		Currency:    req.Product.Currency,
+	}
+
+	respProduct, err := s.Service.CreateProduct(ctx, &reqProduct)
+	if errors.Is(err, apierrors.ErrProductAlreadyExists) {
+		return nil, status.Errorf(codes.AlreadyExists, "product with name %s already exists", req.Product.ProductName)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	return &proto.CreateProductResponse{
+		CreatedProduct: &proto.Product{
+			Id:          respProduct.ID,
+			ProductName: respProduct.ProductName,
+			Quantity:    respProduct.Quantity,
+			Description: respProduct.Description,
+			Price:       respProduct.Price,
+			Currency:    respProduct.Currency,
+		},
 	}, nil
 }
 func (s *Server) UpdateProduct(ctx context.Context, req *proto.UpdateProductRequest) (*proto.UpdateProductResponse, error) {
@@ -90,14 +208,22 @@ func (s *Server) UpdateProduct(ctx context.Context, req *proto.UpdateProductRequ
 		ProductName: req.Product.ProductName,
 		Quantity:    req.Product.Quantity,
 		Description: req.Product.Description,
+		Price:       req.Product.Price,
+		Currency:    req.Product.Currency,
 	}
 	if id <= 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+	} else if reqProduct.Price < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect price")
+	} else if !isCurrencyCode(reqProduct.Currency) {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect currency")
 	}
 
 	respProduct, err := s.Service.UpdateProduct(ctx, id, &reqProduct)
 	if errors.Is(err, apierrors.ErrProductNotFound) {
 		return nil, status.Errorf(codes.NotFound, "product not found")
+	} else if errors.Is(err, apierrors.ErrProductAlreadyExists) {
+		return nil, status.Errorf(codes.AlreadyExists, "product with name %s already exists", req.Product.ProductName)
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
@@ -108,6 +234,36 @@ func (s *Server) UpdateProduct(ctx context.Context, req *proto.UpdateProductRequ
 			ProductName: respProduct.ProductName,
 			Quantity:    respProduct.Quantity,
 			Description: respProduct.Description,
+			Price:       respProduct.Price,
+			Currency:    respProduct.Currency,
 		},
 	}, nil
 }
+func (s *Server) DeleteProduct(ctx context.Context, req *proto.DeleteProductRequest) (*proto.DeleteProductResponse, error) {
+	id := req.Id
+	if id <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect ID")
+	}
+
+	err := s.Service.DeleteProduct(ctx, id)
+	if errors.Is(err, apierrors.ErrProductNotFound) {
+		return nil, status.Errorf(codes.NotFound, "product not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	return &proto.DeleteProductResponse{}, nil
+}
+
+// isCurrencyCode checks the shape of an ISO 4217 code, not that the currency actually exists.
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
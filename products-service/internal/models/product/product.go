@@ -5,4 +5,43 @@ type ProductData struct {
 	ProductName string
 	Quantity    int32
 	Description string
+	Price       int64 // minor units of Currency
+	Currency    string
+}
+
+type SortOrder string
+
+const (
+	SortByIDAsc        SortOrder = "id_asc"
+	SortByIDDesc       SortOrder = "id_desc"
+	SortByNameAsc      SortOrder = "name_asc"
+	SortByNameDesc     SortOrder = "name_desc"
+	SortByQuantityAsc  SortOrder = "quantity_asc"
+	SortByQuantityDesc SortOrder = "quantity_desc"
+)
+
+type ListFilter struct {
+	PageSize     int32
+	PageToken    string
+	NameContains string
+	InStockOnly  bool
+	SortOrder    SortOrder
+}
+
+// Cursor points at the last product of a page, keyset pagination continues right after it.
+// It carries the filter of the listing it came from, it is only valid for that listing.
+type Cursor struct {
+	SortOrder    SortOrder `json:"s"`
+	NameContains string    `json:"f,omitempty"`
+	InStockOnly  bool      `json:"k,omitempty"`
+	ID           int32     `json:"i"`
+	ProductName  string    `json:"n,omitempty"`
+	Quantity     int32     `json:"q,omitempty"`
+}
+
+// Matches reports whether the cursor continues the listing of filter.
+func (c *Cursor) Matches(filter *ListFilter) bool {
+	return c.SortOrder == filter.SortOrder &&
+		c.NameContains == filter.NameContains &&
+		c.InStockOnly == filter.InStockOnly
 }
@@ -0,0 +1,23 @@
+package product
+
+import "time"
+
+const (
+	ReservationPending   = "pending"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	// ReservationRestocked stock was committed, then returned because the order was cancelled or refunded
+	ReservationRestocked = "restocked"
+)
+
+type StockItem struct {
+	ProductID int32
+	Quantity  int32
+}
+
+type Reservation struct {
+	ID        int32
+	Status    string
+	ExpiresAt time.Time
+	Items     []*StockItem
+}
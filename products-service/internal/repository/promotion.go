@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
+)
+
+var promotionColumns = []string{
+	"id", "code", "kind", "percent_off", "amount_off", "currency", "product_id", "buy_quantity", "get_quantity",
+	"min_subtotal", "max_redemptions_per_user", "starts_at", "ends_at", "disabled_at",
+}
+
+func (r *Repository) CreatePromotion(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error) {
+	const op = "Products.Repository.CreatePromotion"
+	r.logger.Debugw("creating promotion", "code", promotion.Code, "op", op)
+
+	query := r.builder.Insert("promotions").
+		Columns(promotionColumns[1:]...).
+		Values(
+			promotion.Code, promotion.Kind, promotion.PercentOff, promotion.AmountOff,
+			sql.NullString{String: promotion.Currency, Valid: promotion.Currency != ""},
+			sql.NullInt32{Int32: promotion.ProductID, Valid: promotion.ProductID != 0},
+			promotion.BuyQuantity, promotion.GetQuantity, promotion.MinSubtotal, promotion.MaxRedemptionsPerUser,
+			promotion.StartsAt, promotion.EndsAt, promotion.DisabledAt,
+		).
+		Suffix("RETURNING " + strings.Join(promotionColumns, ", "))
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	created, err := scanPromotion(r.db.QueryRowContext(ctx, strSql, args...))
+	if err != nil {
+		if isUniqueViolation(err) {
+			r.logger.Debugw("promotion code already exists", "op", op)
+			return nil, apierrors.ErrPromotionExists
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			r.logger.Debugw("promoted product not found", "product_id", promotion.ProductID, "op", op)
+			return nil, apierrors.ErrProductNotFound
+		}
+		r.logger.Errorw("failed to insert promotion", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return created, nil
+}
+
+func (r *Repository) ListPromotions(ctx context.Context, includeDisabled bool) ([]*promotions.Promotion, error) {
+	const op = "Products.Repository.ListPromotions"
+
+	query := r.builder.Select(promotionColumns...).
+		From("promotions").
+		OrderBy("id")
+	if !includeDisabled {
+		query = query.Where(sq.Eq{"disabled_at": nil})
+	}
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := r.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("failed to read promotions", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var list []*promotions.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			r.logger.Errorw("failed to scan promotion", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		list = append(list, promotion)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("failed to read promotions", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return list, nil
+}
+
+// DisablePromotion keeps the first disabled_at, disabling twice is a no-op.
+func (r *Repository) DisablePromotion(ctx context.Context, id int64) error {
+	const op = "Products.Repository.DisablePromotion"
+	r.logger.Debugw("disabling promotion", "promotion_id", id, "op", op)
+
+	query := r.builder.Update("promotions").
+		Set("disabled_at", sq.Expr("COALESCE(disabled_at, NOW())")).
+		Where(sq.Eq{"id": id})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := r.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("failed to disable promotion", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		r.logger.Debugw("promotion not found", "op", op)
+		return apierrors.ErrPromotionNotFound
+	}
+	return nil
+}
+
+// GetPromotion returns the promotion of code and how many times the user redeemed it.
+func (r *Repository) GetPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, int32, error) {
+	const op = "Products.Repository.GetPromotion"
+
+	promotion, err := r.readPromotion(ctx, r.db, code, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	redemptions, err := r.countRedemptions(ctx, r.db, promotion.ID, userID)
+	if err != nil {
+		r.logger.Errorw("failed to count redemptions", "error", err, "op", op)
+		return nil, 0, apierrors.ErrUnknown
+	}
+	return promotion, redemptions, nil
+}
+
+// RedeemPromotion records that the order of eventID used the coupon. The promotion row is locked, so two
+// orders of the same user can't both take the last redemption. Whether the coupon is still active isn't
+// checked, it was at checkout. Redeeming an event twice is a no-op.
+func (r *Repository) RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error {
+	const op = "Products.Repository.RedeemPromotion"
+	r.logger.Debugw("redeeming promotion", "code", code, "user_id", userID, "event_id", eventID, "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	promotion, err := r.readPromotion(ctx, tx, code, true)
+	if err != nil {
+		return err
+	}
+
+	var redeemed int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM promotion_redemptions WHERE event_id = $1`, eventID).Scan(&redeemed)
+	if err == nil {
+		r.logger.Debugw("event already redeemed", "event_id", eventID, "op", op)
+		return nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Errorw("failed to read redemption", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	redemptions, err := r.countRedemptions(ctx, tx, promotion.ID, userID)
+	if err != nil {
+		r.logger.Errorw("failed to count redemptions", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if promotion.LimitReached(redemptions) {
+		r.logger.Debugw("promotion limit reached", "promotion_id", promotion.ID, "user_id", userID, "op", op)
+		return apierrors.ErrPromotionLimitReached
+	}
+
+	insert := r.builder.Insert("promotion_redemptions").
+		Columns("promotion_id", "user_id", "event_id", "discount").
+		Values(promotion.ID, userID, eventID, discount)
+	strSql, args, err := insert.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		r.logger.Errorw("failed to insert redemption", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+func (r *Repository) readPromotion(ctx context.Context, db queryRower, code string, forUpdate bool) (*promotions.Promotion, error) {
+	const op = "Products.Repository.readPromotion"
+
+	query := r.builder.Select(promotionColumns...).
+		From("promotions").
+		Where(sq.Eq{"code": code})
+	if forUpdate {
+		query = query.Suffix("FOR UPDATE")
+	}
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	promotion, err := scanPromotion(db.QueryRowContext(ctx, strSql, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		r.logger.Debugw("promotion not found", "code", code, "op", op)
+		return nil, apierrors.ErrPromotionNotFound
+	} else if err != nil {
+		r.logger.Errorw("failed to read promotion", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return promotion, nil
+}
+
+func (r *Repository) countRedemptions(ctx context.Context, db queryRower, promotionID int64, userID int32) (int32, error) {
+	var redemptions int32
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1 AND user_id = $2`,
+		promotionID, userID,
+	).Scan(&redemptions)
+	return redemptions, err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPromotion(row scanner) (*promotions.Promotion, error) {
+	var (
+		promotion                    promotions.Promotion
+		currency                     sql.NullString
+		productID                    sql.NullInt32
+		startsAt, endsAt, disabledAt sql.NullTime
+	)
+	if err := row.Scan(
+		&promotion.ID, &promotion.Code, &promotion.Kind, &promotion.PercentOff, &promotion.AmountOff, &currency, &productID,
+		&promotion.BuyQuantity, &promotion.GetQuantity, &promotion.MinSubtotal, &promotion.MaxRedemptionsPerUser,
+		&startsAt, &endsAt, &disabledAt,
+	); err != nil {
+		return nil, err
+	}
+	promotion.Currency = currency.String
+	promotion.ProductID = productID.Int32
+	promotion.StartsAt = nullTime(startsAt)
+	promotion.EndsAt = nullTime(endsAt)
+	promotion.DisabledAt = nullTime(disabledAt)
+	return &promotion, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
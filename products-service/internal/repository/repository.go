@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
 	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
 	"go.uber.org/zap"
@@ -29,7 +31,9 @@ func (r *Repository) ReadProduct(ctx context.Context, id int32) (*product.Produc
 	const op = "Products.Repository.ReadProduct"
 	r.logger.Debugw("reading product from database", "item_id", id, "op", op)
 
-	query := r.builder.Select("*").From("products").Where(sq.Eq{"id": id})
+	query := r.builder.Select("id", "product_name", "quantity", "description", "price", "currency").
+		From("products").
+		Where(sq.Eq{"id": id, "deleted_at": nil})
 
 	strSql, args, err := query.ToSql()
 	if err != nil {
@@ -38,7 +42,7 @@ func (r *Repository) ReadProduct(ctx context.Context, id int32) (*product.Produc
 	}
 
 	var product product.ProductData
-	if err := r.db.QueryRowContext(ctx, strSql, args...).Scan(&product.ID, &product.ProductName, &product.Quantity, &product.Description); err != nil {
+	if err := r.db.QueryRowContext(ctx, strSql, args...).Scan(&product.ID, &product.ProductName, &product.Quantity, &product.Description, &product.Price, &product.Currency); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			r.logger.Debugw("product not found", "op", op)
 			return nil, apierrors.ErrProductNotFound
@@ -51,18 +55,87 @@ func (r *Repository) ReadProduct(ctx context.Context, id int32) (*product.Produc
 	return &product, nil
 }
 
-func (r *Repository) ReadManyProducts(ctx context.Context) ([]*product.ProductData, error) {
+// ReadProductsByIDs leaves out products that don't exist or were deleted.
+func (r *Repository) ReadProductsByIDs(ctx context.Context, ids []int32) ([]*product.ProductData, error) {
+	const op = "Products.Repository.ReadProductsByIDs"
+	r.logger.Debugw("reading products from database", "ids", ids, "op", op)
+
+	query := r.builder.Select("id", "product_name", "quantity", "description", "price", "currency").
+		From("products").
+		Where(sq.Eq{"id": ids, "deleted_at": nil}).
+		OrderBy("id")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := r.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("failed to fetch products", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToReadProduct
+	}
+	defer rows.Close()
+
+	var products []*product.ProductData
+	for rows.Next() {
+		var p product.ProductData
+		if err := rows.Scan(&p.ID, &p.ProductName, &p.Quantity, &p.Description, &p.Price, &p.Currency); err != nil {
+			r.logger.Errorw("failed to scan product", "error", err, "op", op)
+			return nil, apierrors.ErrFailedToReadProduct
+		}
+		products = append(products, &p)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("failed to iterate products", "error", err, "op", op)
+		return nil, apierrors.ErrFailedToReadProduct
+	}
+	return products, nil
+}
+
+func (r *Repository) ReadManyProducts(ctx context.Context, filter *product.ListFilter, cursor *product.Cursor) ([]*product.ProductData, error) {
 	const op = "Products.Repository.ReadManyProducts"
-	r.logger.Debugw("reading all products from database", "op", op)
+	r.logger.Debugw("reading products page from database", "filter", filter, "cursor", cursor, "op", op)
+
+	query := r.builder.Select("id", "product_name", "quantity", "description", "price", "currency").
+		From("products").
+		Where(sq.Eq{"deleted_at": nil})
 
-	query := r.builder.Select("*").From("products")
+	if filter.NameContains != "" {
+		query = query.Where(sq.ILike{"product_name": "%" + escapeLike(filter.NameContains) + "%"})
+	}
+	if filter.InStockOnly {
+		query = query.Where(sq.Gt{"quantity": 0})
+	}
 
-	strSql, _, err := query.ToSql()
+	column, desc := sortColumn(filter.SortOrder)
+	cmp, direction := ">", "ASC"
+	if desc {
+		cmp, direction = "<", "DESC"
+	}
+	if cursor != nil {
+		switch column {
+		case "id":
+			query = query.Where(sq.Expr("id "+cmp+" ?", cursor.ID))
+		case "product_name":
+			query = query.Where(sq.Expr("(product_name, id) "+cmp+" (?, ?)", cursor.ProductName, cursor.ID))
+		case "quantity":
+			query = query.Where(sq.Expr("(quantity, id) "+cmp+" (?, ?)", cursor.Quantity, cursor.ID))
+		}
+	}
+	if column != "id" {
+		query = query.OrderBy(column + " " + direction)
+	}
+	// one extra row tells the service whether there is a next page
+	query = query.OrderBy("id " + direction).Limit(uint64(filter.PageSize) + 1)
+
+	strSql, args, err := query.ToSql()
 	if err != nil {
 		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
 		return nil, apierrors.ErrUnknown
 	}
-	rows, err := r.db.QueryContext(ctx, strSql)
+	rows, err := r.db.QueryContext(ctx, strSql, args...)
 	if err != nil {
 		r.logger.Errorw("failed to execute sql query", "error", err, "op", op)
 		return nil, apierrors.ErrUnknown
@@ -71,7 +144,7 @@ func (r *Repository) ReadManyProducts(ctx context.Context) ([]*product.ProductDa
 	var products []*product.ProductData
 	for rows.Next() {
 		var product product.ProductData
-		if err := rows.Scan(&product.ID, &product.ProductName, &product.Quantity, &product.Description); err != nil {
+		if err := rows.Scan(&product.ID, &product.ProductName, &product.Quantity, &product.Description, &product.Price, &product.Currency); err != nil {
 			r.logger.Debugw("failed to read row", "error", err, "op", op)
 			return nil, apierrors.ErrFailedToReadProduct
 		}
@@ -86,16 +159,45 @@ func (r *Repository) ReadManyProducts(ctx context.Context) ([]*product.ProductDa
 	return products, nil
 }
 
+func (r *Repository) CreateProduct(ctx context.Context, newProduct *product.ProductData) (*product.ProductData, error) {
+	const op = "Products.Repository.CreateProduct"
+	r.logger.Debugw("inserting product into database", "product_name", newProduct.ProductName, "op", op)
+
+	query := r.builder.Insert("products").
+		Columns("product_name", "quantity", "description", "price", "currency").
+		Values(newProduct.ProductName, newProduct.Quantity, newProduct.Description, newProduct.Price, newProduct.Currency).
+		Suffix("RETURNING id, product_name, quantity, description, price, currency")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var createdProduct product.ProductData
+	if err := r.db.QueryRowContext(ctx, strSql, args...).Scan(&createdProduct.ID, &createdProduct.ProductName, &createdProduct.Quantity, &createdProduct.Description, &createdProduct.Price, &createdProduct.Currency); err != nil {
+		if isUniqueViolation(err) {
+			r.logger.Debugw("product already exists", "product_name", newProduct.ProductName, "op", op)
+			return nil, apierrors.ErrProductAlreadyExists
+		}
+		r.logger.Errorw("failed to insert row", "error", err, "query", strSql, "args", args, "op", op)
+		return nil, apierrors.ErrFailedToCreateProduct
+	}
+	return &createdProduct, nil
+}
+
 func (r *Repository) UpdateProduct(ctx context.Context, id int32, oldProduct *product.ProductData) (*product.ProductData, error) {
-	const op = "Products.Repository.ReadManyProducts"
-	r.logger.Debugw("reading all products from database", "op", op)
+	const op = "Products.Repository.UpdateProduct"
+	r.logger.Debugw("updating product in database", "item_id", id, "op", op)
 
 	query := r.builder.Update("products").
 		Set("product_name", oldProduct.ProductName).
 		Set("quantity", oldProduct.Quantity).
 		Set("description", oldProduct.Description).
-		Where(sq.Eq{"id": oldProduct.ID}).
-		Suffix("RETURNING id, product_name, quantity, description")
+		Set("price", oldProduct.Price).
+		Set("currency", oldProduct.Currency).
+		Where(sq.Eq{"id": oldProduct.ID, "deleted_at": nil}).
+		Suffix("RETURNING id, product_name, quantity, description, price, currency")
 
 	strSql, args, err := query.ToSql()
 	if err != nil {
@@ -104,10 +206,13 @@ func (r *Repository) UpdateProduct(ctx context.Context, id int32, oldProduct *pr
 	}
 
 	var newProduct product.ProductData
-	if err := r.db.QueryRowContext(ctx, strSql, args...).Scan(&newProduct.ID, &newProduct.ProductName, &newProduct.Quantity, &newProduct.Description); err != nil {
+	if err := r.db.QueryRowContext(ctx, strSql, args...).Scan(&newProduct.ID, &newProduct.ProductName, &newProduct.Quantity, &newProduct.Description, &newProduct.Price, &newProduct.Currency); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			r.logger.Debugw("product not found", "op", op)
 			return nil, apierrors.ErrProductNotFound
+		} else if isUniqueViolation(err) {
+			r.logger.Debugw("product already exists", "product_name", oldProduct.ProductName, "op", op)
+			return nil, apierrors.ErrProductAlreadyExists
 		} else {
 			r.logger.Errorw("failed to update row", "error", err, "query", strSql, "args", args, "op", op)
 			return nil, apierrors.ErrFailedToUpdateProduct
@@ -115,3 +220,62 @@ func (r *Repository) UpdateProduct(ctx context.Context, id int32, oldProduct *pr
 	}
 	return &newProduct, nil
 }
+
+// DeleteProduct only marks the product as deleted, so existing carts and orders keep their references.
+func (r *Repository) DeleteProduct(ctx context.Context, id int32) error {
+	const op = "Products.Repository.DeleteProduct"
+	r.logger.Debugw("deleting product from database", "item_id", id, "op", op)
+
+	query := r.builder.Update("products").
+		Set("deleted_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := r.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("failed to delete row", "error", err, "query", strSql, "args", args, "op", op)
+		return apierrors.ErrFailedToDeleteProduct
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrFailedToDeleteProduct
+	}
+	if rowsAffected == 0 {
+		r.logger.Debugw("product not found", "op", op)
+		return apierrors.ErrProductNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func sortColumn(order product.SortOrder) (column string, desc bool) {
+	switch order {
+	case product.SortByIDDesc:
+		return "id", true
+	case product.SortByNameAsc:
+		return "product_name", false
+	case product.SortByNameDesc:
+		return "product_name", true
+	case product.SortByQuantityAsc:
+		return "quantity", false
+	case product.SortByQuantityDesc:
+		return "quantity", true
+	default:
+		return "id", false
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
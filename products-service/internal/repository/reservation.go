@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
+)
+
+// ReserveStock takes the requested quantity out of stock right away, so concurrent checkouts can't both get the last unit.
+// Items must be sorted by product ID, that keeps row locks in the same order for every transaction.
+func (r *Repository) ReserveStock(ctx context.Context, items []*product.StockItem, expiresAt time.Time) (int32, error) {
+	const op = "Products.Repository.ReserveStock"
+	r.logger.Debugw("reserving stock", "items", len(items), "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("failed to begin transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var reservationID int32
+	query := r.builder.Insert("reservations").
+		Columns("status", "expires_at").
+		Values(product.ReservationPending, expiresAt).
+		Suffix("RETURNING id")
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	if err := tx.QueryRowContext(ctx, strSql, args...).Scan(&reservationID); err != nil {
+		r.logger.Errorw("failed to insert reservation", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	for _, item := range items {
+		update := r.builder.Update("products").
+			Set("quantity", sq.Expr("quantity - ?", item.Quantity)).
+			Where(sq.Eq{"id": item.ProductID, "deleted_at": nil}).
+			Where(sq.GtOrEq{"quantity": item.Quantity})
+		strSql, args, err := update.ToSql()
+		if err != nil {
+			r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+		result, err := tx.ExecContext(ctx, strSql, args...)
+		if err != nil {
+			r.logger.Errorw("failed to decrement stock", "error", err, "product_id", item.ProductID, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+		if rowsAffected == 0 {
+			return 0, r.missingStockError(ctx, tx, item.ProductID)
+		}
+
+		insert := r.builder.Insert("reservation_items").
+			Columns("reservation_id", "product_id", "quantity").
+			Values(reservationID, item.ProductID, item.Quantity)
+		strSql, args, err = insert.ToSql()
+		if err != nil {
+			r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			r.logger.Errorw("failed to insert reservation item", "error", err, "product_id", item.ProductID, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("failed to commit transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	r.logger.Debugw("stock reserved", "reservation_id", reservationID, "op", op)
+	return reservationID, nil
+}
+
+// CommitReservation makes the stock decrement permanent. Committing twice is a no-op.
+func (r *Repository) CommitReservation(ctx context.Context, reservationID int32) error {
+	const op = "Products.Repository.CommitReservation"
+	r.logger.Debugw("committing reservation", "reservation_id", reservationID, "op", op)
+
+	query := r.builder.Update("reservations").
+		Set("status", product.ReservationCommitted).
+		Where(sq.Eq{"id": reservationID, "status": product.ReservationPending}).
+		Where(sq.Expr("expires_at > NOW()"))
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := r.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("failed to update reservation", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	reservation, err := r.readReservation(ctx, r.db, reservationID)
+	if err != nil {
+		return err
+	}
+	switch reservation.Status {
+	case product.ReservationCommitted:
+		return nil
+	case product.ReservationReleased:
+		return apierrors.ErrReservationReleased
+	default:
+		// still pending but past expires_at, the sweeper will release it
+		return apierrors.ErrReservationExpired
+	}
+}
+
+// ReleaseReservation puts the reserved quantity back into stock. Releasing twice is a no-op.
+func (r *Repository) ReleaseReservation(ctx context.Context, reservationID int32) error {
+	const op = "Products.Repository.ReleaseReservation"
+	r.logger.Debugw("releasing reservation", "reservation_id", reservationID, "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := r.builder.Update("reservations").
+		Set("status", product.ReservationReleased).
+		Where(sq.Eq{"id": reservationID, "status": product.ReservationPending})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		r.logger.Errorw("failed to update reservation", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		reservation, err := r.readReservation(ctx, tx, reservationID)
+		if err != nil {
+			return err
+		}
+		if reservation.Status == product.ReservationCommitted {
+			return apierrors.ErrReservationCommitted
+		}
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE products p SET quantity = p.quantity + ri.quantity
+		FROM reservation_items ri
+		WHERE ri.reservation_id = $1 AND ri.product_id = p.id`,
+		reservationID,
+	); err != nil {
+		r.logger.Errorw("failed to restore stock", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// RestockReservation puts the quantity of a reservation back into stock. A pending reservation is
+// released, a committed one restocked. Restocking twice, or a reservation that was released already,
+// is a no-op.
+func (r *Repository) RestockReservation(ctx context.Context, reservationID int32) error {
+	const op = "Products.Repository.RestockReservation"
+	r.logger.Debugw("restocking reservation", "reservation_id", reservationID, "op", op)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Errorw("failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE reservations SET status = CASE WHEN status = $1 THEN $2 ELSE $3 END
+		WHERE id = $4 AND status IN ($1, $5)`,
+		product.ReservationPending, product.ReservationReleased, product.ReservationRestocked,
+		reservationID, product.ReservationCommitted,
+	)
+	if err != nil {
+		r.logger.Errorw("failed to update reservation", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		// released or restocked already, the stock is back
+		_, err := r.readReservation(ctx, tx, reservationID)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE products p SET quantity = p.quantity + ri.quantity
+		FROM reservation_items ri
+		WHERE ri.reservation_id = $1 AND ri.product_id = p.id`,
+		reservationID,
+	); err != nil {
+		r.logger.Errorw("failed to restore stock", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Errorw("failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// ReleaseExpiredReservations releases every pending reservation past its expires_at in a single statement.
+func (r *Repository) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	const op = "Products.Repository.ReleaseExpiredReservations"
+
+	result, err := r.db.ExecContext(ctx,
+		`WITH expired AS (
+			UPDATE reservations SET status = $1
+			WHERE status = $2 AND expires_at <= NOW()
+			RETURNING id
+		)
+		UPDATE products p SET quantity = p.quantity + released.quantity
+		FROM (
+			SELECT ri.product_id, SUM(ri.quantity) AS quantity
+			FROM reservation_items ri
+			JOIN expired e ON e.id = ri.reservation_id
+			GROUP BY ri.product_id
+		) released
+		WHERE p.id = released.product_id`,
+		product.ReservationReleased, product.ReservationPending,
+	)
+	if err != nil {
+		r.logger.Errorw("failed to release expired reservations", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Errorw("failed to get affected rows", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return rowsAffected, nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (r *Repository) readReservation(ctx context.Context, db queryRower, reservationID int32) (*product.Reservation, error) {
+	const op = "Products.Repository.readReservation"
+
+	query := r.builder.Select("id", "status", "expires_at").
+		From("reservations").
+		Where(sq.Eq{"id": reservationID})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var reservation product.Reservation
+	if err := db.QueryRowContext(ctx, strSql, args...).Scan(&reservation.ID, &reservation.Status, &reservation.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.logger.Debugw("reservation not found", "reservation_id", reservationID, "op", op)
+			return nil, apierrors.ErrReservationNotFound
+		}
+		r.logger.Errorw("failed to read reservation", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return &reservation, nil
+}
+
+// missingStockError tells a deleted or unknown product apart from one that simply ran out.
+func (r *Repository) missingStockError(ctx context.Context, db queryRower, productID int32) error {
+	const op = "Products.Repository.missingStockError"
+
+	query := r.builder.Select("1").
+		From("products").
+		Where(sq.Eq{"id": productID, "deleted_at": nil})
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		r.logger.Errorw("failed to build sql query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	var exists int
+	if err := db.QueryRowContext(ctx, strSql, args...).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.logger.Debugw("product not found", "product_id", productID, "op", op)
+			return apierrors.ErrProductNotFound
+		}
+		r.logger.Errorw("failed to read product", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	r.logger.Debugw("not enough product", "product_id", productID, "op", op)
+	return apierrors.ErrNotEnoughProduct
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
+)
+
+func (s *Service) CreatePromotion(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error) {
+	const op = "Products.Service.CreatePromotion"
+	s.logger.Debugw("creating promotion", "code", promotion.Code, "op", op)
+
+	promotion.Code = promotions.NormalizeCode(promotion.Code)
+	if err := promotion.Validate(); err != nil {
+		s.logger.Debugw("invalid promotion", "op", op)
+		return nil, err
+	}
+
+	created, err := s.storage.CreatePromotion(ctx, promotion)
+	if errors.Is(err, apierrors.ErrPromotionExists) || errors.Is(err, apierrors.ErrProductNotFound) {
+		s.logger.Debugw("failed to create promotion", "error", err, "op", op)
+		return nil, err
+	} else if err != nil {
+		s.logger.Errorw("failed to create promotion", "error", err, "op", op)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *Service) ListPromotions(ctx context.Context, includeDisabled bool) ([]*promotions.Promotion, error) {
+	const op = "Products.Service.ListPromotions"
+	s.logger.Debugw("listing promotions", "include_disabled", includeDisabled, "op", op)
+
+	list, err := s.storage.ListPromotions(ctx, includeDisabled)
+	if err != nil {
+		s.logger.Errorw("failed to list promotions", "error", err, "op", op)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Service) DisablePromotion(ctx context.Context, id int64) error {
+	const op = "Products.Service.DisablePromotion"
+	s.logger.Debugw("disabling promotion", "promotion_id", id, "op", op)
+
+	if err := s.storage.DisablePromotion(ctx, id); err != nil {
+		s.logger.Debugw("failed to disable promotion", "error", err, "op", op)
+		return err
+	}
+	return nil
+}
+
+// GetPromotion returns the promotion of the coupon code and how many times the user redeemed it.
+func (s *Service) GetPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, int32, error) {
+	const op = "Products.Service.GetPromotion"
+	s.logger.Debugw("getting promotion", "user_id", userID, "op", op)
+
+	promotion, redemptions, err := s.storage.GetPromotion(ctx, promotions.NormalizeCode(code), userID)
+	if err != nil {
+		s.logger.Debugw("failed to get promotion", "error", err, "op", op)
+		return nil, 0, err
+	}
+	return promotion, redemptions, nil
+}
+
+// RedeemPromotion counts the order of eventID against the user's limit. The discount was agreed on at
+// checkout and the order may be paid for already, so a promotion disabled or expired since is still
+// redeemed. Only the usage limit, which two checkouts can race for, can fail it.
+func (s *Service) RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error {
+	const op = "Products.Service.RedeemPromotion"
+	s.logger.Debugw("redeeming promotion", "user_id", userID, "event_id", eventID, "op", op)
+
+	err := s.storage.RedeemPromotion(ctx, promotions.NormalizeCode(code), userID, eventID, discount)
+	if errors.Is(err, apierrors.ErrPromotionNotFound) || errors.Is(err, apierrors.ErrPromotionLimitReached) {
+		s.logger.Debugw("failed to redeem promotion", "error", err, "op", op)
+		return err
+	} else if err != nil {
+		s.logger.Errorw("failed to redeem promotion", "error", err, "op", op)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
+)
+
+func (s *Service) ReserveStock(ctx context.Context, items []*product.StockItem) (int32, time.Time, error) {
+	const op = "Products.Service.ReserveStock"
+	s.logger.Debugw("reserving stock", "op", op)
+
+	// merge duplicates and sort, so two reservations never lock the same products in a different order
+	quantities := make(map[int32]int32, len(items))
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+	merged := make([]*product.StockItem, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, &product.StockItem{ProductID: productID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+
+	expiresAt := time.Now().Add(s.reservationTTL)
+	reservationID, err := s.storage.ReserveStock(ctx, merged, expiresAt)
+	if errors.Is(err, apierrors.ErrNotEnoughProduct) || errors.Is(err, apierrors.ErrProductNotFound) {
+		s.logger.Debugw("failed to reserve stock", "error", err, "op", op)
+		return 0, time.Time{}, err
+	} else if err != nil {
+		s.logger.Errorw("failed to reserve stock", "error", err, "op", op)
+		return 0, time.Time{}, err
+	}
+	return reservationID, expiresAt, nil
+}
+
+func (s *Service) CommitReservation(ctx context.Context, reservationID int32) error {
+	const op = "Products.Service.CommitReservation"
+	s.logger.Debugw("committing reservation", "reservation_id", reservationID, "op", op)
+
+	if err := s.storage.CommitReservation(ctx, reservationID); err != nil {
+		s.logger.Debugw("failed to commit reservation", "error", err, "op", op)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) ReleaseReservation(ctx context.Context, reservationID int32) error {
+	const op = "Products.Service.ReleaseReservation"
+	s.logger.Debugw("releasing reservation", "reservation_id", reservationID, "op", op)
+
+	if err := s.storage.ReleaseReservation(ctx, reservationID); err != nil {
+		s.logger.Debugw("failed to release reservation", "error", err, "op", op)
+		return err
+	}
+	return nil
+}
+
+// RestockReservation returns the stock of a cancelled order, whether its reservation was committed yet
+// or not.
+func (s *Service) RestockReservation(ctx context.Context, reservationID int32) error {
+	const op = "Products.Service.RestockReservation"
+	s.logger.Debugw("restocking reservation", "reservation_id", reservationID, "op", op)
+
+	if err := s.storage.RestockReservation(ctx, reservationID); err != nil {
+		s.logger.Debugw("failed to restock reservation", "error", err, "op", op)
+		return err
+	}
+	return nil
+}
+
+// ReleaseExpiredReservations returns stock of abandoned reservations every interval until ctx is cancelled.
+func (s *Service) ReleaseExpiredReservations(ctx context.Context, interval time.Duration) {
+	const op = "Products.Service.ReleaseExpiredReservations"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.storage.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				s.logger.Errorw("failed to release expired reservations", "error", err, "op", op)
+				continue
+			}
+			if released > 0 {
+				s.logger.Infow("released expired reservations", "products_restocked", released, "op", op)
+			}
+		}
+	}
+}
@@ -2,28 +2,52 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/promotions"
 	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
 	"go.uber.org/zap"
 )
 
 type Repository interface {
 	ReadProduct(ctx context.Context, id int32) (*product.ProductData, error)
-	ReadManyProducts(ctx context.Context) ([]*product.ProductData, error)
+	ReadProductsByIDs(ctx context.Context, ids []int32) ([]*product.ProductData, error)
+	ReadManyProducts(ctx context.Context, filter *product.ListFilter, cursor *product.Cursor) ([]*product.ProductData, error)
+	CreateProduct(ctx context.Context, product *product.ProductData) (*product.ProductData, error)
 	UpdateProduct(ctx context.Context, id int32, product *product.ProductData) (*product.ProductData, error)
+	DeleteProduct(ctx context.Context, id int32) error
+	ReserveStock(ctx context.Context, items []*product.StockItem, expiresAt time.Time) (int32, error)
+	CommitReservation(ctx context.Context, reservationID int32) error
+	ReleaseReservation(ctx context.Context, reservationID int32) error
+	RestockReservation(ctx context.Context, reservationID int32) error
+	ReleaseExpiredReservations(ctx context.Context) (int64, error)
+	CreatePromotion(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error)
+	ListPromotions(ctx context.Context, includeDisabled bool) ([]*promotions.Promotion, error)
+	DisablePromotion(ctx context.Context, id int64) error
+	GetPromotion(ctx context.Context, code string, userID int32) (*promotions.Promotion, int32, error)
+	RedeemPromotion(ctx context.Context, code string, userID int32, eventID string, discount int64) error
 }
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type Service struct {
-	storage Repository
-	logger  *zap.SugaredLogger
+	storage        Repository
+	reservationTTL time.Duration
+	logger         *zap.SugaredLogger
 }
 
-func New(storage Repository, logger *zap.SugaredLogger) *Service {
+func New(storage Repository, reservationTTL time.Duration, logger *zap.SugaredLogger) *Service {
 	return &Service{
-		storage: storage,
-		logger:  logger,
+		storage:        storage,
+		reservationTTL: reservationTTL,
+		logger:         logger,
 	}
 }
 
@@ -44,17 +68,85 @@ func (s *Service) GetProductById(ctx context.Context, id int32) (*product.Produc
 	}
 	return product, nil
 }
-func (s *Service) GetProducts(ctx context.Context) ([]*product.ProductData, error) {
-	const op = "Products.Service.GetProducts"
-	s.logger.Debugw("getting all products", "op", op)
+func (s *Service) GetProductsByIDs(ctx context.Context, ids []int32) ([]*product.ProductData, error) {
+	const op = "Products.Service.GetProductsByIDs"
+	s.logger.Debugw("getting products by ids", "count", len(ids), "op", op)
+
+	if len(ids) > maxPageSize {
+		s.logger.Debugw("too many ids", "op", op)
+		return nil, apierrors.ErrIncorrectID
+	}
 
-	products, err := s.storage.ReadManyProducts(ctx)
+	products, err := s.storage.ReadProductsByIDs(ctx, ids)
 	if err != nil {
-		s.logger.Debugw("failed to get all products", "error", err, "op", op)
+		s.logger.Errorw("failed to get products from repository", "error", err, "op", op)
 		return nil, err
 	}
 	return products, nil
 }
+
+func (s *Service) GetProducts(ctx context.Context, filter *product.ListFilter) ([]*product.ProductData, string, error) {
+	const op = "Products.Service.GetProducts"
+	s.logger.Debugw("getting products page", "op", op)
+
+	if filter.SortOrder == "" {
+		filter.SortOrder = product.SortByIDAsc
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultPageSize
+	} else if filter.PageSize > maxPageSize {
+		filter.PageSize = maxPageSize
+	}
+
+	var cursor *product.Cursor
+	if filter.PageToken != "" {
+		decoded, err := decodePageToken(filter.PageToken)
+		if err != nil || !decoded.Matches(filter) {
+			s.logger.Debugw("invalid page token", "error", err, "op", op)
+			return nil, "", apierrors.ErrInvalidPageToken
+		}
+		cursor = decoded
+	}
+
+	products, err := s.storage.ReadManyProducts(ctx, filter, cursor)
+	if err != nil {
+		s.logger.Debugw("failed to get products page", "error", err, "op", op)
+		return nil, "", err
+	}
+
+	if len(products) <= int(filter.PageSize) {
+		return products, "", nil
+	}
+	products = products[:filter.PageSize]
+	last := products[len(products)-1]
+	nextPageToken, err := encodePageToken(&product.Cursor{
+		SortOrder:    filter.SortOrder,
+		NameContains: filter.NameContains,
+		InStockOnly:  filter.InStockOnly,
+		ID:           last.ID,
+		ProductName:  last.ProductName,
+		Quantity:     last.Quantity,
+	})
+	if err != nil {
+		s.logger.Errorw("failed to encode page token", "error", err, "op", op)
+		return nil, "", apierrors.ErrUnknown
+	}
+	return products, nextPageToken, nil
+}
+func (s *Service) CreateProduct(ctx context.Context, product *product.ProductData) (*product.ProductData, error) {
+	const op = "Products.Service.CreateProduct"
+	s.logger.Debugw("creating product", "op", op)
+
+	createdProduct, err := s.storage.CreateProduct(ctx, product)
+	if errors.Is(err, apierrors.ErrProductAlreadyExists) {
+		s.logger.Debugw("product already exists", "product_name", product.ProductName, "op", op)
+		return nil, err
+	} else if err != nil {
+		s.logger.Debugw("failed to create product", "error", err, "op", op)
+		return nil, err
+	}
+	return createdProduct, nil
+}
 func (s *Service) UpdateProduct(ctx context.Context, id int32, product *product.ProductData) (*product.ProductData, error) {
 	const op = "Products.Service.UpdateProduct"
 	s.logger.Debugw("updating product", "op", op)
@@ -72,3 +164,37 @@ func (s *Service) UpdateProduct(ctx context.Context, id int32, product *product.
 	}
 	return updatedProduct, nil
 }
+func (s *Service) DeleteProduct(ctx context.Context, id int32) error {
+	const op = "Products.Service.DeleteProduct"
+	s.logger.Debugw("deleting product", "id", id, "op", op)
+
+	err := s.storage.DeleteProduct(ctx, id)
+	if errors.Is(err, apierrors.ErrProductNotFound) {
+		s.logger.Debugw("product not found", "op", op)
+		return err
+	} else if err != nil {
+		s.logger.Debugw("failed to delete product", "error", err, "op", op)
+		return err
+	}
+	return nil
+}
+
+func encodePageToken(cursor *product.Cursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageToken(token string) (*product.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor product.Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/sabirkekw/ecommerce_go/products-service/internal/models/product"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor product.Cursor
+	}{
+		{
+			name:   "by id",
+			cursor: product.Cursor{SortOrder: product.SortByIDAsc, ID: 42},
+		},
+		{
+			name:   "by name",
+			cursor: product.Cursor{SortOrder: product.SortByNameDesc, ID: 7, ProductName: "Tea, green \"sencha\""},
+		},
+		{
+			name:   "by quantity",
+			cursor: product.Cursor{SortOrder: product.SortByQuantityAsc, ID: 3, Quantity: 0},
+		},
+		{
+			name:   "with filter",
+			cursor: product.Cursor{SortOrder: product.SortByIDDesc, NameContains: "чай", InStockOnly: true, ID: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := encodePageToken(&tt.cursor)
+			if err != nil {
+				t.Fatalf("encodePageToken() error = %v", err)
+			}
+			got, err := decodePageToken(token)
+			if err != nil {
+				t.Fatalf("decodePageToken() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.cursor) {
+				t.Errorf("decodePageToken() = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodePageTokenInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "not a token!"},
+		{name: "padded base64", token: base64.URLEncoding.EncodeToString([]byte(`{"s":"id_asc","i":1}`))},
+		{name: "not json", token: base64.RawURLEncoding.EncodeToString([]byte("id_asc:1"))},
+		{name: "wrong types", token: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"id_asc","i":"1"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePageToken(tt.token); err == nil {
+				t.Errorf("decodePageToken(%q) succeeded, want error", tt.token)
+			}
+		})
+	}
+}
+
+func TestCursorMatches(t *testing.T) {
+	cursor := product.Cursor{SortOrder: product.SortByNameAsc, NameContains: "tea", InStockOnly: true, ID: 5, ProductName: "Green tea"}
+
+	tests := []struct {
+		name   string
+		filter product.ListFilter
+		want   bool
+	}{
+		{
+			name:   "same listing",
+			filter: product.ListFilter{SortOrder: product.SortByNameAsc, NameContains: "tea", InStockOnly: true},
+			want:   true,
+		},
+		{
+			name:   "page size may change",
+			filter: product.ListFilter{PageSize: 50, SortOrder: product.SortByNameAsc, NameContains: "tea", InStockOnly: true},
+			want:   true,
+		},
+		{
+			name:   "other sort order",
+			filter: product.ListFilter{SortOrder: product.SortByNameDesc, NameContains: "tea", InStockOnly: true},
+		},
+		{
+			name:   "other name filter",
+			filter: product.ListFilter{SortOrder: product.SortByNameAsc, NameContains: "coffee", InStockOnly: true},
+		},
+		{
+			name:   "name filter dropped",
+			filter: product.ListFilter{SortOrder: product.SortByNameAsc, InStockOnly: true},
+		},
+		{
+			name:   "stock filter dropped",
+			filter: product.ListFilter{SortOrder: product.SortByNameAsc, NameContains: "tea"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := encodePageToken(&cursor)
+			if err != nil {
+				t.Fatalf("encodePageToken() error = %v", err)
+			}
+			decoded, err := decodePageToken(token)
+			if err != nil {
+				t.Fatalf("decodePageToken() error = %v", err)
+			}
+			if got := decoded.Matches(&tt.filter); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,8 +11,12 @@ import (
 	"github.com/sabirkekw/ecommerce_go/pkg/logger"
 	"github.com/sabirkekw/ecommerce_go/sso-service/internal/app"
 	"github.com/sabirkekw/ecommerce_go/sso-service/internal/config"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/mailer"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/messaging"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/oidc"
 	"github.com/sabirkekw/ecommerce_go/sso-service/internal/repository"
 	authservice "github.com/sabirkekw/ecommerce_go/sso-service/internal/service/auth"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/service/keys"
 )
 
 func main() {
@@ -32,9 +37,83 @@ func main() {
 
 	authRepo := repository.New(logger.Log, db, squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar))
 
-	authService := authservice.New(logger.Log, authRepo, cfg.TokenTTL, cfg.JWTSecret)
+	keySet := keys.New(logger.Log, authRepo, cfg.SigningKeys.RotationInterval, cfg.SigningKeys.RetiredKeyTTL)
+	if err := keySet.Refresh(context.Background()); err != nil {
+		logger.Log.Errorw("Failed to load signing keys", "error", err)
+		return
+	}
+	keysCtx, stopKeys := context.WithCancel(context.Background())
+	defer stopKeys()
+	go keySet.Run(keysCtx, cfg.SigningKeys.ReloadInterval)
+	logger.Log.Infow("Signing keys loaded")
+
+	var mail authservice.Mailer
+	switch cfg.Mailer.Kind {
+	case "file":
+		fileMailer, err := mailer.NewFileMailer(cfg.Mailer.Dir)
+		if err != nil {
+			logger.Log.Errorw("Failed to create file mailer", "error", err)
+			return
+		}
+		mail = fileMailer
+	case "log":
+		// mails carry account tokens, they must not end up in the logs of a real deployment
+		if cfg.Env != "local" {
+			logger.Log.Fatalw("Log mailer is for local runs only", "env", cfg.Env)
+		}
+		mail = mailer.NewLogMailer(logger.Log)
+	default:
+		logger.Log.Errorw("Unknown mailer kind", "kind", cfg.Mailer.Kind)
+		return
+	}
+
+	authService := authservice.New(logger.Log, authRepo, authRepo, cfg.TokenTTL, cfg.RefreshTokenTTL, keySet, mail, authservice.EmailSettings{
+		RequireVerified:       cfg.Email.RequireVerified,
+		VerificationTokenTTL:  cfg.Email.VerificationTokenTTL,
+		PasswordResetTokenTTL: cfg.Email.PasswordResetTokenTTL,
+	}, authservice.ThrottleSettings{
+		Window:          cfg.LoginThrottle.Window,
+		FreeAttempts:    cfg.LoginThrottle.FreeAttempts,
+		BaseDelay:       cfg.LoginThrottle.BaseDelay,
+		MaxDelay:        cfg.LoginThrottle.MaxDelay,
+		AccountLockout:  cfg.LoginThrottle.AccountLockout,
+		LockoutDuration: cfg.LoginThrottle.LockoutDuration,
+		ClientLockout:   cfg.LoginThrottle.ClientLockout,
+	}, authservice.TwoFactorSettings{
+		Issuer:        cfg.TwoFactor.Issuer,
+		ChallengeTTL:  cfg.TwoFactor.ChallengeTTL,
+		RecoveryCodes: cfg.TwoFactor.RecoveryCodes,
+	}, cfg.Kafka.UserEventsTopic, cfg.ServiceAccounts.TokenTTL, authservice.OIDCSettings{
+		Issuer:  cfg.OIDC.Issuer,
+		CodeTTL: cfg.OIDC.CodeTTL,
+	})
+
+	for clientID, secret := range cfg.ServiceAccounts.Clients {
+		if err := authService.RegisterServiceAccount(context.Background(), clientID, secret); err != nil {
+			logger.Log.Fatalw("Failed to register service account", "client_id", clientID, "error", err)
+		}
+	}
+	logger.Log.Infow("Service accounts registered", "count", len(cfg.ServiceAccounts.Clients))
+
+	for _, client := range cfg.OIDC.Clients {
+		if err := authService.RegisterOAuthClient(context.Background(), client.ClientID, client.Name, client.Secret, client.RedirectURIs); err != nil {
+			logger.Log.Errorw("Failed to register OAuth client", "client_id", client.ClientID, "error", err)
+			return
+		}
+	}
+	logger.Log.Infow("OAuth clients registered", "count", len(cfg.OIDC.Clients))
+
+	kafkaProducer := messaging.New(logger.Log, cfg.Kafka.Brokers)
+	defer kafkaProducer.Close()
+
+	outboxRelay := messaging.NewOutboxRelay(logger.Log, authRepo, kafkaProducer, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize)
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go outboxRelay.Run(relayCtx)
+
+	oidcHandler := oidc.New(logger.Log, authService, keySet, cfg.OIDC.Issuer)
 
-	application := app.New(logger.Log, cfg.GRPC.Port, cfg.HTTP.Port, db, authService, cfg.GRPC.Timeout)
+	application := app.New(logger.Log, cfg.GRPC.Port, cfg.HTTP.Port, db, authService, keySet, oidcHandler, cfg.GRPC.Timeout)
 	go application.AuthGRPCServer.Run()
 	logger.Log.Infow("gRPC server started", "auth_port", cfg.GRPC.Port)
 	go application.AuthHTTPServer.Run()
@@ -0,0 +1,131 @@
+// Package address normalizes and validates postal addresses. Which fields are required and what a
+// postal code looks like depends on the country.
+package address
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+const (
+	maxFieldLength = 255
+	maxLabelLength = 64
+)
+
+type countryRules struct {
+	// RequireRegion is set where mail isn't delivered without the state or province
+	RequireRegion bool
+	// PostalCode matches valid postal codes, nil if the country doesn't use them
+	PostalCode *regexp.Regexp
+}
+
+// countries we have rules for. Other countries only need the fields every address has and take
+// any postal code.
+var countries = map[string]countryRules{
+	"US": {RequireRegion: true, PostalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`)},
+	"CA": {RequireRegion: true, PostalCode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`)},
+	"AU": {RequireRegion: true, PostalCode: regexp.MustCompile(`^\d{4}$`)},
+	"JP": {RequireRegion: true, PostalCode: regexp.MustCompile(`^\d{3}-?\d{4}$`)},
+	"GB": {PostalCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"DE": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"ES": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"IT": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"NL": {PostalCode: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+	"PL": {PostalCode: regexp.MustCompile(`^\d{2}-\d{3}$`)},
+	"RU": {PostalCode: regexp.MustCompile(`^\d{6}$`)},
+	"AE": {},
+	"HK": {},
+}
+
+var (
+	countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+	phoneNumber = regexp.MustCompile(`^\+?[0-9 ()-]{5,20}$`)
+)
+
+// ValidationError names the field an address was rejected for.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s %s", apierrors.ErrInvalidAddress, e.Field, e.Reason)
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == apierrors.ErrInvalidAddress
+}
+
+// Normalize trims every field and upper-cases the country and postal code.
+func Normalize(a *models.Address) {
+	a.Label = strings.TrimSpace(a.Label)
+	a.RecipientName = strings.TrimSpace(a.RecipientName)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.City = strings.TrimSpace(a.City)
+	a.Region = strings.TrimSpace(a.Region)
+	a.PostalCode = strings.ToUpper(strings.TrimSpace(a.PostalCode))
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.Phone = strings.TrimSpace(a.Phone)
+}
+
+// Validate checks a normalized address and returns a *ValidationError for the first bad field.
+func Validate(a *models.Address) error {
+	if !countryCode.MatchString(a.Country) {
+		return &ValidationError{Field: "country", Reason: "must be an ISO 3166-1 alpha-2 code"}
+	}
+	rules, known := countries[a.Country]
+
+	required := [][2]string{
+		{"recipient_name", a.RecipientName},
+		{"line1", a.Line1},
+		{"city", a.City},
+	}
+	if rules.RequireRegion {
+		required = append(required, [2]string{"region", a.Region})
+	}
+	for _, field := range required {
+		if field[1] == "" {
+			return &ValidationError{Field: field[0], Reason: "is required"}
+		}
+	}
+
+	limited := [][2]string{
+		{"recipient_name", a.RecipientName},
+		{"line1", a.Line1},
+		{"line2", a.Line2},
+		{"city", a.City},
+		{"region", a.Region},
+	}
+	for _, field := range limited {
+		if len(field[1]) > maxFieldLength {
+			return &ValidationError{Field: field[0], Reason: fmt.Sprintf("must be at most %d characters", maxFieldLength)}
+		}
+	}
+	if len(a.Label) > maxLabelLength {
+		return &ValidationError{Field: "label", Reason: fmt.Sprintf("must be at most %d characters", maxLabelLength)}
+	}
+
+	switch {
+	case known && rules.PostalCode == nil:
+		if a.PostalCode != "" {
+			return &ValidationError{Field: "postal_code", Reason: "is not used in " + a.Country}
+		}
+	case known:
+		if !rules.PostalCode.MatchString(a.PostalCode) {
+			return &ValidationError{Field: "postal_code", Reason: "is not valid for " + a.Country}
+		}
+	case len(a.PostalCode) > 32:
+		return &ValidationError{Field: "postal_code", Reason: "must be at most 32 characters"}
+	}
+
+	if a.Phone != "" && !phoneNumber.MatchString(a.Phone) {
+		return &ValidationError{Field: "phone", Reason: "is not a valid phone number"}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	authgrpcapp "github.com/sabirkekw/ecommerce_go/sso-service/internal/app/grpc"
 	authhttpapp "github.com/sabirkekw/ecommerce_go/sso-service/internal/app/http"
 	authgrpcserver "github.com/sabirkekw/ecommerce_go/sso-service/internal/grpc/auth"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/oidc"
 	"go.uber.org/zap"
 )
 
@@ -16,9 +17,9 @@ type App struct {
 	Storage        *sql.DB
 }
 
-func New(log *zap.SugaredLogger, GRPCPort int, HTTPPort int, storage *sql.DB, authService authgrpcserver.AuthService, timeout time.Duration) *App {
-	authGRPCServer := authgrpcapp.NewGRPCServer(log, GRPCPort, authService, timeout)
-	authHTTPServer := authhttpapp.New(log, HTTPPort, GRPCPort)
+func New(log *zap.SugaredLogger, GRPCPort int, HTTPPort int, storage *sql.DB, authService authgrpcserver.AuthService, keys authgrpcserver.KeyProvider, oidcHandler *oidc.Handler, timeout time.Duration) *App {
+	authGRPCServer := authgrpcapp.NewGRPCServer(log, GRPCPort, authService, keys, timeout)
+	authHTTPServer := authhttpapp.New(log, HTTPPort, GRPCPort, oidcHandler)
 
 	return &App{
 		AuthHTTPServer: authHTTPServer,
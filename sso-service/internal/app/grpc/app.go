@@ -18,7 +18,7 @@ type AuthGRPCApp struct {
 	port   int
 }
 
-func NewGRPCServer(log *zap.SugaredLogger, port int, service authgrpc.AuthService, timeout time.Duration) *AuthGRPCApp {
+func NewGRPCServer(log *zap.SugaredLogger, port int, service authgrpc.AuthService, keys authgrpc.KeyProvider, timeout time.Duration) *AuthGRPCApp {
 	wrappedTimeoutInterceptor := func(ctx context.Context, req any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		ctx = context.WithValue(ctx, "logger", log)
 		ctx = context.WithValue(ctx, "timeout", timeout)
@@ -29,7 +29,7 @@ func NewGRPCServer(log *zap.SugaredLogger, port int, service authgrpc.AuthServic
 		interceptor.LogInterceptor,
 	))
 
-	authgrpc.Register(grpcServer, authgrpc.New(service, log))
+	authgrpc.Register(grpcServer, authgrpc.New(service, keys, log))
 	return &AuthGRPCApp{
 		Logger: log,
 		Server: grpcServer,
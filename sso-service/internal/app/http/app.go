@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	gw "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/oidc"
 )
 
 type AuthHTTPApp struct {
@@ -19,15 +20,17 @@ type AuthHTTPApp struct {
 	Router   *runtime.ServeMux
 	Port     int
 	GRPCPort int
+	OIDC     *oidc.Handler
 }
 
-func New(logger *zap.SugaredLogger, port int, grpcPort int) *AuthHTTPApp {
+func New(logger *zap.SugaredLogger, port int, grpcPort int, oidcHandler *oidc.Handler) *AuthHTTPApp {
 	router := runtime.NewServeMux()
 	return &AuthHTTPApp{
 		Logger:   logger,
 		Router:   router,
 		Port:     port,
 		GRPCPort: grpcPort,
+		OIDC:     oidcHandler,
 	}
 }
 
@@ -42,6 +45,13 @@ func (s *AuthHTTPApp) Run() {
 		panic(err)
 	}
 
+	// the OpenID Connect endpoints are plain HTTP, they don't go through gRPC
+	err = s.OIDC.Register(s.Router)
+	if err != nil {
+		s.Logger.Errorw("failed to register OIDC endpoints", "error", err.Error(), "op", op)
+		panic(err)
+	}
+
 	err = http.ListenAndServe(fmt.Sprintf(":%d", s.Port), s.Router)
 	if err != nil {
 		s.Logger.Errorw("failed to run gRPC gateway", "error", err.Error(), "op", op)
@@ -25,8 +25,64 @@ type Config struct {
 		Port    int           `yaml:"port"`
 		Timeout time.Duration `yaml:"timeout"`
 	} `yaml:"http"`
-	JWTSecret string        `yaml:"jwt_secret"`
-	TokenTTL  time.Duration `yaml:"token_ttl"`
+	TokenTTL        time.Duration `yaml:"token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	SigningKeys     struct {
+		RotationInterval time.Duration `yaml:"rotation_interval" env-default:"720h"`
+		// how long a retired key stays in the JWKS document, must exceed token_ttl
+		RetiredKeyTTL  time.Duration `yaml:"retired_key_ttl" env-default:"24h"`
+		ReloadInterval time.Duration `yaml:"reload_interval" env-default:"1m"`
+	} `yaml:"signing_keys"`
+	Email struct {
+		RequireVerified       bool          `yaml:"require_verified" env:"EMAIL_REQUIRE_VERIFIED" env-default:"false"`
+		VerificationTokenTTL  time.Duration `yaml:"verification_token_ttl" env-default:"24h"`
+		PasswordResetTokenTTL time.Duration `yaml:"password_reset_token_ttl" env-default:"1h"`
+	} `yaml:"email"`
+	Mailer struct {
+		// file, or log for local runs. There is no SMTP mailer yet
+		Kind string `yaml:"kind" env:"MAILER_KIND" env-default:"file"`
+		Dir  string `yaml:"dir" env:"MAILER_DIR" env-default:"./mail"`
+	} `yaml:"mailer"`
+	LoginThrottle struct {
+		Window          time.Duration `yaml:"window" env-default:"15m"`
+		FreeAttempts    int           `yaml:"free_attempts" env-default:"3"`
+		BaseDelay       time.Duration `yaml:"base_delay" env-default:"1s"`
+		MaxDelay        time.Duration `yaml:"max_delay" env-default:"1m"`
+		AccountLockout  int           `yaml:"account_lockout" env-default:"10"`
+		LockoutDuration time.Duration `yaml:"lockout_duration" env-default:"15m"`
+		ClientLockout   int           `yaml:"client_lockout" env-default:"100"`
+	} `yaml:"login_throttle"`
+	TwoFactor struct {
+		Issuer        string        `yaml:"issuer" env-default:"ecommerce"`
+		ChallengeTTL  time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+		RecoveryCodes int           `yaml:"recovery_codes" env-default:"10"`
+	} `yaml:"two_factor"`
+	Kafka struct {
+		Brokers string `yaml:"brokers" env:"KAFKA_BROKERS" env-default:"localhost:9092"`
+		// account deletions are announced here for the other services
+		UserEventsTopic string `yaml:"user_events_topic" env:"KAFKA_USER_EVENTS_TOPIC" env-default:"user-events"`
+	} `yaml:"kafka"`
+	Outbox struct {
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+		BatchSize    uint64        `yaml:"batch_size" env-default:"100"`
+	} `yaml:"outbox"`
+	ServiceAccounts struct {
+		TokenTTL time.Duration `yaml:"token_ttl" env-default:"10m"`
+		// client ID to secret, registered on startup. From the environment as "id:secret,id:secret"
+		Clients map[string]string `yaml:"clients" env:"SERVICE_ACCOUNTS"`
+	} `yaml:"service_accounts"`
+	OIDC struct {
+		// where the HTTP gateway is reachable from the outside, the endpoints in the discovery document start with it
+		Issuer  string        `yaml:"issuer" env:"OIDC_ISSUER" env-default:"http://localhost:8081"`
+		CodeTTL time.Duration `yaml:"code_ttl" env-default:"1m"`
+		// registered on startup, clients without a secret are public and rely on PKCE alone
+		Clients []struct {
+			ClientID     string   `yaml:"client_id"`
+			Name         string   `yaml:"name"`
+			Secret       string   `yaml:"secret"`
+			RedirectURIs []string `yaml:"redirect_uris"`
+		} `yaml:"clients"`
+	} `yaml:"oidc"`
 }
 
 func MustLoad() *Config {
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/address"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthServer) ListAddresses(ctx context.Context, in *proto.ListAddressesRequest) (*proto.ListAddressesResponse, error) {
+	const op = "sso.Auth.Server.ListAddresses"
+	s.logger.Debugw("Recieved ListAddresses request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.auth.ListAddresses(ctx, caller.userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list addresses")
+	}
+
+	resp := &proto.ListAddressesResponse{Addresses: make([]*proto.Address, 0, len(addresses))}
+	for _, a := range addresses {
+		resp.Addresses = append(resp.Addresses, addressToProto(a))
+	}
+	return resp, nil
+}
+
+func (s *AuthServer) CreateAddress(ctx context.Context, in *proto.CreateAddressRequest) (*proto.CreateAddressResponse, error) {
+	const op = "sso.Auth.Server.CreateAddress"
+	s.logger.Debugw("Recieved CreateAddress request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Address == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "address is required")
+	}
+
+	created, err := s.auth.CreateAddress(ctx, caller.userID, addressFromProto(in.Address))
+	if err != nil {
+		return nil, addressError(err, "failed to create address")
+	}
+	return &proto.CreateAddressResponse{Address: addressToProto(created)}, nil
+}
+
+func (s *AuthServer) UpdateAddress(ctx context.Context, in *proto.UpdateAddressRequest) (*proto.UpdateAddressResponse, error) {
+	const op = "sso.Auth.Server.UpdateAddress"
+	s.logger.Debugw("Recieved UpdateAddress request", "address_id", in.Id, "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Id <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address id")
+	}
+	if in.Address == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "address is required")
+	}
+
+	updated := addressFromProto(in.Address)
+	updated.ID = in.Id
+	updated, err = s.auth.UpdateAddress(ctx, caller.userID, updated)
+	if err != nil {
+		return nil, addressError(err, "failed to update address")
+	}
+	return &proto.UpdateAddressResponse{Address: addressToProto(updated)}, nil
+}
+
+func (s *AuthServer) DeleteAddress(ctx context.Context, in *proto.DeleteAddressRequest) (*proto.DeleteAddressResponse, error) {
+	const op = "sso.Auth.Server.DeleteAddress"
+	s.logger.Debugw("Recieved DeleteAddress request", "address_id", in.Id, "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Id <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address id")
+	}
+
+	if err := s.auth.DeleteAddress(ctx, caller.userID, in.Id); err != nil {
+		return nil, addressError(err, "failed to delete address")
+	}
+	return &proto.DeleteAddressResponse{}, nil
+}
+
+func (s *AuthServer) GetCheckoutAddresses(ctx context.Context, in *proto.GetCheckoutAddressesRequest) (*proto.GetCheckoutAddressesResponse, error) {
+	const op = "sso.Auth.Server.GetCheckoutAddresses"
+	s.logger.Debugw("Recieved GetCheckoutAddresses request", "user_id", in.UserId, "op", op)
+
+	// any user id is accepted here, so only other services may ask
+	if _, err := s.authenticateService(ctx); err != nil {
+		return nil, err
+	}
+	if in.UserId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id")
+	}
+
+	shipping, billing, err := s.auth.GetCheckoutAddresses(ctx, in.UserId, in.ShippingAddressId, in.BillingAddressId)
+	if errors.Is(err, apierrors.ErrNoShippingAddress) {
+		return nil, status.Errorf(codes.FailedPrecondition, "no shipping address")
+	} else if err != nil {
+		return nil, addressError(err, "failed to get checkout addresses")
+	}
+	return &proto.GetCheckoutAddressesResponse{
+		Shipping: addressToProto(shipping),
+		Billing:  addressToProto(billing),
+	}, nil
+}
+
+func addressError(err error, internal string) error {
+	var invalid *address.ValidationError
+	if errors.As(err, &invalid) {
+		return status.Errorf(codes.InvalidArgument, "%s %s", invalid.Field, invalid.Reason)
+	} else if errors.Is(err, apierrors.ErrAddressNotFound) {
+		return status.Errorf(codes.NotFound, "address not found")
+	} else if errors.Is(err, apierrors.ErrTooManyAddresses) {
+		return status.Errorf(codes.ResourceExhausted, "address book is full")
+	}
+	return status.Errorf(codes.Internal, "%s", internal)
+}
+
+func addressFromProto(a *proto.Address) *models.Address {
+	return &models.Address{
+		Label:           a.Label,
+		RecipientName:   a.RecipientName,
+		Line1:           a.Line1,
+		Line2:           a.Line2,
+		City:            a.City,
+		Region:          a.Region,
+		PostalCode:      a.PostalCode,
+		Country:         a.Country,
+		Phone:           a.Phone,
+		DefaultShipping: a.DefaultShipping,
+		DefaultBilling:  a.DefaultBilling,
+	}
+}
+
+func addressToProto(a *models.Address) *proto.Address {
+	return &proto.Address{
+		Id:              a.ID,
+		Label:           a.Label,
+		RecipientName:   a.RecipientName,
+		Line1:           a.Line1,
+		Line2:           a.Line2,
+		City:            a.City,
+		Region:          a.Region,
+		PostalCode:      a.PostalCode,
+		Country:         a.Country,
+		Phone:           a.Phone,
+		DefaultShipping: a.DefaultShipping,
+		DefaultBilling:  a.DefaultBilling,
+	}
+}
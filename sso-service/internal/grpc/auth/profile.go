@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthServer) GetMe(ctx context.Context, in *proto.GetMeRequest) (*proto.GetMeResponse, error) {
+	const op = "sso.Auth.Server.GetMe"
+	s.logger.Debugw("Recieved GetMe request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.auth.GetMe(ctx, caller.userID)
+	if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get profile")
+	}
+	return &proto.GetMeResponse{Profile: profileToProto(profile)}, nil
+}
+
+func (s *AuthServer) UpdateProfile(ctx context.Context, in *proto.UpdateProfileRequest) (*proto.UpdateProfileResponse, error) {
+	const op = "sso.Auth.Server.UpdateProfile"
+	s.logger.Debugw("Recieved UpdateProfile request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	firstName, lastName := strings.TrimSpace(in.FirstName), strings.TrimSpace(in.LastName)
+	if firstName == "" || lastName == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "first and last name are required")
+	}
+
+	profile, err := s.auth.UpdateProfile(ctx, caller.userID, firstName, lastName)
+	if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update profile")
+	}
+	return &proto.UpdateProfileResponse{Profile: profileToProto(profile)}, nil
+}
+
+func (s *AuthServer) ChangePassword(ctx context.Context, in *proto.ChangePasswordRequest) (*proto.ChangePasswordResponse, error) {
+	const op = "sso.Auth.Server.ChangePassword"
+	s.logger.Debugw("Recieved ChangePassword request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.OldPassword == "" || in.NewPassword == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "old and new password are required")
+	}
+
+	err = s.auth.ChangePassword(ctx, caller.userID, caller.jti, in.OldPassword, in.NewPassword, clientIP(ctx))
+	if throttledErr := s.throttled(ctx, err); throttledErr != nil {
+		return nil, throttledErr
+	} else if errors.Is(err, apierrors.ErrInvalidCredentials) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credentials")
+	} else if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to change password")
+	}
+	return &proto.ChangePasswordResponse{}, nil
+}
+
+func (s *AuthServer) DeleteAccount(ctx context.Context, in *proto.DeleteAccountRequest) (*proto.DeleteAccountResponse, error) {
+	const op = "sso.Auth.Server.DeleteAccount"
+	s.logger.Debugw("Recieved DeleteAccount request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Password == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "password is required")
+	}
+
+	err = s.auth.DeleteAccount(ctx, caller.userID, in.Password, clientIP(ctx))
+	if throttledErr := s.throttled(ctx, err); throttledErr != nil {
+		return nil, throttledErr
+	} else if errors.Is(err, apierrors.ErrInvalidCredentials) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credentials")
+	} else if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete account")
+	}
+	return &proto.DeleteAccountResponse{}, nil
+}
+
+func profileToProto(profile *models.Profile) *proto.Profile {
+	return &proto.Profile{
+		Id:               profile.User.ID,
+		FirstName:        profile.User.FirstName,
+		LastName:         profile.User.LastName,
+		Email:            profile.User.Email,
+		EmailVerified:    profile.User.EmailVerifiedAt != nil,
+		Roles:            profile.User.Roles,
+		TwoFactorEnabled: profile.TwoFactorEnabled,
+	}
+}
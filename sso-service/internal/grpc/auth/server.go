@@ -2,30 +2,67 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	proto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/jwks"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	authservice "github.com/sabirkekw/ecommerce_go/sso-service/internal/service/auth"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type AuthServer struct {
 	auth   AuthService
+	keys   KeyProvider
 	logger *zap.SugaredLogger
 	proto.UnimplementedAuthServer
 }
 
+type KeyProvider interface {
+	PublicKeys() map[string]ed25519.PublicKey
+}
+
 type AuthService interface {
-	Login(ctx context.Context, email string, password string) (token string, err error)
+	Login(ctx context.Context, email string, password string, clientIP string) (*models.LoginResult, error)
+	VerifySecondFactor(ctx context.Context, challenge string, code string, clientIP string) (*models.TokenPair, error)
+	EnrollTOTP(ctx context.Context, userID int64) (secret string, uri string, err error)
+	ConfirmTOTP(ctx context.Context, userID int64, code string) ([]string, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
 	Register(ctx context.Context, firstName string, lastName string, email string, password string) (userID int64, err error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	ListRevokedTokens(ctx context.Context, afterID int64, limit uint64) ([]*models.RevokedToken, error)
+	GetMe(ctx context.Context, userID int64) (*models.Profile, error)
+	UpdateProfile(ctx context.Context, userID int64, firstName string, lastName string) (*models.Profile, error)
+	ChangePassword(ctx context.Context, userID int64, currentJTI string, oldPassword string, newPassword string, clientIP string) error
+	DeleteAccount(ctx context.Context, userID int64, password string, clientIP string) error
+	ListAddresses(ctx context.Context, userID int64) ([]*models.Address, error)
+	CreateAddress(ctx context.Context, userID int64, address *models.Address) (*models.Address, error)
+	UpdateAddress(ctx context.Context, userID int64, address *models.Address) (*models.Address, error)
+	DeleteAddress(ctx context.Context, userID int64, addressID int64) error
+	GetCheckoutAddresses(ctx context.Context, userID int64, shippingID int64, billingID int64) (shipping *models.Address, billing *models.Address, err error)
+	ClientCredentials(ctx context.Context, clientID string, secret string, clientIP string) (string, time.Duration, error)
 }
 
-func New(auth AuthService, logger *zap.SugaredLogger) *AuthServer {
+func New(auth AuthService, keys KeyProvider, logger *zap.SugaredLogger) *AuthServer {
 	return &AuthServer{
 		auth:   auth,
+		keys:   keys,
 		logger: logger,
 	}
 }
@@ -62,11 +99,188 @@ func (s *AuthServer) Login(ctx context.Context, in *proto.LoginRequest) (*proto.
 		return nil, status.Errorf(codes.InvalidArgument, "email and password are required")
 	}
 
-	token, err := s.auth.Login(ctx, in.Email, in.Password)
-	if errors.Is(err, apierrors.ErrInvalidCredentials) {
+	result, err := s.auth.Login(ctx, in.Email, in.Password, clientIP(ctx))
+	if throttledErr := s.throttled(ctx, err); throttledErr != nil {
+		return nil, throttledErr
+	} else if errors.Is(err, apierrors.ErrInvalidCredentials) {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid credentials")
+	} else if errors.Is(err, apierrors.ErrEmailNotVerified) {
+		return nil, status.Errorf(codes.FailedPrecondition, "email is not verified")
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to log in")
 	}
-	return &proto.LoginResponse{Token: token}, nil
+	if result.Challenge != "" {
+		return &proto.LoginResponse{Challenge: result.Challenge}, nil
+	}
+	return &proto.LoginResponse{Token: result.Tokens.AccessToken, RefreshToken: result.Tokens.RefreshToken}, nil
+}
+
+func (s *AuthServer) RefreshToken(ctx context.Context, in *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	const op = "sso.Auth.Server.RefreshToken"
+	s.logger.Debugw("Recieved RefreshToken request", "op", op)
+
+	if in.RefreshToken == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "refresh token is required")
+	}
+
+	tokens, err := s.auth.RefreshToken(ctx, in.RefreshToken)
+	if errors.Is(err, apierrors.ErrInvalidRefreshToken) || errors.Is(err, apierrors.ErrRefreshTokenReused) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to refresh token")
+	}
+	return &proto.RefreshTokenResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+}
+
+func (s *AuthServer) Logout(ctx context.Context, in *proto.LogoutRequest) (*proto.LogoutResponse, error) {
+	const op = "sso.Auth.Server.Logout"
+	s.logger.Debugw("Recieved Logout request", "op", op)
+
+	if in.RefreshToken == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "refresh token is required")
+	}
+
+	err := s.auth.Logout(ctx, in.RefreshToken)
+	if errors.Is(err, apierrors.ErrInvalidRefreshToken) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to log out")
+	}
+	return &proto.LogoutResponse{}, nil
+}
+
+func (s *AuthServer) VerifyEmail(ctx context.Context, in *proto.VerifyEmailRequest) (*proto.VerifyEmailResponse, error) {
+	const op = "sso.Auth.Server.VerifyEmail"
+	s.logger.Debugw("Recieved VerifyEmail request", "op", op)
+
+	if in.Token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token is required")
+	}
+
+	err := s.auth.VerifyEmail(ctx, in.Token)
+	if errors.Is(err, apierrors.ErrInvalidUserToken) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify email")
+	}
+	return &proto.VerifyEmailResponse{}, nil
+}
+
+func (s *AuthServer) RequestPasswordReset(ctx context.Context, in *proto.RequestPasswordResetRequest) (*proto.RequestPasswordResetResponse, error) {
+	const op = "sso.Auth.Server.RequestPasswordReset"
+	s.logger.Debugw("Recieved RequestPasswordReset request", "op", op)
+
+	if in.Email == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.auth.RequestPasswordReset(ctx, in.Email); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to request password reset")
+	}
+	return &proto.RequestPasswordResetResponse{}, nil
+}
+
+func (s *AuthServer) ResetPassword(ctx context.Context, in *proto.ResetPasswordRequest) (*proto.ResetPasswordResponse, error) {
+	const op = "sso.Auth.Server.ResetPassword"
+	s.logger.Debugw("Recieved ResetPassword request", "op", op)
+
+	if in.Token == "" || in.NewPassword == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token and new password are required")
+	}
+
+	err := s.auth.ResetPassword(ctx, in.Token, in.NewPassword)
+	if errors.Is(err, apierrors.ErrInvalidUserToken) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset password")
+	}
+	return &proto.ResetPasswordResponse{}, nil
+}
+
+func (s *AuthServer) GetJWKS(ctx context.Context, in *proto.GetJWKSRequest) (*proto.GetJWKSResponse, error) {
+	publicKeys := s.keys.PublicKeys()
+
+	kids := make([]string, 0, len(publicKeys))
+	for kid := range publicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]*proto.JWK, 0, len(kids))
+	for _, kid := range kids {
+		jwk := jwks.NewJWK(kid, publicKeys[kid])
+		keys = append(keys, &proto.JWK{
+			Kty: jwk.Kty,
+			Crv: jwk.Crv,
+			Kid: jwk.Kid,
+			Use: jwk.Use,
+			Alg: jwk.Alg,
+			X:   jwk.X,
+		})
+	}
+	return &proto.GetJWKSResponse{Keys: keys}, nil
+}
+
+func (s *AuthServer) ListRevokedTokens(ctx context.Context, in *proto.ListRevokedTokensRequest) (*proto.ListRevokedTokensResponse, error) {
+	const op = "sso.Auth.Server.ListRevokedTokens"
+	s.logger.Debugw("Recieved ListRevokedTokens request", "after_id", in.AfterId, "op", op)
+
+	if _, err := s.authenticateService(ctx); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.auth.ListRevokedTokens(ctx, in.AfterId, uint64(in.Limit))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list revoked tokens")
+	}
+
+	tokens := make([]*proto.RevokedToken, 0, len(revoked))
+	for _, token := range revoked {
+		tokens = append(tokens, &proto.RevokedToken{
+			Id:        token.ID,
+			Jti:       token.JTI,
+			ExpiresAt: token.ExpiresAt.Unix(),
+		})
+	}
+	return &proto.ListRevokedTokensResponse{Tokens: tokens}, nil
+}
+
+// throttled turns a ThrottledError into ResourceExhausted with a retry-after header, it returns nil for other errors.
+func (s *AuthServer) throttled(ctx context.Context, err error) error {
+	const op = "sso.Auth.Server.throttled"
+
+	var throttled *authservice.ThrottledError
+	if !errors.As(err, &throttled) {
+		return nil
+	}
+
+	seconds := strconv.Itoa(int(throttled.RetryAfter / time.Second))
+	if err := grpc.SetHeader(ctx, metadata.Pairs("retry-after", seconds)); err != nil {
+		s.logger.Warnw("failed to set retry-after header", "error", err, "op", op)
+	}
+	return status.Errorf(codes.ResourceExhausted, "too many login attempts, retry after %s seconds", seconds)
+}
+
+// clientIP returns the address the request came from. The HTTP gateway runs in the same process and
+// dials over loopback, so x-forwarded-for is only trusted from there; direct clients can't spoof it.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+				// the gateway appends the address it saw last
+				hops := strings.Split(forwarded[len(forwarded)-1], ",")
+				return strings.TrimSpace(hops[len(hops)-1])
+			}
+		}
+	}
+	return host
 }
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthServer) ClientCredentials(ctx context.Context, in *proto.ClientCredentialsRequest) (*proto.ClientCredentialsResponse, error) {
+	const op = "sso.Auth.Server.ClientCredentials"
+	s.logger.Debugw("Recieved ClientCredentials request", "client_id", in.ClientId, "op", op)
+
+	if in.ClientId == "" || in.ClientSecret == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "client_id and client_secret are required")
+	}
+
+	token, expiresIn, err := s.auth.ClientCredentials(ctx, in.ClientId, in.ClientSecret, clientIP(ctx))
+	if throttledErr := s.throttled(ctx, err); throttledErr != nil {
+		return nil, throttledErr
+	} else if errors.Is(err, apierrors.ErrInvalidClient) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid client credentials")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to issue token")
+	}
+	return &proto.ClientCredentialsResponse{AccessToken: token, ExpiresIn: int64(expiresIn.Seconds())}, nil
+}
+
+// authenticateService lets only tokens issued to service accounts through and returns the client ID.
+func (s *AuthServer) authenticateService(ctx context.Context) (string, error) {
+	claims, err := s.tokenClaims(ctx)
+	if err != nil {
+		return "", err
+	}
+	clientID, ok := auth.ServiceFromClaims(claims)
+	if !ok {
+		return "", status.Errorf(codes.PermissionDenied, "service identity required")
+	}
+	return clientID, nil
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	proto "github.com/sabirkekw/ecommerce_go/pkg/api/sso"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthServer) VerifySecondFactor(ctx context.Context, in *proto.VerifySecondFactorRequest) (*proto.VerifySecondFactorResponse, error) {
+	const op = "sso.Auth.Server.VerifySecondFactor"
+	s.logger.Debugw("Recieved VerifySecondFactor request", "op", op)
+
+	if in.Challenge == "" || in.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "challenge and code are required")
+	}
+
+	tokens, err := s.auth.VerifySecondFactor(ctx, in.Challenge, in.Code, clientIP(ctx))
+	if throttledErr := s.throttled(ctx, err); throttledErr != nil {
+		return nil, throttledErr
+	} else if errors.Is(err, apierrors.ErrInvalidLoginChallenge) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired login challenge")
+	} else if errors.Is(err, apierrors.ErrInvalidSecondFactor) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid code")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify second factor")
+	}
+	return &proto.VerifySecondFactorResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+}
+
+func (s *AuthServer) EnrollTOTP(ctx context.Context, in *proto.EnrollTOTPRequest) (*proto.EnrollTOTPResponse, error) {
+	const op = "sso.Auth.Server.EnrollTOTP"
+	s.logger.Debugw("Recieved EnrollTOTP request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, uri, err := s.auth.EnrollTOTP(ctx, caller.userID)
+	if errors.Is(err, apierrors.ErrTOTPAlreadyEnabled) {
+		return nil, status.Errorf(codes.AlreadyExists, "two-factor authentication is already enabled")
+	} else if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enroll TOTP")
+	}
+	return &proto.EnrollTOTPResponse{Secret: secret, OtpauthUri: uri}, nil
+}
+
+func (s *AuthServer) ConfirmTOTP(ctx context.Context, in *proto.ConfirmTOTPRequest) (*proto.ConfirmTOTPResponse, error) {
+	const op = "sso.Auth.Server.ConfirmTOTP"
+	s.logger.Debugw("Recieved ConfirmTOTP request", "op", op)
+
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "code is required")
+	}
+
+	recoveryCodes, err := s.auth.ConfirmTOTP(ctx, caller.userID, in.Code)
+	if errors.Is(err, apierrors.ErrTOTPNotEnrolled) {
+		return nil, status.Errorf(codes.FailedPrecondition, "TOTP enrollment not started")
+	} else if errors.Is(err, apierrors.ErrTOTPAlreadyEnabled) {
+		return nil, status.Errorf(codes.AlreadyExists, "two-factor authentication is already enabled")
+	} else if errors.Is(err, apierrors.ErrInvalidSecondFactor) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid code")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to confirm TOTP")
+	}
+	return &proto.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// caller is the user an access token was issued to, jti identifies the token itself.
+type caller struct {
+	userID int64
+	jti    string
+}
+
+// authenticate returns the caller the access token in the request metadata belongs to. sso-service
+// signs the tokens itself, so they are checked against its own keys instead of the JWKS endpoint.
+func (s *AuthServer) authenticate(ctx context.Context) (*caller, error) {
+	claims, err := s.tokenClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti != "" {
+		revoked, err := s.auth.IsTokenRevoked(ctx, jti)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check token")
+		}
+		if revoked {
+			return nil, status.Errorf(codes.Unauthenticated, "token revoked")
+		}
+	}
+	return &caller{userID: int64(userID), jti: jti}, nil
+}
+
+// tokenClaims verifies the access token in the request metadata and returns its claims.
+func (s *AuthServer) tokenClaims(ctx context.Context) (jwt.MapClaims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "no token")
+	}
+	token := strings.TrimPrefix(strings.Join(md.Get("authorization"), ""), "Bearer ")
+
+	parsedToken, err := jwt.Parse(token, s.keyfunc)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, status.Errorf(codes.Unauthenticated, "token expired")
+	} else if err != nil || !parsedToken.Valid {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	return claims, nil
+}
+
+func (s *AuthServer) keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys.PublicKeys()[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
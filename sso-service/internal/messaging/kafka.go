@@ -0,0 +1,98 @@
+package messaging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"go.uber.org/zap"
+)
+
+// EventUserDeleted tells the other services to forget or anonymize what they keep about a user.
+const EventUserDeleted = "user.deleted"
+
+type KafkaProducer struct {
+	logger   *zap.SugaredLogger
+	Producer *kafka.Producer
+}
+
+func New(logger *zap.SugaredLogger, brokers string) *KafkaProducer {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": brokers,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return &KafkaProducer{
+		logger:   logger,
+		Producer: producer,
+	}
+}
+
+// Publish blocks until the broker acknowledges the message or ctx is done.
+func (p *KafkaProducer) Publish(ctx context.Context, topic string, key string, payload []byte) error {
+	// not closed on purpose: the delivery report may still arrive after ctx is done
+	deliveryChan := make(chan kafka.Event, 1)
+
+	err := p.Producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:   []byte(key),
+		Value: payload,
+	}, deliveryChan)
+
+	if err != nil {
+		p.logger.Errorw("Failed to produce message", "error", err)
+		return apierrors.ErrUnknown
+	}
+
+	select {
+	case e := <-deliveryChan:
+		m := e.(*kafka.Message)
+		if m.TopicPartition.Error != nil {
+			p.logger.Errorw("Failed to deliver message", "error", m.TopicPartition.Error)
+			return apierrors.ErrUnknown
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (p *KafkaProducer) Close() {
+	p.Producer.Flush(5000)
+	p.Producer.Close()
+}
+
+// NewEventID returns a random ID consumers use to drop redelivered events.
+func NewEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func SerializeUserEvent(eventID string, eventType string, userID int64, occurredAt time.Time) ([]byte, error) {
+	type UserEvent struct {
+		EventID    string    `json:"event_id"`
+		Type       string    `json:"type"`
+		UserID     int64     `json:"user_id"`
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+
+	return json.Marshal(UserEvent{
+		EventID:    eventID,
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: occurredAt,
+	})
+}
@@ -0,0 +1,18 @@
+package models
+
+type Address struct {
+	ID            int64
+	UserID        int64
+	Label         string // "home", "work", whatever helps the user tell addresses apart
+	RecipientName string
+	Line1         string
+	Line2         string
+	City          string
+	Region        string // state, province or prefecture
+	PostalCode    string
+	Country       string // ISO 3166-1 alpha-2, upper case
+	Phone         string
+
+	DefaultShipping bool
+	DefaultBilling  bool
+}
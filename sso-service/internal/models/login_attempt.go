@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type LoginAttempt struct {
+	Email    string
+	UserID   int64 // 0 if no user has the email
+	ClientIP string
+	Success  bool
+}
+
+// LoginFailures summarizes the recent failed attempts for an account or a client address.
+type LoginFailures struct {
+	Count  int
+	LastAt time.Time
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// OAuthClient is an application registered to log users in through OpenID Connect. Public clients,
+// like single page apps, have no secret and rely on PKCE.
+type OAuthClient struct {
+	ID           int64
+	ClientID     string
+	Name         string
+	RedirectURIs []string
+	SecretHash   []byte // nil for public clients
+	DisabledAt   *time.Time
+}
+
+// AuthorizationRequest holds the parameters of a request to the authorization endpoint.
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// AuthorizationCode is handed to the client through the redirect and exchanged once for tokens.
+// Only the hash of the code is stored.
+type AuthorizationCode struct {
+	ID            int64
+	CodeHash      string
+	ClientID      string
+	UserID        int64
+	RedirectURI   string
+	Scope         string
+	Nonce         string
+	CodeChallenge string
+	SecondFactor  bool
+	ExpiresAt     time.Time
+}
+
+// AuthorizationResult is either a code for the client or, if a second factor is needed, a
+// challenge for it.
+type AuthorizationResult struct {
+	Code      string
+	Challenge string
+}
+
+// OIDCTokens is the response of the token endpoint. IDToken is only set for the authorization
+// code grant.
+type OIDCTokens struct {
+	Tokens    *TokenPair
+	IDToken   string
+	ExpiresIn time.Duration
+	Scope     string
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// OutboxMessage is an event waiting in the outbox table to be published to Kafka.
+type OutboxMessage struct {
+	ID        int64
+	Topic     string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ServiceAccount is an internal caller, like cart-service, that authenticates with a client ID
+// and secret instead of a user's password. Only the bcrypt hash of the secret is stored.
+type ServiceAccount struct {
+	ID         int64
+	ClientID   string
+	SecretHash []byte
+	DisabledAt *time.Time // disabled accounts can't get new tokens
+}
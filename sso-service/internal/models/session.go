@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Session is a refresh token issued to a user. Only the hash of the token is stored.
+type Session struct {
+	ID        int64
+	FamilyID  string // shared by every token rotated from the same login
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	RotatedAt *time.Time // set once the token was exchanged for a new one
+	RevokedAt *time.Time // set on logout or when reuse is detected
+	// SecondFactor is set if the login was confirmed with a second factor
+	SecondFactor bool
+	// ClientID is the OAuth client the login was made through, empty for logins to our own apps
+	ClientID string
+
+	// access token issued along with the refresh token, revoked with the session
+	AccessJTI       string
+	AccessExpiresAt time.Time
+}
+
+// RevokedToken is an access token that must be rejected before it expires.
+type RevokedToken struct {
+	ID        int64
+	JTI       string
+	ExpiresAt time.Time
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
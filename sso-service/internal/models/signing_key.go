@@ -0,0 +1,14 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"time"
+)
+
+// SigningKey is an Ed25519 key access tokens are signed with, tokens name it in their kid header.
+type SigningKey struct {
+	Kid        string
+	PrivateKey ed25519.PrivateKey
+	CreatedAt  time.Time
+	RetiredAt  *time.Time // set once a newer key took over signing
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type TOTP struct {
+	UserID       int64
+	Secret       string
+	ConfirmedAt  *time.Time // nil until the user proved the authenticator works
+	LastUsedStep int64
+}
+
+// LoginChallenge is handed out by Login instead of tokens when the user has 2FA enabled. Only the
+// hash of the challenge is stored.
+type LoginChallenge struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ClientIP  string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// LoginResult is either a token pair or, if a second factor is needed, a challenge for it.
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge string
+}
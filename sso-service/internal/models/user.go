@@ -1,11 +1,20 @@
 package models
 
+import "time"
 
 type User struct {
-	ID        int64
-	FirstName string
-	LastName  string
-	Email     string
-	Balance   float64
-	PassHash  []byte
+	ID              int64
+	FirstName       string
+	LastName        string
+	Email           string
+	Balance         float64
+	PassHash        []byte
+	Roles           []string
+	EmailVerifiedAt *time.Time // nil until the user confirms the address
+}
+
+// Profile is what a user sees about their own account.
+type Profile struct {
+	User             *User
+	TwoFactorEnabled bool
 }
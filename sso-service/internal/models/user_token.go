@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposePasswordReset     = "password_reset"
+)
+
+// UserToken is a single use token mailed to a user. Only the hash of the token is stored.
+type UserToken struct {
+	ID        int64
+	UserID    int64
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
@@ -0,0 +1,195 @@
+package oidc
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	authservice "github.com/sabirkekw/ecommerce_go/sso-service/internal/service/auth"
+)
+
+// loginPage asks for the password, or for the second factor once a challenge is set. The
+// parameters of the authorization request travel along in hidden fields.
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in</title></head>
+<body>
+<h1>Sign in to {{.ClientName}}</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<form method="post" action="/oauth/authorize">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}{{if .Challenge}}<input type="hidden" name="challenge" value="{{.Challenge}}">
+<label>Authenticator or recovery code <input name="code" autocomplete="one-time-code" required autofocus></label>
+{{else}}<label>Email <input type="email" name="email" value="{{.Email}}" autocomplete="username" required autofocus></label>
+<label>Password <input type="password" name="password" autocomplete="current-password" required></label>
+{{end}}<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+type loginView struct {
+	ClientName string
+	Params     map[string]string
+	Email      string
+	Challenge  string
+	Error      string
+}
+
+// Authorize starts the authorization code flow: it checks the request and shows the login page.
+func (h *Handler) Authorize(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	const op = "sso.OIDC.Authorize"
+
+	req := authorizationRequest(r.URL.Query())
+	client, err := h.service.ValidateAuthorizationRequest(r.Context(), req)
+	if err != nil {
+		h.logger.Debugw("Invalid authorization request", "client_id", req.ClientID, "error", err, "op", op)
+		h.authorizationError(w, r, req, err)
+		return
+	}
+
+	h.renderLogin(w, http.StatusOK, &loginView{ClientName: clientName(client), Params: authorizationParams(req)})
+}
+
+// Login takes the submitted login page. Once the user is in, the browser is sent back to the
+// client with an authorization code.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	const op = "sso.OIDC.Login"
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	req := authorizationRequest(r.PostForm)
+	client, err := h.service.ValidateAuthorizationRequest(r.Context(), req)
+	if err != nil {
+		h.logger.Debugw("Invalid authorization request", "client_id", req.ClientID, "error", err, "op", op)
+		h.authorizationError(w, r, req, err)
+		return
+	}
+	view := &loginView{ClientName: clientName(client), Params: authorizationParams(req), Email: r.PostForm.Get("email")}
+
+	var result *models.AuthorizationResult
+	if challenge := r.PostForm.Get("challenge"); challenge != "" {
+		view.Challenge = challenge
+		result, err = h.service.AuthorizeWithSecondFactor(r.Context(), req, challenge, r.PostForm.Get("code"), clientIP(r))
+	} else {
+		result, err = h.service.AuthorizeWithPassword(r.Context(), req, r.PostForm.Get("email"), r.PostForm.Get("password"), clientIP(r))
+	}
+
+	var throttled *authservice.ThrottledError
+	switch {
+	case err == nil && result.Challenge != "":
+		view.Challenge = result.Challenge
+		h.renderLogin(w, http.StatusOK, view)
+	case err == nil:
+		h.logger.Debugw("Authorization code issued", "client_id", req.ClientID, "op", op)
+		redirect(w, r, req.RedirectURI, url.Values{"code": {result.Code}, "state": {req.State}})
+	case errors.As(err, &throttled):
+		view.Error = "Too many failed attempts, try again in " + throttled.RetryAfter.Round(time.Second).String() + "."
+		h.renderLogin(w, http.StatusTooManyRequests, view)
+	case errors.Is(err, apierrors.ErrInvalidCredentials):
+		view.Error = "Invalid email or password."
+		h.renderLogin(w, http.StatusUnauthorized, view)
+	case errors.Is(err, apierrors.ErrEmailNotVerified):
+		view.Error = "Confirm your email address before signing in."
+		h.renderLogin(w, http.StatusForbidden, view)
+	case errors.Is(err, apierrors.ErrInvalidSecondFactor):
+		view.Error = "Invalid code."
+		h.renderLogin(w, http.StatusUnauthorized, view)
+	case errors.Is(err, apierrors.ErrInvalidLoginChallenge):
+		view.Challenge = ""
+		view.Error = "The sign in expired, start again."
+		h.renderLogin(w, http.StatusUnauthorized, view)
+	default:
+		h.logger.Debugw("Authorization failed", "client_id", req.ClientID, "error", err, "op", op)
+		h.authorizationError(w, r, req, err)
+	}
+}
+
+// authorizationError reports errors of the authorization request. Without a trusted redirect URI
+// they can only be shown to the user, the rest go back to the client as RFC 6749 demands.
+func (h *Handler) authorizationError(w http.ResponseWriter, r *http.Request, req *models.AuthorizationRequest, err error) {
+	var oauthErr *authservice.OAuthError
+	switch {
+	case errors.Is(err, apierrors.ErrInvalidClient):
+		http.Error(w, "unknown client", http.StatusBadRequest)
+	case errors.Is(err, apierrors.ErrInvalidRedirectURI):
+		http.Error(w, "redirect_uri is not registered for this client", http.StatusBadRequest)
+	case errors.As(err, &oauthErr):
+		redirect(w, r, req.RedirectURI, url.Values{
+			"error":             {oauthErr.Code},
+			"error_description": {oauthErr.Description},
+			"state":             {req.State},
+		})
+	default:
+		redirect(w, r, req.RedirectURI, url.Values{"error": {"server_error"}, "state": {req.State}})
+	}
+}
+
+func (h *Handler) renderLogin(w http.ResponseWriter, status int, view *loginView) {
+	const op = "sso.OIDC.renderLogin"
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// the page takes passwords, keep it out of frames
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	w.WriteHeader(status)
+	if err := loginPage.Execute(w, view); err != nil {
+		h.logger.Warnw("failed to render login page", "error", err, "op", op)
+	}
+}
+
+func redirect(w http.ResponseWriter, r *http.Request, redirectURI string, params url.Values) {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	query := target.Query()
+	for name, values := range params {
+		if len(values) > 0 && values[0] != "" {
+			query.Set(name, values[0])
+		}
+	}
+	target.RawQuery = query.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func clientName(client *models.OAuthClient) string {
+	if client.Name != "" {
+		return client.Name
+	}
+	return client.ClientID
+}
+
+func authorizationRequest(params url.Values) *models.AuthorizationRequest {
+	return &models.AuthorizationRequest{
+		ResponseType:        params.Get("response_type"),
+		ClientID:            params.Get("client_id"),
+		RedirectURI:         params.Get("redirect_uri"),
+		Scope:               params.Get("scope"),
+		State:               params.Get("state"),
+		Nonce:               params.Get("nonce"),
+		CodeChallenge:       params.Get("code_challenge"),
+		CodeChallengeMethod: params.Get("code_challenge_method"),
+	}
+}
+
+func authorizationParams(req *models.AuthorizationRequest) map[string]string {
+	return map[string]string{
+		"response_type":         req.ResponseType,
+		"client_id":             req.ClientID,
+		"redirect_uri":          req.RedirectURI,
+		"scope":                 req.Scope,
+		"state":                 req.State,
+		"nonce":                 req.Nonce,
+		"code_challenge":        req.CodeChallenge,
+		"code_challenge_method": req.CodeChallengeMethod,
+	}
+}
@@ -0,0 +1,185 @@
+// Package oidc serves the OpenID Connect provider endpoints of sso-service: discovery, the
+// authorization code flow with PKCE, the token endpoint and userinfo. OAuth 2.0 needs redirects and
+// form posts the gRPC gateway can't express, so these are plain HTTP handlers on the gateway mux.
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	authservice "github.com/sabirkekw/ecommerce_go/sso-service/internal/service/auth"
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	ValidateAuthorizationRequest(ctx context.Context, req *models.AuthorizationRequest) (*models.OAuthClient, error)
+	AuthorizeWithPassword(ctx context.Context, req *models.AuthorizationRequest, email string, password string, clientIP string) (*models.AuthorizationResult, error)
+	AuthorizeWithSecondFactor(ctx context.Context, req *models.AuthorizationRequest, challenge string, code string, clientIP string) (*models.AuthorizationResult, error)
+	ExchangeAuthorizationCode(ctx context.Context, clientID string, clientSecret string, code string, redirectURI string, codeVerifier string) (*models.OIDCTokens, error)
+	RefreshOIDCToken(ctx context.Context, clientID string, clientSecret string, refreshToken string) (*models.OIDCTokens, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	GetMe(ctx context.Context, userID int64) (*models.Profile, error)
+}
+
+type KeyProvider interface {
+	PublicKeys() map[string]ed25519.PublicKey
+}
+
+type Handler struct {
+	logger  *zap.SugaredLogger
+	service Service
+	keys    KeyProvider
+	issuer  string
+}
+
+func New(logger *zap.SugaredLogger, service Service, keys KeyProvider, issuer string) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+		keys:    keys,
+		issuer:  strings.TrimSuffix(issuer, "/"),
+	}
+}
+
+// Register adds the endpoints to the gateway mux.
+func (h *Handler) Register(mux *runtime.ServeMux) error {
+	routes := []struct {
+		method  string
+		path    string
+		handler runtime.HandlerFunc
+	}{
+		{http.MethodGet, "/.well-known/openid-configuration", h.Discovery},
+		{http.MethodGet, "/oauth/authorize", h.Authorize},
+		{http.MethodPost, "/oauth/authorize", h.Login},
+		{http.MethodPost, "/oauth/token", h.Token},
+		{http.MethodGet, "/oauth/userinfo", h.UserInfo},
+		{http.MethodPost, "/oauth/userinfo", h.UserInfo},
+	}
+	for _, route := range routes {
+		if err := mux.HandlePath(route.method, route.path, route.handler); err != nil {
+			return fmt.Errorf("failed to register %s %s: %w", route.method, route.path, err)
+		}
+	}
+	return nil
+}
+
+// Discovery serves the provider metadata clients configure themselves from.
+func (h *Handler) Discovery(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                h.issuer,
+		"authorization_endpoint":                h.issuer + "/oauth/authorize",
+		"token_endpoint":                        h.issuer + "/oauth/token",
+		"userinfo_endpoint":                     h.issuer + "/oauth/userinfo",
+		"jwks_uri":                              h.issuer + "/.well-known/jwks.json",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"EdDSA"},
+		"scopes_supported":                      []string{authservice.ScopeOpenID, authservice.ScopeProfile, authservice.ScopeEmail},
+		"token_endpoint_auth_methods_supported": []string{"none", "client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{authservice.CodeChallengeS256},
+		"claims_supported": []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "amr",
+			"name", "given_name", "family_name", "email", "email_verified",
+		},
+	})
+}
+
+// UserInfo returns the claims of the user the access token belongs to.
+func (h *Handler) UserInfo(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	const op = "sso.OIDC.UserInfo"
+
+	userID, err := h.authenticate(r)
+	if err != nil {
+		h.logger.Debugw("Userinfo token rejected", "error", err, "op", op)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+
+	profile, err := h.service.GetMe(r.Context(), userID)
+	if err != nil {
+		h.logger.Warnw("failed to get profile", "user_id", userID, "error", err, "op", op)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+
+	claims := map[string]any{"sub": fmt.Sprint(userID)}
+	for name, value := range authservice.ProfileClaims(profile.User) {
+		claims[name] = value
+	}
+	for name, value := range authservice.EmailClaims(profile.User) {
+		claims[name] = value
+	}
+	writeJSON(w, http.StatusOK, claims)
+}
+
+// authenticate returns the user of the bearer token. sso-service signs the tokens itself, so they
+// are checked against its own keys.
+func (h *Handler) authenticate(r *http.Request) (int64, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return 0, errors.New("no bearer token")
+	}
+
+	parsedToken, err := jwt.Parse(token, h.keyfunc)
+	if err != nil || !parsedToken.Valid {
+		return 0, errors.New("invalid token")
+	}
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid token")
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, errors.New("not a user token")
+	}
+
+	if jti, _ := claims["jti"].(string); jti != "" {
+		revoked, err := h.service.IsTokenRevoked(r.Context(), jti)
+		if err != nil {
+			return 0, err
+		}
+		if revoked {
+			return 0, errors.New("token revoked")
+		}
+	}
+	return int64(userID), nil
+}
+
+func (h *Handler) keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := h.keys.PublicKeys()[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
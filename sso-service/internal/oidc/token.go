@@ -0,0 +1,94 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// Token serves the authorization code and refresh token grants. Confidential clients authenticate
+// with HTTP basic auth or client_secret in the form, public clients only send client_id.
+func (h *Handler) Token(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	const op = "sso.OIDC.Token"
+
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, basicAuth := r.BasicAuth()
+	if !basicAuth {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID == "" {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	var (
+		tokens *models.OIDCTokens
+		err    error
+	)
+	switch grantType := r.PostForm.Get("grant_type"); grantType {
+	case "authorization_code":
+		code, redirectURI, verifier := r.PostForm.Get("code"), r.PostForm.Get("redirect_uri"), r.PostForm.Get("code_verifier")
+		if code == "" || redirectURI == "" || verifier == "" {
+			tokenError(w, http.StatusBadRequest, "invalid_request")
+			return
+		}
+		tokens, err = h.service.ExchangeAuthorizationCode(r.Context(), clientID, clientSecret, code, redirectURI, verifier)
+	case "refresh_token":
+		refreshToken := r.PostForm.Get("refresh_token")
+		if refreshToken == "" {
+			tokenError(w, http.StatusBadRequest, "invalid_request")
+			return
+		}
+		tokens, err = h.service.RefreshOIDCToken(r.Context(), clientID, clientSecret, refreshToken)
+	default:
+		h.logger.Debugw("Unsupported grant type", "client_id", clientID, "grant_type", grantType, "op", op)
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	switch {
+	case errors.Is(err, apierrors.ErrInvalidClient):
+		if basicAuth {
+			w.Header().Set("WWW-Authenticate", `Basic realm="sso"`)
+		}
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	case errors.Is(err, apierrors.ErrInvalidGrant),
+		errors.Is(err, apierrors.ErrInvalidRefreshToken),
+		errors.Is(err, apierrors.ErrRefreshTokenReused):
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	case err != nil:
+		h.logger.Warnw("failed to issue tokens", "client_id", clientID, "error", err, "op", op)
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &tokenResponse{
+		AccessToken:  tokens.Tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		RefreshToken: tokens.Tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		Scope:        tokens.Scope,
+	})
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
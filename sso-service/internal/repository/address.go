@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+var addressColumns = []string{
+	"id", "user_id", "label", "recipient_name", "line1", "line2", "city", "region", "postal_code", "country", "phone",
+	"is_default_shipping", "is_default_billing",
+}
+
+// CreateAddress adds an address to the user's address book. If it is flagged as a default, the
+// previous default of that kind loses the flag.
+func (s *UserRepository) CreateAddress(ctx context.Context, address *models.Address) (int64, error) {
+	const op = "sso.Auth.Repository.CreateAddress"
+	s.logger.Debugw("Creating address", "user_id", address.UserID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if err := s.clearDefaultAddresses(ctx, tx, address); err != nil {
+		return 0, err
+	}
+
+	query := s.builder.Insert("addresses").
+		Columns("user_id", "label", "recipient_name", "line1", "line2", "city", "region", "postal_code", "country", "phone",
+			"is_default_shipping", "is_default_billing").
+		Values(address.UserID, address.Label, address.RecipientName, address.Line1, address.Line2, address.City, address.Region,
+			address.PostalCode, address.Country, address.Phone, address.DefaultShipping, address.DefaultBilling).
+		Suffix("RETURNING id")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, strSql, args...).Scan(&id); err != nil {
+		s.logger.Warnw("failed to insert address", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return id, nil
+}
+
+// UpdateAddress replaces every field of an address of the user, default flags included.
+func (s *UserRepository) UpdateAddress(ctx context.Context, address *models.Address) error {
+	const op = "sso.Auth.Repository.UpdateAddress"
+	s.logger.Debugw("Updating address", "user_id", address.UserID, "address_id", address.ID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if err := s.clearDefaultAddresses(ctx, tx, address); err != nil {
+		return err
+	}
+
+	query := s.builder.Update("addresses").
+		Set("label", address.Label).
+		Set("recipient_name", address.RecipientName).
+		Set("line1", address.Line1).
+		Set("line2", address.Line2).
+		Set("city", address.City).
+		Set("region", address.Region).
+		Set("postal_code", address.PostalCode).
+		Set("country", address.Country).
+		Set("phone", address.Phone).
+		Set("is_default_shipping", address.DefaultShipping).
+		Set("is_default_billing", address.DefaultBilling).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": address.ID, "user_id": address.UserID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to update address", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrAddressNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+func (s *UserRepository) DeleteAddress(ctx context.Context, userID int64, addressID int64) error {
+	const op = "sso.Auth.Repository.DeleteAddress"
+	s.logger.Debugw("Deleting address", "user_id", userID, "address_id", addressID, "op", op)
+
+	strSql, args, err := s.builder.Delete("addresses").Where(sq.Eq{"id": addressID, "user_id": userID}).ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := s.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to delete address", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrAddressNotFound
+	}
+	return nil
+}
+
+func (s *UserRepository) GetAddress(ctx context.Context, userID int64, addressID int64) (*models.Address, error) {
+	const op = "sso.Auth.Repository.GetAddress"
+
+	return s.getAddress(ctx, op, sq.Eq{"id": addressID, "user_id": userID})
+}
+
+// GetDefaultAddress returns the user's default shipping address, or the default billing address
+// if billing is set. apierrors.ErrAddressNotFound means there is none.
+func (s *UserRepository) GetDefaultAddress(ctx context.Context, userID int64, billing bool) (*models.Address, error) {
+	const op = "sso.Auth.Repository.GetDefaultAddress"
+
+	flag := "is_default_shipping"
+	if billing {
+		flag = "is_default_billing"
+	}
+	return s.getAddress(ctx, op, sq.Eq{"user_id": userID, flag: true})
+}
+
+func (s *UserRepository) ListAddresses(ctx context.Context, userID int64) ([]*models.Address, error) {
+	const op = "sso.Auth.Repository.ListAddresses"
+
+	query := s.builder.Select(addressColumns...).
+		From("addresses").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("id")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := s.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to list addresses", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var addresses []*models.Address
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			s.logger.Warnw("failed to scan address", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Warnw("failed to iterate addresses", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return addresses, nil
+}
+
+func (s *UserRepository) getAddress(ctx context.Context, op string, where sq.Eq) (*models.Address, error) {
+	strSql, args, err := s.builder.Select(addressColumns...).From("addresses").Where(where).ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	address, err := scanAddress(s.db.QueryRowContext(ctx, strSql, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrAddressNotFound
+	} else if err != nil {
+		s.logger.Warnw("failed to get address", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return address, nil
+}
+
+// clearDefaultAddresses drops the default flags address is about to take from the user's other
+// addresses, so the unique indexes on the flags hold.
+func (s *UserRepository) clearDefaultAddresses(ctx context.Context, tx *sql.Tx, address *models.Address) error {
+	const op = "sso.Auth.Repository.clearDefaultAddresses"
+
+	for flag, set := range map[string]bool{
+		"is_default_shipping": address.DefaultShipping,
+		"is_default_billing":  address.DefaultBilling,
+	} {
+		if !set {
+			continue
+		}
+
+		query := s.builder.Update("addresses").
+			Set(flag, false).
+			Where(sq.Eq{"user_id": address.UserID, flag: true}).
+			Where(sq.NotEq{"id": address.ID})
+
+		strSql, args, err := query.ToSql()
+		if err != nil {
+			s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			s.logger.Warnw("failed to clear default address", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAddress(row rowScanner) (*models.Address, error) {
+	var address models.Address
+	err := row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.Label,
+		&address.RecipientName,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.Region,
+		&address.PostalCode,
+		&address.Country,
+		&address.Phone,
+		&address.DefaultShipping,
+		&address.DefaultBilling,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
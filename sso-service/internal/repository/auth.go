@@ -25,10 +25,18 @@ func New(logger *zap.SugaredLogger, db *sql.DB, builder sq.StatementBuilderType)
 	}
 }
 
-func (s *UserRepository) CreateUser(ctx context.Context, firstName string, lastName string, email string, hash []byte) (int64, error) {
+// CreateUser inserts the user together with its roles.
+func (s *UserRepository) CreateUser(ctx context.Context, firstName string, lastName string, email string, hash []byte, roles []string) (int64, error) {
 	const op = "sso.Auth.Repository.CreateUser"
 	s.logger.Debugw("Creating new user", "email", email, "op", op)
 
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
 	query := s.builder.Insert("users").
 		Columns("first_name", "last_name", "email", "pass_hash").
 		Values(firstName, lastName, email, hash).
@@ -41,11 +49,33 @@ func (s *UserRepository) CreateUser(ctx context.Context, firstName string, lastN
 	}
 
 	var id int64
-	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(&id)
+	err = tx.QueryRowContext(ctx, strSql, args...).Scan(&id)
 	if err != nil {
 		s.logger.Debugw("Failed to execute SQL query", "error", err, "op", op)
 		return 0, apierrors.ErrUnknown
 	}
+
+	if len(roles) > 0 {
+		rolesQuery := s.builder.Insert("user_roles").Columns("user_id", "role")
+		for _, role := range roles {
+			rolesQuery = rolesQuery.Values(id, role)
+		}
+
+		strSql, args, err = rolesQuery.ToSql()
+		if err != nil {
+			s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			s.logger.Debugw("Failed to insert user roles", "error", err, "op", op)
+			return 0, apierrors.ErrUnknown
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
 	return id, nil
 }
 
@@ -53,7 +83,7 @@ func (s *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	const op = "sso.Auth.Repository.GetByEmail"
 	s.logger.Debugw("Getting user by email", "email", email, "op", op)
 
-	query := s.builder.Select("*").
+	query := s.builder.Select("id", "first_name", "last_name", "email", "pass_hash", "email_verified_at").
 		From("users").
 		Where(sq.Eq{"email": email})
 
@@ -63,15 +93,103 @@ func (s *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		return nil, err
 	}
 
-	var user models.User
+	var (
+		user       models.User
+		verifiedAt sql.NullTime
+	)
 	row := s.db.QueryRowContext(ctx, strSql, args...)
-	if err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PassHash); err != nil {
+	if err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PassHash, &verifiedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			s.logger.Debugw("User not found", "email", email, "op", op)
 			return nil, apierrors.ErrNoUser
 		}
-		s.logger.Warnf("failed to get user by email", "error", err, "op", op)
+		s.logger.Warnw("failed to get user by email", "error", err, "op", op)
+		return nil, err
+	}
+
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+
+	user.Roles, err = s.GetRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *UserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	const op = "sso.Auth.Repository.GetByID"
+	s.logger.Debugw("Getting user by id", "user_id", id, "op", op)
+
+	query := s.builder.Select("id", "first_name", "last_name", "email", "pass_hash", "email_verified_at").
+		From("users").
+		Where(sq.Eq{"id": id})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, err
+	}
+
+	var (
+		user       models.User
+		verifiedAt sql.NullTime
+	)
+	row := s.db.QueryRowContext(ctx, strSql, args...)
+	if err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PassHash, &verifiedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Debugw("User not found", "user_id", id, "op", op)
+			return nil, apierrors.ErrNoUser
+		}
+		s.logger.Warnw("failed to get user by id", "error", err, "op", op)
+		return nil, err
+	}
+
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+
+	user.Roles, err = s.GetRoles(ctx, user.ID)
+	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+func (s *UserRepository) GetRoles(ctx context.Context, userID int64) ([]string, error) {
+	const op = "sso.Auth.Repository.GetRoles"
+
+	query := s.builder.Select("role").
+		From("user_roles").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("role")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := s.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to get user roles", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			s.logger.Warnw("failed to scan user role", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Warnw("failed to iterate user roles", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return roles, nil
+}
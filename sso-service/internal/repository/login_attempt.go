@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+func (s *UserRepository) RecordLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	const op = "sso.Auth.Repository.RecordLoginAttempt"
+
+	var userID sql.NullInt64
+	if attempt.UserID != 0 {
+		userID = sql.NullInt64{Int64: attempt.UserID, Valid: true}
+	}
+
+	query := s.builder.Insert("login_attempts").
+		Columns("email", "user_id", "client_ip", "success").
+		Values(attempt.Email, userID, attempt.ClientIP, attempt.Success)
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to record login attempt", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// GetAccountLoginFailures counts the failed attempts for the email since the given time. A
+// successful login resets the count.
+func (s *UserRepository) GetAccountLoginFailures(ctx context.Context, email string, since time.Time) (*models.LoginFailures, error) {
+	// left with ? placeholders, the outer query numbers them
+	lastSuccess := sq.Select("MAX(created_at)").
+		From("login_attempts").
+		Where(sq.Eq{"email": email, "success": true})
+
+	return s.getLoginFailures(ctx, "sso.Auth.Repository.GetAccountLoginFailures", sq.And{
+		sq.Eq{"email": email},
+		sq.Gt{"created_at": since},
+		sq.Expr("created_at > COALESCE((?), 'epoch')", lastSuccess),
+	})
+}
+
+// GetClientLoginFailures counts the failed attempts from the address since the given time. Unlike
+// the account count it is not reset by a success, an attacker could log into their own account.
+func (s *UserRepository) GetClientLoginFailures(ctx context.Context, clientIP string, since time.Time) (*models.LoginFailures, error) {
+	return s.getLoginFailures(ctx, "sso.Auth.Repository.GetClientLoginFailures", sq.And{
+		sq.Eq{"client_ip": clientIP},
+		sq.Gt{"created_at": since},
+	})
+}
+
+func (s *UserRepository) getLoginFailures(ctx context.Context, op string, where sq.And) (*models.LoginFailures, error) {
+	query := s.builder.Select("COUNT(*)", "MAX(created_at)").
+		From("login_attempts").
+		Where(where).
+		Where(sq.Eq{"success": false})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var (
+		failures models.LoginFailures
+		lastAt   sql.NullTime
+	)
+	if err := s.db.QueryRowContext(ctx, strSql, args...).Scan(&failures.Count, &lastAt); err != nil {
+		s.logger.Warnw("failed to count login failures", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	failures.LastAt = lastAt.Time
+	return &failures, nil
+}
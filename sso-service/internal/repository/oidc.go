@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+// SaveOAuthClient registers a client or replaces the name, redirect URIs and secret of an existing
+// one. A disabled client stays disabled.
+func (s *UserRepository) SaveOAuthClient(ctx context.Context, client *models.OAuthClient) error {
+	const op = "sso.Auth.Repository.SaveOAuthClient"
+	s.logger.Debugw("Saving OAuth client", "client_id", client.ClientID, "op", op)
+
+	var secretHash sql.NullString
+	if client.SecretHash != nil {
+		secretHash = sql.NullString{String: string(client.SecretHash), Valid: true}
+	}
+
+	query := s.builder.Insert("oauth_clients").
+		Columns("client_id", "name", "redirect_uris", "secret_hash").
+		Values(client.ClientID, client.Name, pq.Array(client.RedirectURIs), secretHash).
+		Suffix("ON CONFLICT (client_id) DO UPDATE SET name = EXCLUDED.name, redirect_uris = EXCLUDED.redirect_uris, secret_hash = EXCLUDED.secret_hash")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to save OAuth client", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// GetOAuthClient returns apierrors.ErrInvalidClient for unknown client IDs.
+func (s *UserRepository) GetOAuthClient(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	const op = "sso.Auth.Repository.GetOAuthClient"
+
+	query := s.builder.Select("id", "client_id", "name", "redirect_uris", "secret_hash", "disabled_at").
+		From("oauth_clients").
+		Where(sq.Eq{"client_id": clientID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var (
+		client     models.OAuthClient
+		secretHash sql.NullString
+		disabledAt sql.NullTime
+	)
+	err = s.db.QueryRowContext(ctx, strSql, args...).
+		Scan(&client.ID, &client.ClientID, &client.Name, pq.Array(&client.RedirectURIs), &secretHash, &disabledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrInvalidClient
+	} else if err != nil {
+		s.logger.Warnw("failed to get OAuth client", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	if secretHash.Valid {
+		client.SecretHash = []byte(secretHash.String)
+	}
+	if disabledAt.Valid {
+		client.DisabledAt = &disabledAt.Time
+	}
+	return &client, nil
+}
+
+func (s *UserRepository) CreateAuthorizationCode(ctx context.Context, code *models.AuthorizationCode) error {
+	const op = "sso.Auth.Repository.CreateAuthorizationCode"
+	s.logger.Debugw("Creating authorization code", "client_id", code.ClientID, "user_id", code.UserID, "op", op)
+
+	query := s.builder.Insert("oauth_authorization_codes").
+		Columns("code_hash", "client_id", "user_id", "redirect_uri", "scope", "nonce", "code_challenge", "second_factor", "expires_at").
+		Values(code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, code.Scope, code.Nonce, code.CodeChallenge, code.SecondFactor, code.ExpiresAt)
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to create authorization code", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// UseAuthorizationCode marks an unused, unexpired code as used and returns it. Marking and reading
+// is one statement, so a code can't be exchanged twice by concurrent requests.
+// apierrors.ErrInvalidGrant means there is no such code left.
+func (s *UserRepository) UseAuthorizationCode(ctx context.Context, codeHash string) (*models.AuthorizationCode, error) {
+	const op = "sso.Auth.Repository.UseAuthorizationCode"
+
+	query := s.builder.Update("oauth_authorization_codes").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"code_hash": codeHash, "used_at": nil}).
+		Where(sq.Expr("expires_at > NOW()")).
+		Suffix("RETURNING id, code_hash, client_id, user_id, redirect_uri, scope, nonce, code_challenge, second_factor, expires_at")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var code models.AuthorizationCode
+	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(
+		&code.ID,
+		&code.CodeHash,
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		&code.Scope,
+		&code.Nonce,
+		&code.CodeChallenge,
+		&code.SecondFactor,
+		&code.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrInvalidGrant
+	} else if err != nil {
+		s.logger.Warnw("failed to use authorization code", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return &code, nil
+}
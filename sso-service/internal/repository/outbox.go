@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+func (s *UserRepository) FetchPendingOutbox(ctx context.Context, limit uint64) ([]*models.OutboxMessage, error) {
+	const op = "sso.Auth.Repository.FetchPendingOutbox"
+
+	query := s.builder.Select("id", "topic", "message_key", "payload", "created_at").
+		From("outbox").
+		Where(sq.Eq{"sent_at": nil}).
+		OrderBy("id").
+		Limit(limit)
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := s.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to fetch pending outbox messages", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var messages []*models.OutboxMessage
+	for rows.Next() {
+		var message models.OutboxMessage
+		if err := rows.Scan(&message.ID, &message.Topic, &message.Key, &message.Payload, &message.CreatedAt); err != nil {
+			s.logger.Warnw("failed to scan outbox message", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		messages = append(messages, &message)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Warnw("failed to iterate outbox messages", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return messages, nil
+}
+
+func (s *UserRepository) MarkOutboxSent(ctx context.Context, id int64) error {
+	const op = "sso.Auth.Repository.MarkOutboxSent"
+
+	query := s.builder.Update("outbox").
+		Set("sent_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to mark outbox message as sent", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+func (s *UserRepository) UpdateProfile(ctx context.Context, userID int64, firstName string, lastName string) error {
+	const op = "sso.Auth.Repository.UpdateProfile"
+	s.logger.Debugw("Updating profile", "user_id", userID, "op", op)
+
+	query := s.builder.Update("users").
+		Set("first_name", firstName).
+		Set("last_name", lastName).
+		Where(sq.Eq{"id": userID})
+
+	return s.execOnce(ctx, op, query, apierrors.ErrNoUser)
+}
+
+// ChangePassword sets a new password hash and revokes every session of the user except the one
+// the access token currentJTI belongs to, so the user stays logged in where the change was made.
+func (s *UserRepository) ChangePassword(ctx context.Context, userID int64, passHash []byte, currentJTI string) error {
+	const op = "sso.Auth.Repository.ChangePassword"
+	s.logger.Debugw("Changing password", "user_id", userID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := s.builder.Update("users").
+		Set("pass_hash", passHash).
+		Where(sq.Eq{"id": userID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to update password", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrNoUser
+	}
+
+	otherSessions := sq.And{
+		sq.Eq{"user_id": userID},
+		sq.Expr("family_id NOT IN (SELECT family_id FROM sessions WHERE access_jti = ?)", currentJTI),
+	}
+	if err := s.revokeSessions(ctx, tx, otherSessions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// DeleteUser revokes the user's tokens, deletes the account and stores the given event in one
+// transaction. Everything keyed by the user goes with it through ON DELETE CASCADE, the login
+// audit log keeps its rows but loses the email.
+func (s *UserRepository) DeleteUser(ctx context.Context, userID int64, message *models.OutboxMessage) error {
+	const op = "sso.Auth.Repository.DeleteUser"
+	s.logger.Debugw("Deleting user", "user_id", userID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if err := s.revokeSessions(ctx, tx, sq.Eq{"user_id": userID}); err != nil {
+		return err
+	}
+
+	auditQuery := s.builder.Update("login_attempts").
+		Set("email", "").
+		Where(sq.Eq{"user_id": userID})
+
+	strSql, args, err := auditQuery.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to anonymize login attempts", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	strSql, args, err = s.builder.Delete("users").Where(sq.Eq{"id": userID}).ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to delete user", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrNoUser
+	}
+
+	outboxQuery := s.builder.Insert("outbox").
+		Columns("topic", "message_key", "payload").
+		Values(message.Topic, message.Key, message.Payload)
+
+	strSql, args, err = outboxQuery.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to write user event to outbox", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+// SaveServiceAccount registers a service account or replaces the secret of an existing one.
+// A disabled account stays disabled.
+func (s *UserRepository) SaveServiceAccount(ctx context.Context, clientID string, secretHash []byte) error {
+	const op = "sso.Auth.Repository.SaveServiceAccount"
+	s.logger.Debugw("Saving service account", "client_id", clientID, "op", op)
+
+	query := s.builder.Insert("service_accounts").
+		Columns("client_id", "secret_hash").
+		Values(clientID, string(secretHash)).
+		Suffix("ON CONFLICT (client_id) DO UPDATE SET secret_hash = EXCLUDED.secret_hash")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to save service account", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// GetServiceAccount returns apierrors.ErrInvalidClient for unknown client IDs.
+func (s *UserRepository) GetServiceAccount(ctx context.Context, clientID string) (*models.ServiceAccount, error) {
+	const op = "sso.Auth.Repository.GetServiceAccount"
+
+	query := s.builder.Select("id", "client_id", "secret_hash", "disabled_at").
+		From("service_accounts").
+		Where(sq.Eq{"client_id": clientID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var (
+		account    models.ServiceAccount
+		secretHash string
+		disabledAt sql.NullTime
+	)
+	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(&account.ID, &account.ClientID, &secretHash, &disabledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrInvalidClient
+	} else if err != nil {
+		s.logger.Warnw("failed to get service account", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	account.SecretHash = []byte(secretHash)
+	if disabledAt.Valid {
+		account.DisabledAt = &disabledAt.Time
+	}
+	return &account, nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+func (s *UserRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	const op = "sso.Auth.Repository.CreateSession"
+	s.logger.Debugw("Creating session", "user_id", session.UserID, "op", op)
+
+	return s.insertSession(ctx, s.db, session)
+}
+
+func (s *UserRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	const op = "sso.Auth.Repository.GetSessionByTokenHash"
+
+	query := s.builder.Select("id", "family_id", "user_id", "token_hash", "expires_at", "rotated_at", "revoked_at", "second_factor", "client_id").
+		From("sessions").
+		Where(sq.Eq{"token_hash": tokenHash})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var (
+		session   models.Session
+		rotatedAt sql.NullTime
+		revokedAt sql.NullTime
+		clientID  sql.NullString
+	)
+	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(
+		&session.ID,
+		&session.FamilyID,
+		&session.UserID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&rotatedAt,
+		&revokedAt,
+		&session.SecondFactor,
+		&clientID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrSessionNotFound
+	} else if err != nil {
+		s.logger.Warnw("failed to get session", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	session.ClientID = clientID.String
+	if rotatedAt.Valid {
+		session.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return &session, nil
+}
+
+// RotateSession marks the old refresh token used and stores its replacement. It fails with
+// apierrors.ErrRefreshTokenReused if the old token was used or revoked in the meantime.
+func (s *UserRepository) RotateSession(ctx context.Context, oldSessionID int64, next *models.Session) error {
+	const op = "sso.Auth.Repository.RotateSession"
+	s.logger.Debugw("Rotating session", "session_id", oldSessionID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := s.builder.Update("sessions").
+		Set("rotated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": oldSessionID, "rotated_at": nil, "revoked_at": nil})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to rotate session", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrRefreshTokenReused
+	}
+
+	if err := s.insertSession(ctx, tx, next); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// RevokeSessionFamily ends the login the family belongs to: every refresh token in it stops working
+// and the access tokens issued with them are put on the revocation list.
+func (s *UserRepository) RevokeSessionFamily(ctx context.Context, familyID string) error {
+	const op = "sso.Auth.Repository.RevokeSessionFamily"
+	s.logger.Debugw("Revoking session family", "family_id", familyID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if err := s.revokeSessions(ctx, tx, sq.Eq{"family_id": familyID}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// ListRevokedTokens returns unexpired revocations with an ID greater than afterID, in ID order.
+func (s *UserRepository) ListRevokedTokens(ctx context.Context, afterID int64, limit uint64) ([]*models.RevokedToken, error) {
+	const op = "sso.Auth.Repository.ListRevokedTokens"
+
+	query := s.builder.Select("id", "jti", "expires_at").
+		From("revoked_tokens").
+		Where(sq.Gt{"id": afterID}).
+		Where(sq.Expr("expires_at > NOW()")).
+		OrderBy("id").
+		Limit(limit)
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := s.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to get revoked tokens", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var tokens []*models.RevokedToken
+	for rows.Next() {
+		var token models.RevokedToken
+		if err := rows.Scan(&token.ID, &token.JTI, &token.ExpiresAt); err != nil {
+			s.logger.Warnw("failed to scan revoked token", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		tokens = append(tokens, &token)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Warnw("failed to iterate revoked tokens", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return tokens, nil
+}
+
+// revokeSessions revokes the matching sessions and puts their unexpired access tokens on the revocation list.
+func (s *UserRepository) revokeSessions(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) error {
+	const op = "sso.Auth.Repository.revokeSessions"
+
+	query := s.builder.Update("sessions").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(where).
+		Where(sq.Eq{"revoked_at": nil})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to revoke sessions", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	revokeQuery := s.builder.Insert("revoked_tokens").
+		Columns("jti", "expires_at").
+		Select(s.builder.Select("access_jti", "access_expires_at").
+			From("sessions").
+			Where(where).
+			Where("access_jti IS NOT NULL AND access_expires_at > NOW()")).
+		Suffix("ON CONFLICT (jti) DO NOTHING")
+
+	strSql, args, err = revokeQuery.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to revoke access tokens", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether the access token with the given jti is on the revocation list.
+func (s *UserRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	const op = "sso.Auth.Repository.IsTokenRevoked"
+
+	query := s.builder.Select("1").
+		From("revoked_tokens").
+		Where(sq.Eq{"jti": jti})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+
+	var found int
+	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		s.logger.Warnw("failed to check revoked token", "error", err, "op", op)
+		return false, apierrors.ErrUnknown
+	}
+	return true, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (s *UserRepository) insertSession(ctx context.Context, db execer, session *models.Session) error {
+	const op = "sso.Auth.Repository.insertSession"
+
+	query := s.builder.Insert("sessions").
+		Columns("family_id", "user_id", "token_hash", "expires_at", "access_jti", "access_expires_at", "second_factor", "client_id").
+		Values(session.FamilyID, session.UserID, session.TokenHash, session.ExpiresAt, session.AccessJTI, session.AccessExpiresAt, session.SecondFactor,
+			sql.NullString{String: session.ClientID, Valid: session.ClientID != ""})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if _, err := db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to insert session", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
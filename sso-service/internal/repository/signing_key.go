@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+// CreateSigningKey stores the new key and retires every other key in the same transaction.
+func (s *UserRepository) CreateSigningKey(ctx context.Context, key *models.SigningKey) error {
+	const op = "sso.Auth.Repository.CreateSigningKey"
+	s.logger.Debugw("Creating signing key", "kid", key.Kid, "op", op)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		s.logger.Warnw("failed to marshal signing key", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	retireQuery := s.builder.Update("signing_keys").
+		Set("retired_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"retired_at": nil})
+
+	strSql, args, err := retireQuery.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to retire signing keys", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	insertQuery := s.builder.Insert("signing_keys").
+		Columns("kid", "private_key", "created_at").
+		Values(key.Kid, der, key.CreatedAt)
+
+	strSql, args, err = insertQuery.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to insert signing key", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// ListSigningKeys returns the active key and keys retired after retiredAfter, newest first.
+func (s *UserRepository) ListSigningKeys(ctx context.Context, retiredAfter time.Time) ([]*models.SigningKey, error) {
+	const op = "sso.Auth.Repository.ListSigningKeys"
+
+	query := s.builder.Select("kid", "private_key", "created_at", "retired_at").
+		From("signing_keys").
+		Where(sq.Or{
+			sq.Eq{"retired_at": nil},
+			sq.Gt{"retired_at": retiredAfter},
+		}).
+		OrderBy("created_at DESC")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	rows, err := s.db.QueryContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to get signing keys", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	defer rows.Close()
+
+	var keys []*models.SigningKey
+	for rows.Next() {
+		var (
+			key       models.SigningKey
+			der       []byte
+			retiredAt sql.NullTime
+		)
+		if err := rows.Scan(&key.Kid, &der, &key.CreatedAt, &retiredAt); err != nil {
+			s.logger.Warnw("failed to scan signing key", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+
+		parsed, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			s.logger.Warnw("failed to parse signing key", "error", err, "kid", key.Kid, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		privateKey, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			s.logger.Warnw("signing key is not ed25519", "kid", key.Kid, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		key.PrivateKey = privateKey
+
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Warnw("failed to iterate signing keys", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	return keys, nil
+}
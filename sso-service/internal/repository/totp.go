@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+// SaveTOTPSecret starts an enrollment, replacing the secret of an unconfirmed one. Confirmed
+// enrollments are left alone and apierrors.ErrTOTPAlreadyEnabled is returned.
+func (s *UserRepository) SaveTOTPSecret(ctx context.Context, userID int64, secret string) error {
+	const op = "sso.Auth.Repository.SaveTOTPSecret"
+	s.logger.Debugw("Saving TOTP secret", "user_id", userID, "op", op)
+
+	query := s.builder.Insert("user_totp").
+		Columns("user_id", "secret").
+		Values(userID, secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW() WHERE user_totp.confirmed_at IS NULL")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := s.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to save TOTP secret", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrTOTPAlreadyEnabled
+	}
+	return nil
+}
+
+func (s *UserRepository) GetTOTP(ctx context.Context, userID int64) (*models.TOTP, error) {
+	const op = "sso.Auth.Repository.GetTOTP"
+
+	query := s.builder.Select("user_id", "secret", "confirmed_at", "last_used_step").
+		From("user_totp").
+		Where(sq.Eq{"user_id": userID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var (
+		totp        models.TOTP
+		confirmedAt sql.NullTime
+	)
+	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(&totp.UserID, &totp.Secret, &confirmedAt, &totp.LastUsedStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrTOTPNotEnrolled
+	} else if err != nil {
+		s.logger.Warnw("failed to get TOTP", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	if confirmedAt.Valid {
+		totp.ConfirmedAt = &confirmedAt.Time
+	}
+	return &totp, nil
+}
+
+// ConfirmTOTP enables 2FA and replaces the recovery codes of the user with the given ones.
+func (s *UserRepository) ConfirmTOTP(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error {
+	const op = "sso.Auth.Repository.ConfirmTOTP"
+	s.logger.Debugw("Confirming TOTP", "user_id", userID, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := s.builder.Update("user_totp").
+		Set("confirmed_at", sq.Expr("NOW()")).
+		Set("last_used_step", step).
+		Where(sq.Eq{"user_id": userID, "confirmed_at": nil})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	result, err := tx.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to confirm TOTP", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return apierrors.ErrTOTPAlreadyEnabled
+	}
+
+	strSql, args, err = s.builder.Delete("recovery_codes").Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to delete recovery codes", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if len(recoveryCodeHashes) > 0 {
+		codesQuery := s.builder.Insert("recovery_codes").Columns("user_id", "code_hash")
+		for _, hash := range recoveryCodeHashes {
+			codesQuery = codesQuery.Values(userID, hash)
+		}
+
+		strSql, args, err = codesQuery.ToSql()
+		if err != nil {
+			s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+		if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+			s.logger.Warnw("failed to insert recovery codes", "error", err, "op", op)
+			return apierrors.ErrUnknown
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// UseTOTPStep records the step of an accepted code. It fails with apierrors.ErrInvalidSecondFactor if
+// the same or a later step was used in the meantime.
+func (s *UserRepository) UseTOTPStep(ctx context.Context, userID int64, step int64) error {
+	const op = "sso.Auth.Repository.UseTOTPStep"
+
+	query := s.builder.Update("user_totp").
+		Set("last_used_step", step).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Lt{"last_used_step": step})
+
+	return s.execOnce(ctx, op, query, apierrors.ErrInvalidSecondFactor)
+}
+
+func (s *UserRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	const op = "sso.Auth.Repository.UseRecoveryCode"
+
+	query := s.builder.Update("recovery_codes").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "code_hash": codeHash, "used_at": nil})
+
+	return s.execOnce(ctx, op, query, apierrors.ErrInvalidSecondFactor)
+}
+
+func (s *UserRepository) CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	const op = "sso.Auth.Repository.CreateLoginChallenge"
+	s.logger.Debugw("Creating login challenge", "user_id", challenge.UserID, "op", op)
+
+	query := s.builder.Insert("login_challenges").
+		Columns("user_id", "token_hash", "client_ip", "expires_at").
+		Values(challenge.UserID, challenge.TokenHash, challenge.ClientIP, challenge.ExpiresAt)
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to insert login challenge", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+func (s *UserRepository) GetLoginChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error) {
+	const op = "sso.Auth.Repository.GetLoginChallenge"
+
+	query := s.builder.Select("id", "user_id", "token_hash", "client_ip", "attempts", "expires_at", "used_at").
+		From("login_challenges").
+		Where(sq.Eq{"token_hash": tokenHash})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	var (
+		challenge models.LoginChallenge
+		usedAt    sql.NullTime
+	)
+	err = s.db.QueryRowContext(ctx, strSql, args...).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.ClientIP,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&usedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.ErrInvalidLoginChallenge
+	} else if err != nil {
+		s.logger.Warnw("failed to get login challenge", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+	return &challenge, nil
+}
+
+func (s *UserRepository) FailLoginChallenge(ctx context.Context, id int64) error {
+	const op = "sso.Auth.Repository.FailLoginChallenge"
+
+	query := s.builder.Update("login_challenges").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Where(sq.Eq{"id": id})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := s.db.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to count login challenge attempt", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// UseLoginChallenge marks the challenge used, so it can't be exchanged for tokens twice.
+func (s *UserRepository) UseLoginChallenge(ctx context.Context, id int64) error {
+	const op = "sso.Auth.Repository.UseLoginChallenge"
+
+	query := s.builder.Update("login_challenges").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "used_at": nil})
+
+	return s.execOnce(ctx, op, query, apierrors.ErrInvalidLoginChallenge)
+}
+
+// execOnce runs an update that has to change exactly one row, returning notMatched if none did.
+func (s *UserRepository) execOnce(ctx context.Context, op string, query sq.UpdateBuilder, notMatched error) error {
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	result, err := s.db.ExecContext(ctx, strSql, args...)
+	if err != nil {
+		s.logger.Warnw("failed to execute update", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apierrors.ErrUnknown
+	}
+	if rowsAffected == 0 {
+		return notMatched
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+// CreateUserToken stores a new token, invalidating the tokens the user still had for the same purpose.
+func (s *UserRepository) CreateUserToken(ctx context.Context, token *models.UserToken) error {
+	const op = "sso.Auth.Repository.CreateUserToken"
+	s.logger.Debugw("Creating user token", "user_id", token.UserID, "purpose", token.Purpose, "op", op)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	if err := s.invalidateUserTokens(ctx, tx, token.UserID, token.Purpose); err != nil {
+		return err
+	}
+
+	query := s.builder.Insert("user_tokens").
+		Columns("user_id", "purpose", "token_hash", "expires_at").
+		Values(token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt)
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to insert user token", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
+
+// VerifyEmail redeems an email verification token and marks the address of its user verified.
+func (s *UserRepository) VerifyEmail(ctx context.Context, tokenHash string) (int64, error) {
+	const op = "sso.Auth.Repository.VerifyEmail"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	userID, err := s.useUserToken(ctx, tx, tokenHash, models.PurposeEmailVerification)
+	if err != nil {
+		return 0, err
+	}
+
+	query := s.builder.Update("users").
+		Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, NOW())")).
+		Where(sq.Eq{"id": userID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to mark email verified", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return userID, nil
+}
+
+// ResetPassword redeems a password reset token, stores the new hash and revokes every session of
+// the user, so whoever knew the old password is logged out too. The token was mailed to the user,
+// so redeeming it verifies the address as well.
+func (s *UserRepository) ResetPassword(ctx context.Context, tokenHash string, passHash []byte) (int64, error) {
+	const op = "sso.Auth.Repository.ResetPassword"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Debugw("Failed to begin transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	defer tx.Rollback()
+
+	userID, err := s.useUserToken(ctx, tx, tokenHash, models.PurposePasswordReset)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.invalidateUserTokens(ctx, tx, userID, models.PurposePasswordReset); err != nil {
+		return 0, err
+	}
+
+	query := s.builder.Update("users").
+		Set("pass_hash", passHash).
+		Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, NOW())")).
+		Where(sq.Eq{"id": userID})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to update password", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	if err := s.revokeSessions(ctx, tx, sq.Eq{"user_id": userID}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Debugw("Failed to commit transaction", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return userID, nil
+}
+
+// useUserToken marks an unused, unexpired token used and returns its user. Anything else is
+// reported as apierrors.ErrInvalidUserToken.
+func (s *UserRepository) useUserToken(ctx context.Context, tx *sql.Tx, tokenHash string, purpose string) (int64, error) {
+	const op = "sso.Auth.Repository.useUserToken"
+
+	query := s.builder.Update("user_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"token_hash": tokenHash, "purpose": purpose, "used_at": nil}).
+		Where("expires_at > NOW()").
+		Suffix("RETURNING user_id")
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+
+	var userID int64
+	err = tx.QueryRowContext(ctx, strSql, args...).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, apierrors.ErrInvalidUserToken
+	} else if err != nil {
+		s.logger.Warnw("failed to use user token", "error", err, "op", op)
+		return 0, apierrors.ErrUnknown
+	}
+	return userID, nil
+}
+
+func (s *UserRepository) invalidateUserTokens(ctx context.Context, tx *sql.Tx, userID int64, purpose string) error {
+	const op = "sso.Auth.Repository.invalidateUserTokens"
+
+	query := s.builder.Update("user_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "purpose": purpose, "used_at": nil})
+
+	strSql, args, err := query.ToSql()
+	if err != nil {
+		s.logger.Debugw("Failed to build SQL query", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if _, err := tx.ExecContext(ctx, strSql, args...); err != nil {
+		s.logger.Warnw("failed to invalidate user tokens", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	return nil
+}
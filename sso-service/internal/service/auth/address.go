@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/address"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+)
+
+// maxAddresses keeps address books at a size people actually pick from.
+const maxAddresses = 20
+
+func (s *AuthService) ListAddresses(ctx context.Context, userID int64) ([]*models.Address, error) {
+	const op = "sso.Auth.Service.ListAddresses"
+	s.logger.Debugw("Listing addresses", "user_id", userID, "op", op)
+
+	return s.userRepo.ListAddresses(ctx, userID)
+}
+
+// CreateAddress validates and stores a new address. The first address of a user becomes the
+// default for both shipping and billing.
+func (s *AuthService) CreateAddress(ctx context.Context, userID int64, newAddress *models.Address) (*models.Address, error) {
+	const op = "sso.Auth.Service.CreateAddress"
+	s.logger.Debugw("Creating address", "user_id", userID, "op", op)
+
+	address.Normalize(newAddress)
+	if err := address.Validate(newAddress); err != nil {
+		s.logger.Debugw("Invalid address", "error", err, "op", op)
+		return nil, err
+	}
+
+	existing, err := s.userRepo.ListAddresses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAddresses {
+		return nil, apierrors.ErrTooManyAddresses
+	}
+	if len(existing) == 0 {
+		newAddress.DefaultShipping = true
+		newAddress.DefaultBilling = true
+	}
+
+	newAddress.ID = 0
+	newAddress.UserID = userID
+	id, err := s.userRepo.CreateAddress(ctx, newAddress)
+	if err != nil {
+		return nil, err
+	}
+	newAddress.ID = id
+
+	s.logger.Debugw("Address created", "user_id", userID, "address_id", id, "op", op)
+	return newAddress, nil
+}
+
+func (s *AuthService) UpdateAddress(ctx context.Context, userID int64, updated *models.Address) (*models.Address, error) {
+	const op = "sso.Auth.Service.UpdateAddress"
+	s.logger.Debugw("Updating address", "user_id", userID, "address_id", updated.ID, "op", op)
+
+	address.Normalize(updated)
+	if err := address.Validate(updated); err != nil {
+		s.logger.Debugw("Invalid address", "error", err, "op", op)
+		return nil, err
+	}
+
+	updated.UserID = userID
+	if err := s.userRepo.UpdateAddress(ctx, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *AuthService) DeleteAddress(ctx context.Context, userID int64, addressID int64) error {
+	const op = "sso.Auth.Service.DeleteAddress"
+	s.logger.Debugw("Deleting address", "user_id", userID, "address_id", addressID, "op", op)
+
+	return s.userRepo.DeleteAddress(ctx, userID, addressID)
+}
+
+// GetCheckoutAddresses resolves the addresses an order ships to and is billed to. A zero ID means
+// the user's default. Without a default billing address the order is billed to the shipping address.
+func (s *AuthService) GetCheckoutAddresses(ctx context.Context, userID int64, shippingID int64, billingID int64) (shipping *models.Address, billing *models.Address, err error) {
+	const op = "sso.Auth.Service.GetCheckoutAddresses"
+	s.logger.Debugw("Resolving checkout addresses", "user_id", userID, "shipping_id", shippingID, "billing_id", billingID, "op", op)
+
+	if shippingID != 0 {
+		shipping, err = s.userRepo.GetAddress(ctx, userID, shippingID)
+	} else {
+		shipping, err = s.userRepo.GetDefaultAddress(ctx, userID, false)
+		if errors.Is(err, apierrors.ErrAddressNotFound) {
+			return nil, nil, apierrors.ErrNoShippingAddress
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if billingID != 0 {
+		billing, err = s.userRepo.GetAddress(ctx, userID, billingID)
+	} else {
+		billing, err = s.userRepo.GetDefaultAddress(ctx, userID, true)
+		if errors.Is(err, apierrors.ErrAddressNotFound) {
+			billing, err = shipping, nil
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shipping, billing, nil
+}
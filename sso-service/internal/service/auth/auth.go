@@ -2,34 +2,114 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/auth"
 	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserRepo interface {
-	CreateUser(ctx context.Context, firstName string, lastName string, email string, password_hash []byte) (int64, error)
+	CreateUser(ctx context.Context, firstName string, lastName string, email string, password_hash []byte, roles []string) (int64, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id int64) (*models.User, error)
+	CreateUserToken(ctx context.Context, token *models.UserToken) error
+	VerifyEmail(ctx context.Context, tokenHash string) (int64, error)
+	ResetPassword(ctx context.Context, tokenHash string, passHash []byte) (int64, error)
+	RecordLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error
+	GetAccountLoginFailures(ctx context.Context, email string, since time.Time) (*models.LoginFailures, error)
+	GetClientLoginFailures(ctx context.Context, clientIP string, since time.Time) (*models.LoginFailures, error)
+	SaveTOTPSecret(ctx context.Context, userID int64, secret string) error
+	GetTOTP(ctx context.Context, userID int64) (*models.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error
+	UseTOTPStep(ctx context.Context, userID int64, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error
+	GetLoginChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error)
+	FailLoginChallenge(ctx context.Context, id int64) error
+	UseLoginChallenge(ctx context.Context, id int64) error
+	UpdateProfile(ctx context.Context, userID int64, firstName string, lastName string) error
+	ChangePassword(ctx context.Context, userID int64, passHash []byte, currentJTI string) error
+	DeleteUser(ctx context.Context, userID int64, message *models.OutboxMessage) error
+	CreateAddress(ctx context.Context, address *models.Address) (int64, error)
+	UpdateAddress(ctx context.Context, address *models.Address) error
+	DeleteAddress(ctx context.Context, userID int64, addressID int64) error
+	GetAddress(ctx context.Context, userID int64, addressID int64) (*models.Address, error)
+	GetDefaultAddress(ctx context.Context, userID int64, billing bool) (*models.Address, error)
+	ListAddresses(ctx context.Context, userID int64) ([]*models.Address, error)
+	SaveServiceAccount(ctx context.Context, clientID string, secretHash []byte) error
+	GetServiceAccount(ctx context.Context, clientID string) (*models.ServiceAccount, error)
+	SaveOAuthClient(ctx context.Context, client *models.OAuthClient) error
+	GetOAuthClient(ctx context.Context, clientID string) (*models.OAuthClient, error)
+	CreateAuthorizationCode(ctx context.Context, code *models.AuthorizationCode) error
+	UseAuthorizationCode(ctx context.Context, codeHash string) (*models.AuthorizationCode, error)
+}
+
+type SessionRepo interface {
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSessionByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error)
+	RotateSession(ctx context.Context, oldSessionID int64, next *models.Session) error
+	RevokeSessionFamily(ctx context.Context, familyID string) error
+	ListRevokedTokens(ctx context.Context, afterID int64, limit uint64) ([]*models.RevokedToken, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+const maxRevokedTokensPage = 1000
+
+type Signer interface {
+	Sign(claims jwt.MapClaims) (string, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
+
+type EmailSettings struct {
+	// RequireVerified makes Login refuse users who haven't confirmed their address yet
+	RequireVerified       bool
+	VerificationTokenTTL  time.Duration
+	PasswordResetTokenTTL time.Duration
 }
 
 type AuthService struct {
-	logger    *zap.SugaredLogger
-	userRepo  UserRepo
-	tokenTTL  time.Duration
-	jwtSecret string
+	logger          *zap.SugaredLogger
+	userRepo        UserRepo
+	sessionRepo     SessionRepo
+	tokenTTL        time.Duration
+	refreshTokenTTL time.Duration
+	signer          Signer
+	mailer          Mailer
+	email           EmailSettings
+	throttle        ThrottleSettings
+	twoFactor       TwoFactorSettings
+	userEventsTopic string
+	serviceTokenTTL time.Duration
+	oidc            OIDCSettings
 }
 
-func New(logger *zap.SugaredLogger, userRepo UserRepo, tokenTTL time.Duration, jwtSecret string) *AuthService {
+func New(logger *zap.SugaredLogger, userRepo UserRepo, sessionRepo SessionRepo, tokenTTL time.Duration, refreshTokenTTL time.Duration, signer Signer, mailer Mailer, email EmailSettings, throttle ThrottleSettings, twoFactor TwoFactorSettings, userEventsTopic string, serviceTokenTTL time.Duration, oidc OIDCSettings) *AuthService {
 	return &AuthService{
-		logger:    logger,
-		userRepo:  userRepo,
-		tokenTTL:  tokenTTL,
-		jwtSecret: jwtSecret,
+		logger:          logger,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		tokenTTL:        tokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+		signer:          signer,
+		mailer:          mailer,
+		email:           email,
+		throttle:        throttle,
+		twoFactor:       twoFactor,
+		userEventsTopic: userEventsTopic,
+		serviceTokenTTL: serviceTokenTTL,
+		oidc:            oidc,
 	}
 }
 
@@ -49,41 +129,277 @@ func (s *AuthService) Register(ctx context.Context, firstName string, lastName s
 		s.logger.Debugw("Failed to make password hash", "error", err, "op", op)
 		return 0, apierrors.ErrUnknown
 	}
-	id, err := s.userRepo.CreateUser(ctx, firstName, lastName, email, hash)
+	// self-registered accounts are always shoppers, staff roles are granted separately
+	id, err := s.userRepo.CreateUser(ctx, firstName, lastName, email, hash, []string{auth.RoleCustomer})
 	if err != nil {
 		s.logger.Debugw("Failed to add user to database", "op", op)
 		return 0, err
 	}
 
+	// the account exists either way, a password reset verifies the address too if this mail gets lost
+	if err := s.sendVerification(ctx, id, email); err != nil {
+		s.logger.Errorw("failed to send verification mail", "error", err, "user_id", id, "op", op)
+	}
+
 	s.logger.Debugw("Successfuly registered user", "op", op)
 	return id, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, email string, password string) (string, error) {
+// Login checks the credentials unless the account or the client address is throttled, in which
+// case it returns a ThrottledError without looking at the password. Users with 2FA get a challenge
+// for VerifySecondFactor instead of tokens.
+func (s *AuthService) Login(ctx context.Context, email string, password string, clientIP string) (*models.LoginResult, error) {
 	const op = "sso.Auth.Service.Login"
+	s.logger.Debugw("Logging in user", "email", email, "client_ip", clientIP, "op", op)
+
+	user, challenge, err := s.checkPassword(ctx, email, password, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != "" {
+		return &models.LoginResult{Challenge: challenge}, nil
+	}
+
+	tokens, err := s.startSession(ctx, user, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugw("Successfuly logged in user", "email", email, "op", op)
+	return &models.LoginResult{Tokens: tokens}, nil
+}
+
+// checkPassword is the first step of every login. It returns the user once the password is
+// confirmed, or only a login challenge if the user has 2FA enabled.
+func (s *AuthService) checkPassword(ctx context.Context, email string, password string, clientIP string) (*models.User, string, error) {
+	const op = "sso.Auth.Service.checkPassword"
+
+	if err := s.checkThrottle(ctx, email, clientIP); err != nil {
+		s.logger.Warnw("Login throttled", "email", email, "client_ip", clientIP, "error", err, "op", op)
+		return nil, "", err
+	}
 
-	s.logger.Debugw("Logging in user", "email", email, "op", op)
+	attempt := &models.LoginAttempt{Email: email, ClientIP: clientIP}
 	existingUser, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, apierrors.ErrNoUser) {
 			s.logger.Debugw("User not found", "email", email, "op", op)
-			return "", apierrors.ErrInvalidCredentials
+			s.recordAttempt(ctx, attempt)
+			return nil, "", apierrors.ErrInvalidCredentials
 		}
-		s.logger.Warnf("failed to log in user", "error", err, "op", op)
-		return "", err
+		s.logger.Warnw("failed to log in user", "error", err, "op", op)
+		return nil, "", err
 	}
+	attempt.UserID = existingUser.ID
 
 	err = bcrypt.CompareHashAndPassword(existingUser.PassHash, []byte(password))
 	if err != nil {
-		return "", apierrors.ErrInvalidCredentials
+		s.recordAttempt(ctx, attempt)
+		return nil, "", apierrors.ErrInvalidCredentials
 	}
 
-	claims := jwt.MapClaims{
-		"user_id": existingUser.ID,
-		"exp":     time.Now().Add(s.tokenTTL).Unix(),
+	if s.email.RequireVerified && existingUser.EmailVerifiedAt == nil {
+		s.logger.Debugw("Email not verified", "email", email, "op", op)
+		return nil, "", apierrors.ErrEmailNotVerified
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	s.logger.Debugw("Successfuly logged in user", "email", email, "op", op)
-	return token.SignedString([]byte(s.jwtSecret))
+	secondFactor, err := s.requiresSecondFactor(ctx, existingUser.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if secondFactor {
+		// the attempt is recorded once the second factor is checked, a password alone must not
+		// reset the failure count wrong codes build up
+		challenge, err := s.newLoginChallenge(ctx, existingUser.ID, clientIP)
+		if err != nil {
+			return nil, "", err
+		}
+		s.logger.Debugw("Second factor required", "email", email, "op", op)
+		return nil, challenge, nil
+	}
+
+	attempt.Success = true
+	s.recordAttempt(ctx, attempt)
+	return existingUser, "", nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. Every refresh token works once:
+// presenting one that was already exchanged means it leaked, so the whole session is revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	return s.refreshSession(ctx, refreshToken, "")
+}
+
+// refreshSession rotates the session of refreshToken. The token only works for the OAuth client the
+// session was issued to, clientID is empty for our own apps.
+func (s *AuthService) refreshSession(ctx context.Context, refreshToken string, clientID string) (*models.TokenPair, error) {
+	const op = "sso.Auth.Service.refreshSession"
+	s.logger.Debugw("Refreshing token", "client_id", clientID, "op", op)
+
+	session, err := s.sessionRepo.GetSessionByTokenHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, apierrors.ErrSessionNotFound) {
+		return nil, apierrors.ErrInvalidRefreshToken
+	} else if err != nil {
+		return nil, err
+	}
+	if session.ClientID != clientID {
+		s.logger.Warnw("Refresh token presented by another client", "session_id", session.ID, "client_id", clientID, "op", op)
+		if clientID != "" {
+			return nil, apierrors.ErrInvalidGrant
+		}
+		return nil, apierrors.ErrInvalidRefreshToken
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		s.logger.Debugw("Refresh token revoked or expired", "session_id", session.ID, "op", op)
+		return nil, apierrors.ErrInvalidRefreshToken
+	}
+	if session.RotatedAt != nil {
+		return nil, s.revokeReused(ctx, session)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, apierrors.ErrInvalidRefreshToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	tokens, next, err := s.newSession(user, session.FamilyID, session.SecondFactor)
+	if err != nil {
+		s.logger.Warnw("failed to issue tokens", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	next.ClientID = session.ClientID
+	err = s.sessionRepo.RotateSession(ctx, session.ID, next)
+	if errors.Is(err, apierrors.ErrRefreshTokenReused) {
+		// lost a race against another request with the same token
+		return nil, s.revokeReused(ctx, session)
+	} else if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugw("Successfuly refreshed token", "user_id", user.ID, "op", op)
+	return tokens, nil
+}
+
+// Logout revokes every refresh token of the session the given token belongs to.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	const op = "sso.Auth.Service.Logout"
+	s.logger.Debugw("Logging out", "op", op)
+
+	session, err := s.sessionRepo.GetSessionByTokenHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, apierrors.ErrSessionNotFound) {
+		return apierrors.ErrInvalidRefreshToken
+	} else if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeSessionFamily(ctx, session.FamilyID); err != nil {
+		return err
+	}
+
+	s.logger.Debugw("Successfuly logged out", "user_id", session.UserID, "op", op)
+	return nil
+}
+
+// ListRevokedTokens is the feed other services poll to reject revoked access tokens before they expire.
+func (s *AuthService) ListRevokedTokens(ctx context.Context, afterID int64, limit uint64) ([]*models.RevokedToken, error) {
+	const op = "sso.Auth.Service.ListRevokedTokens"
+	s.logger.Debugw("Listing revoked tokens", "after_id", afterID, "op", op)
+
+	if limit == 0 || limit > maxRevokedTokensPage {
+		limit = maxRevokedTokensPage
+	}
+	return s.sessionRepo.ListRevokedTokens(ctx, afterID, limit)
+}
+
+func (s *AuthService) revokeReused(ctx context.Context, session *models.Session) error {
+	const op = "sso.Auth.Service.revokeReused"
+	s.logger.Warnw("Refresh token reuse detected, revoking session", "user_id", session.UserID, "family_id", session.FamilyID, "op", op)
+
+	if err := s.sessionRepo.RevokeSessionFamily(ctx, session.FamilyID); err != nil {
+		return err
+	}
+	return apierrors.ErrRefreshTokenReused
+}
+
+// IsTokenRevoked lets handlers of sso-service itself reject logged out access tokens.
+func (s *AuthService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.sessionRepo.IsTokenRevoked(ctx, jti)
+}
+
+// startSession opens a new session family for a completed login. clientID is the OAuth client the
+// login was made through, empty for our own apps.
+func (s *AuthService) startSession(ctx context.Context, user *models.User, clientID string, secondFactor bool) (*models.TokenPair, error) {
+	const op = "sso.Auth.Service.startSession"
+
+	familyID, err := randomToken()
+	if err != nil {
+		s.logger.Warnw("failed to generate session family", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	tokens, session, err := s.newSession(user, familyID, secondFactor)
+	if err != nil {
+		s.logger.Warnw("failed to issue tokens", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+	session.ClientID = clientID
+	if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// newSession issues an access and a refresh token pair. The session stores the refresh token hash
+// and the jti of the access token, so both can be revoked together.
+func (s *AuthService) newSession(user *models.User, familyID string, secondFactor bool) (*models.TokenPair, *models.Session, error) {
+	jti, err := randomToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	accessExpiresAt := time.Now().Add(s.tokenTTL)
+
+	methods := []string{auth.MethodPassword}
+	if secondFactor {
+		methods = append(methods, auth.MethodMFA)
+	}
+
+	accessToken, err := s.signer.Sign(jwt.MapClaims{
+		"jti":     jti,
+		"user_id": user.ID,
+		"roles":   user.Roles,
+		"amr":     methods,
+		"exp":     accessExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &models.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, &models.Session{
+		FamilyID:        familyID,
+		UserID:          user.ID,
+		TokenHash:       hashToken(refreshToken),
+		ExpiresAt:       time.Now().Add(s.refreshTokenTTL),
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		SecondFactor:    secondFactor,
+	}, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
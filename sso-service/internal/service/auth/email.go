@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	const op = "sso.Auth.Service.VerifyEmail"
+	s.logger.Debugw("Verifying email", "op", op)
+
+	userID, err := s.userRepo.VerifyEmail(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debugw("Successfuly verified email", "user_id", userID, "op", op)
+	return nil
+}
+
+// RequestPasswordReset mails a reset token if the email belongs to a user. Unknown emails are not
+// reported, so the endpoint can't be used to find out who has an account. For the same reason the
+// token is issued and mailed in the background, a failure to do so, or the time it takes, would
+// only show for registered emails.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	const op = "sso.Auth.Service.RequestPasswordReset"
+	s.logger.Debugw("Requesting password reset", "email", email, "op", op)
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, apierrors.ErrNoUser) {
+		s.logger.Debugw("User not found", "email", email, "op", op)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	go s.sendPasswordReset(context.WithoutCancel(ctx), user)
+	return nil
+}
+
+func (s *AuthService) sendPasswordReset(ctx context.Context, user *models.User) {
+	const op = "sso.Auth.Service.sendPasswordReset"
+
+	token, err := s.issueUserToken(ctx, user.ID, models.PurposePasswordReset, s.email.PasswordResetTokenTTL)
+	if err != nil {
+		s.logger.Errorw("failed to issue password reset token", "error", err, "user_id", user.ID, "op", op)
+		return
+	}
+
+	body := fmt.Sprintf("Someone asked to reset the password of your account. If it was you, use this token within %s:\n\n%s\n\nOtherwise you can ignore this mail.",
+		s.email.PasswordResetTokenTTL, token)
+	if err := s.mailer.Send(ctx, user.Email, "Reset your password", body); err != nil {
+		s.logger.Errorw("failed to send password reset mail", "error", err, "user_id", user.ID, "op", op)
+		return
+	}
+
+	s.logger.Debugw("Password reset mail sent", "user_id", user.ID, "op", op)
+}
+
+// ResetPassword sets a new password and logs the user out everywhere.
+func (s *AuthService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	const op = "sso.Auth.Service.ResetPassword"
+	s.logger.Debugw("Resetting password", "op", op)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		s.logger.Debugw("Failed to make password hash", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	userID, err := s.userRepo.ResetPassword(ctx, hashToken(token), hash)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debugw("Successfuly reset password", "user_id", userID, "op", op)
+	return nil
+}
+
+func (s *AuthService) sendVerification(ctx context.Context, userID int64, email string) error {
+	token, err := s.issueUserToken(ctx, userID, models.PurposeEmailVerification, s.email.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Confirm your email address with this token within %s:\n\n%s", s.email.VerificationTokenTTL, token)
+	return s.mailer.Send(ctx, email, "Confirm your email address", body)
+}
+
+func (s *AuthService) issueUserToken(ctx context.Context, userID int64, purpose string, ttl time.Duration) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", apierrors.ErrUnknown
+	}
+
+	err = s.userRepo.CreateUserToken(ctx, &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
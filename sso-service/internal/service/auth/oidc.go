@@ -0,0 +1,319 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/pkg/auth"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Scopes of the OpenID Connect endpoints. profile and email add the matching claims to the ID token.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// CodeChallengeS256 is the only PKCE method accepted, plain would defeat the point.
+const CodeChallengeS256 = "S256"
+
+type OIDCSettings struct {
+	// Issuer is the URL the provider is reachable at, it goes into the iss claim of ID tokens
+	Issuer  string
+	CodeTTL time.Duration
+}
+
+// OAuthError is an error the OIDC endpoints report to the client with one of the error codes of
+// RFC 6749, like invalid_request or invalid_scope.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return fmt.Sprintf("%s: %s", apierrors.ErrInvalidAuthorizationRequest, e.Description)
+}
+
+func (e *OAuthError) Is(target error) bool {
+	return target == apierrors.ErrInvalidAuthorizationRequest
+}
+
+// RegisterOAuthClient stores a client for the OIDC endpoints. Clients without a secret are public
+// and have to prove themselves with PKCE alone.
+func (s *AuthService) RegisterOAuthClient(ctx context.Context, clientID string, name string, secret string, redirectURIs []string) error {
+	const op = "sso.Auth.Service.RegisterOAuthClient"
+	s.logger.Debugw("Registering OAuth client", "client_id", clientID, "op", op)
+
+	if clientID == "" || len(redirectURIs) == 0 {
+		return apierrors.ErrInvalidClient
+	}
+
+	client := &models.OAuthClient{ClientID: clientID, Name: name, RedirectURIs: redirectURIs}
+	if secret != "" {
+		if len(secret) < minClientSecretLength {
+			return apierrors.ErrInvalidClient
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			s.logger.Debugw("Failed to make secret hash", "error", err, "op", op)
+			return apierrors.ErrInvalidClient
+		}
+		client.SecretHash = hash
+	}
+	return s.userRepo.SaveOAuthClient(ctx, client)
+}
+
+// ValidateAuthorizationRequest checks a request to the authorization endpoint. Errors matching
+// apierrors.ErrInvalidClient or apierrors.ErrInvalidRedirectURI must not be sent to the redirect
+// URI, an *OAuthError can be.
+func (s *AuthService) ValidateAuthorizationRequest(ctx context.Context, req *models.AuthorizationRequest) (*models.OAuthClient, error) {
+	const op = "sso.Auth.Service.ValidateAuthorizationRequest"
+
+	client, err := s.userRepo.GetOAuthClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.DisabledAt != nil {
+		s.logger.Debugw("Disabled OAuth client", "client_id", req.ClientID, "op", op)
+		return nil, apierrors.ErrInvalidClient
+	}
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		s.logger.Debugw("Redirect URI not registered", "client_id", req.ClientID, "redirect_uri", req.RedirectURI, "op", op)
+		return nil, apierrors.ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return nil, &OAuthError{Code: "unsupported_response_type", Description: "only the code response type is supported"}
+	}
+	if !slices.Contains(strings.Fields(req.Scope), ScopeOpenID) {
+		return nil, &OAuthError{Code: "invalid_scope", Description: "the openid scope is required"}
+	}
+	if req.CodeChallengeMethod != CodeChallengeS256 || len(req.CodeChallenge) != 43 {
+		return nil, &OAuthError{Code: "invalid_request", Description: "a PKCE code challenge with the S256 method is required"}
+	}
+	return client, nil
+}
+
+// AuthorizeWithPassword logs the user in on the authorization page and issues an authorization
+// code for the client. Users with 2FA get a challenge for AuthorizeWithSecondFactor instead.
+func (s *AuthService) AuthorizeWithPassword(ctx context.Context, req *models.AuthorizationRequest, email string, password string, clientIP string) (*models.AuthorizationResult, error) {
+	const op = "sso.Auth.Service.AuthorizeWithPassword"
+	s.logger.Debugw("Authorizing OAuth client", "client_id", req.ClientID, "email", email, "client_ip", clientIP, "op", op)
+
+	if _, err := s.ValidateAuthorizationRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	user, challenge, err := s.checkPassword(ctx, email, password, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != "" {
+		return &models.AuthorizationResult{Challenge: challenge}, nil
+	}
+
+	code, err := s.issueAuthorizationCode(ctx, req, user.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthorizationResult{Code: code}, nil
+}
+
+func (s *AuthService) AuthorizeWithSecondFactor(ctx context.Context, req *models.AuthorizationRequest, challenge string, code string, clientIP string) (*models.AuthorizationResult, error) {
+	const op = "sso.Auth.Service.AuthorizeWithSecondFactor"
+	s.logger.Debugw("Authorizing OAuth client with second factor", "client_id", req.ClientID, "client_ip", clientIP, "op", op)
+
+	if _, err := s.ValidateAuthorizationRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.checkLoginChallenge(ctx, challenge, code, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	authCode, err := s.issueAuthorizationCode(ctx, req, user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthorizationResult{Code: authCode}, nil
+}
+
+// ExchangeAuthorizationCode is the authorization code grant of the token endpoint. The code must
+// have been issued to the same client and redirect URI, and the verifier must match its PKCE
+// challenge. A code works once.
+func (s *AuthService) ExchangeAuthorizationCode(ctx context.Context, clientID string, clientSecret string, code string, redirectURI string, codeVerifier string) (*models.OIDCTokens, error) {
+	const op = "sso.Auth.Service.ExchangeAuthorizationCode"
+	s.logger.Debugw("Exchanging authorization code", "client_id", clientID, "op", op)
+
+	if _, err := s.authenticateOAuthClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+
+	authCode, err := s.userRepo.UseAuthorizationCode(ctx, hashToken(code))
+	if err != nil {
+		return nil, err
+	}
+	if authCode.ClientID != clientID || authCode.RedirectURI != redirectURI {
+		s.logger.Warnw("Authorization code presented by another client", "client_id", clientID, "op", op)
+		return nil, apierrors.ErrInvalidGrant
+	}
+	if subtle.ConstantTimeCompare([]byte(codeChallenge(codeVerifier)), []byte(authCode.CodeChallenge)) != 1 {
+		s.logger.Debugw("PKCE verifier mismatch", "client_id", clientID, "op", op)
+		return nil, apierrors.ErrInvalidGrant
+	}
+
+	user, err := s.userRepo.GetByID(ctx, authCode.UserID)
+	if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, apierrors.ErrInvalidGrant
+	} else if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user, clientID, authCode.SecondFactor)
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := s.signIDToken(user, authCode)
+	if err != nil {
+		s.logger.Warnw("failed to sign ID token", "error", err, "op", op)
+		return nil, apierrors.ErrUnknown
+	}
+
+	s.logger.Debugw("Authorization code exchanged", "client_id", clientID, "user_id", user.ID, "op", op)
+	return &models.OIDCTokens{Tokens: tokens, IDToken: idToken, ExpiresIn: s.tokenTTL, Scope: authCode.Scope}, nil
+}
+
+// RefreshOIDCToken is the refresh token grant of the token endpoint, it works like RefreshToken
+// once the client is authenticated. Only the client the refresh token was issued to can use it.
+func (s *AuthService) RefreshOIDCToken(ctx context.Context, clientID string, clientSecret string, refreshToken string) (*models.OIDCTokens, error) {
+	if _, err := s.authenticateOAuthClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.refreshSession(ctx, refreshToken, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OIDCTokens{Tokens: tokens, ExpiresIn: s.tokenTTL}, nil
+}
+
+// authenticateOAuthClient checks the secret of confidential clients. Public clients must not send one.
+func (s *AuthService) authenticateOAuthClient(ctx context.Context, clientID string, clientSecret string) (*models.OAuthClient, error) {
+	const op = "sso.Auth.Service.authenticateOAuthClient"
+
+	client, err := s.userRepo.GetOAuthClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.DisabledAt != nil {
+		return nil, apierrors.ErrInvalidClient
+	}
+
+	if client.SecretHash == nil {
+		if clientSecret != "" {
+			return nil, apierrors.ErrInvalidClient
+		}
+		return client, nil
+	}
+	if err := bcrypt.CompareHashAndPassword(client.SecretHash, []byte(clientSecret)); err != nil {
+		s.logger.Warnw("Invalid OAuth client secret", "client_id", clientID, "op", op)
+		return nil, apierrors.ErrInvalidClient
+	}
+	return client, nil
+}
+
+func (s *AuthService) issueAuthorizationCode(ctx context.Context, req *models.AuthorizationRequest, userID int64, secondFactor bool) (string, error) {
+	code, err := randomToken()
+	if err != nil {
+		return "", apierrors.ErrUnknown
+	}
+
+	err = s.userRepo.CreateAuthorizationCode(ctx, &models.AuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      req.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         req.Scope,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		SecondFactor:  secondFactor,
+		ExpiresAt:     time.Now().Add(s.oidc.CodeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// signIDToken issues the ID token of the OIDC authorization code flow. It tells the client who
+// logged in, API calls still need the access token.
+func (s *AuthService) signIDToken(user *models.User, authCode *models.AuthorizationCode) (string, error) {
+	now := time.Now()
+
+	methods := []string{auth.MethodPassword}
+	if authCode.SecondFactor {
+		methods = append(methods, auth.MethodMFA)
+	}
+
+	claims := jwt.MapClaims{
+		"iss":       s.oidc.Issuer,
+		"sub":       strconv.FormatInt(user.ID, 10),
+		"aud":       authCode.ClientID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.tokenTTL).Unix(),
+		"auth_time": now.Unix(),
+		"amr":       methods,
+	}
+	if authCode.Nonce != "" {
+		claims["nonce"] = authCode.Nonce
+	}
+
+	scopes := strings.Fields(authCode.Scope)
+	if slices.Contains(scopes, ScopeProfile) {
+		for name, value := range ProfileClaims(user) {
+			claims[name] = value
+		}
+	}
+	if slices.Contains(scopes, ScopeEmail) {
+		for name, value := range EmailClaims(user) {
+			claims[name] = value
+		}
+	}
+	return s.signer.Sign(claims)
+}
+
+// ProfileClaims are the standard claims of the profile scope we have data for.
+func ProfileClaims(user *models.User) map[string]any {
+	return map[string]any{
+		"name":        strings.TrimSpace(user.FirstName + " " + user.LastName),
+		"given_name":  user.FirstName,
+		"family_name": user.LastName,
+	}
+}
+
+// EmailClaims are the standard claims of the email scope.
+func EmailClaims(user *models.User) map[string]any {
+	return map[string]any{
+		"email":          user.Email,
+		"email_verified": user.EmailVerifiedAt != nil,
+	}
+}
+
+// codeChallenge derives the S256 PKCE challenge from a code verifier, see RFC 7636.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/messaging"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s *AuthService) GetMe(ctx context.Context, userID int64) (*models.Profile, error) {
+	const op = "sso.Auth.Service.GetMe"
+	s.logger.Debugw("Getting profile", "user_id", userID, "op", op)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.requiresSecondFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Profile{User: user, TwoFactorEnabled: twoFactor}, nil
+}
+
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, firstName string, lastName string) (*models.Profile, error) {
+	const op = "sso.Auth.Service.UpdateProfile"
+	s.logger.Debugw("Updating profile", "user_id", userID, "op", op)
+
+	if err := s.userRepo.UpdateProfile(ctx, userID, firstName, lastName); err != nil {
+		return nil, err
+	}
+	return s.GetMe(ctx, userID)
+}
+
+// ChangePassword replaces the password once the current one is confirmed. Sessions other than the
+// one currentJTI was issued for are revoked, in case the old password leaked.
+func (s *AuthService) ChangePassword(ctx context.Context, userID int64, currentJTI string, oldPassword string, newPassword string, clientIP string) error {
+	const op = "sso.Auth.Service.ChangePassword"
+	s.logger.Debugw("Changing password", "user_id", userID, "op", op)
+
+	if err := s.reauthenticate(ctx, userID, oldPassword, clientIP); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		s.logger.Debugw("Failed to make password hash", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	if err := s.userRepo.ChangePassword(ctx, userID, hash, currentJTI); err != nil {
+		return err
+	}
+
+	s.logger.Debugw("Successfuly changed password", "user_id", userID, "op", op)
+	return nil
+}
+
+// DeleteAccount deletes the user once the password is confirmed and announces it on the user
+// events topic, so the other services can drop or anonymize their data about the user.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID int64, password string, clientIP string) error {
+	const op = "sso.Auth.Service.DeleteAccount"
+	s.logger.Debugw("Deleting account", "user_id", userID, "op", op)
+
+	if err := s.reauthenticate(ctx, userID, password, clientIP); err != nil {
+		return err
+	}
+
+	eventID, err := messaging.NewEventID()
+	if err != nil {
+		s.logger.Warnw("failed to generate event id", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+	payload, err := messaging.SerializeUserEvent(eventID, messaging.EventUserDeleted, userID, time.Now().UTC())
+	if err != nil {
+		s.logger.Warnw("failed to serialize user event", "error", err, "op", op)
+		return apierrors.ErrUnknown
+	}
+
+	message := &models.OutboxMessage{
+		Topic:   s.userEventsTopic,
+		Key:     strconv.FormatInt(userID, 10),
+		Payload: payload,
+	}
+	if err := s.userRepo.DeleteUser(ctx, userID, message); err != nil {
+		return err
+	}
+
+	s.logger.Debugw("Successfuly deleted account", "user_id", userID, "op", op)
+	return nil
+}
+
+// reauthenticate checks the password of a logged in user. Wrong passwords count as failed logins,
+// so a stolen access token can't be used to guess the password either.
+func (s *AuthService) reauthenticate(ctx context.Context, userID int64, password string, clientIP string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkThrottle(ctx, user.Email, clientIP); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+		s.recordAttempt(ctx, &models.LoginAttempt{Email: user.Email, UserID: user.ID, ClientIP: clientIP})
+		return apierrors.ErrInvalidCredentials
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// attemptsRepo keeps login attempts in memory. Account failures aren't implemented, service
+// accounts must never be throttled by their client ID.
+type attemptsRepo struct {
+	UserRepo
+	accounts map[string]*models.ServiceAccount
+	attempts []*models.LoginAttempt
+}
+
+func (r *attemptsRepo) GetServiceAccount(ctx context.Context, clientID string) (*models.ServiceAccount, error) {
+	account, ok := r.accounts[clientID]
+	if !ok {
+		return nil, apierrors.ErrInvalidClient
+	}
+	return account, nil
+}
+
+func (r *attemptsRepo) RecordLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *attemptsRepo) GetClientLoginFailures(ctx context.Context, clientIP string, since time.Time) (*models.LoginFailures, error) {
+	failures := &models.LoginFailures{}
+	for _, attempt := range r.attempts {
+		if attempt.ClientIP == clientIP && !attempt.Success {
+			failures.Count++
+			failures.LastAt = time.Now()
+		}
+	}
+	return failures, nil
+}
+
+type stubSigner struct{}
+
+func (stubSigner) Sign(claims jwt.MapClaims) (string, error) {
+	return "token-for-" + claims["client_id"].(string), nil
+}
+
+func TestClientCredentialsThrottlesClientAddress(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("cart-service-secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &attemptsRepo{accounts: map[string]*models.ServiceAccount{
+		"cart-service": {ClientID: "cart-service", SecretHash: hash},
+	}}
+	s := &AuthService{
+		logger:   zap.NewNop().Sugar(),
+		userRepo: repo,
+		throttle: ThrottleSettings{Window: time.Hour, LockoutDuration: time.Hour, ClientLockout: 3},
+	}
+
+	for _, clientID := range []string{"cart-service", "cart-service", "unknown"} {
+		_, _, err := s.ClientCredentials(context.Background(), clientID, "wrong-secret-guess", "10.0.0.9")
+		if !errors.Is(err, apierrors.ErrInvalidClient) {
+			t.Fatalf("ClientCredentials(%q) error = %v, want %v", clientID, err, apierrors.ErrInvalidClient)
+		}
+	}
+	if len(repo.attempts) != 3 || repo.attempts[0].Email != "cart-service" {
+		t.Fatalf("recorded attempts = %+v, want 3 keyed by client ID", repo.attempts)
+	}
+
+	_, _, err = s.ClientCredentials(context.Background(), "cart-service", "cart-service-secret", "10.0.0.9")
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("error = %v, want the address locked out", err)
+	}
+
+	// another address still gets a token with the right secret
+	s.signer = stubSigner{}
+	if _, _, err := s.ClientCredentials(context.Background(), "cart-service", "cart-service-secret", "10.0.0.10"); err != nil {
+		t.Errorf("ClientCredentials() from another address error = %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sabirkekw/ecommerce_go/pkg/apierrors"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/totp"
+)
+
+// maxChallengeAttempts is how many wrong codes a login challenge survives.
+const maxChallengeAttempts = 5
+
+type TwoFactorSettings struct {
+	// Issuer is the account name authenticator apps show next to the email
+	Issuer        string
+	ChallengeTTL  time.Duration
+	RecoveryCodes int
+}
+
+// EnrollTOTP starts 2FA enrollment with a fresh secret. 2FA is only enforced once ConfirmTOTP
+// proved the authenticator app produces valid codes.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID int64) (secret string, uri string, err error) {
+	const op = "sso.Auth.Service.EnrollTOTP"
+	s.logger.Debugw("Enrolling TOTP", "user_id", userID, "op", op)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		s.logger.Warnw("failed to generate TOTP secret", "error", err, "op", op)
+		return "", "", apierrors.ErrUnknown
+	}
+	if err := s.userRepo.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.URI(s.twoFactor.Issuer, user.Email, secret), nil
+}
+
+// ConfirmTOTP enables 2FA if code matches the enrolled secret and returns the recovery codes.
+// They are shown this one time, only their hashes are kept.
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID int64, code string) ([]string, error) {
+	const op = "sso.Auth.Service.ConfirmTOTP"
+	s.logger.Debugw("Confirming TOTP", "user_id", userID, "op", op)
+
+	enrollment, err := s.userRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.ConfirmedAt != nil {
+		return nil, apierrors.ErrTOTPAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(enrollment.Secret, code, time.Now(), enrollment.LastUsedStep)
+	if !ok {
+		return nil, apierrors.ErrInvalidSecondFactor
+	}
+
+	codes := make([]string, 0, s.twoFactor.RecoveryCodes)
+	hashes := make([]string, 0, s.twoFactor.RecoveryCodes)
+	for range s.twoFactor.RecoveryCodes {
+		code, err := recoveryCode()
+		if err != nil {
+			s.logger.Warnw("failed to generate recovery code", "error", err, "op", op)
+			return nil, apierrors.ErrUnknown
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.userRepo.ConfirmTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugw("TOTP enabled", "user_id", userID, "op", op)
+	return codes, nil
+}
+
+// VerifySecondFactor exchanges a login challenge and a TOTP or recovery code for a token pair.
+// Wrong codes count as failed logins, so the login throttle covers them as well.
+func (s *AuthService) VerifySecondFactor(ctx context.Context, challenge string, code string, clientIP string) (*models.TokenPair, error) {
+	const op = "sso.Auth.Service.VerifySecondFactor"
+	s.logger.Debugw("Verifying second factor", "client_ip", clientIP, "op", op)
+
+	user, err := s.checkLoginChallenge(ctx, challenge, code, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugw("Successfuly logged in user with second factor", "user_id", user.ID, "op", op)
+	return tokens, nil
+}
+
+// checkLoginChallenge is the second step of a login with 2FA, it uses up the challenge and returns
+// the user once the code is confirmed.
+func (s *AuthService) checkLoginChallenge(ctx context.Context, challenge string, code string, clientIP string) (*models.User, error) {
+	const op = "sso.Auth.Service.checkLoginChallenge"
+
+	pending, err := s.userRepo.GetLoginChallenge(ctx, hashToken(challenge))
+	if err != nil {
+		return nil, err
+	}
+	if pending.UsedAt != nil || time.Now().After(pending.ExpiresAt) || pending.Attempts >= maxChallengeAttempts {
+		return nil, apierrors.ErrInvalidLoginChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, pending.UserID)
+	if errors.Is(err, apierrors.ErrNoUser) {
+		return nil, apierrors.ErrInvalidLoginChallenge
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkThrottle(ctx, user.Email, clientIP); err != nil {
+		s.logger.Warnw("Second factor throttled", "user_id", user.ID, "client_ip", clientIP, "error", err, "op", op)
+		return nil, err
+	}
+
+	attempt := &models.LoginAttempt{Email: user.Email, UserID: user.ID, ClientIP: clientIP}
+	if err := s.checkSecondFactor(ctx, user.ID, code); err != nil {
+		if !errors.Is(err, apierrors.ErrInvalidSecondFactor) {
+			return nil, err
+		}
+		if err := s.userRepo.FailLoginChallenge(ctx, pending.ID); err != nil {
+			s.logger.Errorw("failed to count login challenge attempt", "error", err, "op", op)
+		}
+		s.recordAttempt(ctx, attempt)
+		return nil, apierrors.ErrInvalidSecondFactor
+	}
+
+	if err := s.userRepo.UseLoginChallenge(ctx, pending.ID); err != nil {
+		return nil, err
+	}
+	attempt.Success = true
+	s.recordAttempt(ctx, attempt)
+	return user, nil
+}
+
+// requiresSecondFactor reports whether the user has confirmed 2FA enrollment.
+func (s *AuthService) requiresSecondFactor(ctx context.Context, userID int64) (bool, error) {
+	enrollment, err := s.userRepo.GetTOTP(ctx, userID)
+	if errors.Is(err, apierrors.ErrTOTPNotEnrolled) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return enrollment.ConfirmedAt != nil, nil
+}
+
+func (s *AuthService) newLoginChallenge(ctx context.Context, userID int64, clientIP string) (string, error) {
+	challenge, err := randomToken()
+	if err != nil {
+		return "", apierrors.ErrUnknown
+	}
+
+	err = s.userRepo.CreateLoginChallenge(ctx, &models.LoginChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(challenge),
+		ClientIP:  clientIP,
+		ExpiresAt: time.Now().Add(s.twoFactor.ChallengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+// checkSecondFactor accepts a current TOTP code or an unused recovery code.
+func (s *AuthService) checkSecondFactor(ctx context.Context, userID int64, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		enrollment, err := s.userRepo.GetTOTP(ctx, userID)
+		if errors.Is(err, apierrors.ErrTOTPNotEnrolled) {
+			return apierrors.ErrInvalidSecondFactor
+		} else if err != nil {
+			return err
+		}
+
+		step, ok := totp.Validate(enrollment.Secret, code, time.Now(), enrollment.LastUsedStep)
+		if !ok {
+			return apierrors.ErrInvalidSecondFactor
+		}
+		return s.userRepo.UseTOTPStep(ctx, userID, step)
+	}
+
+	return s.userRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// recoveryCode returns 10 random base32 characters, grouped as xxxxx-xxxxx for readability.
+func recoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
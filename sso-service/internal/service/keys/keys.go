@@ -0,0 +1,158 @@
+package keys
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sabirkekw/ecommerce_go/sso-service/internal/models"
+	"go.uber.org/zap"
+)
+
+var ErrNoSigningKey = errors.New("no signing key loaded")
+
+type Repository interface {
+	CreateSigningKey(ctx context.Context, key *models.SigningKey) error
+	ListSigningKeys(ctx context.Context, retiredAfter time.Time) ([]*models.SigningKey, error)
+}
+
+// KeySet signs access tokens with the newest key and publishes the public halves of all keys
+// whose tokens may still be in circulation.
+type KeySet struct {
+	logger           *zap.SugaredLogger
+	repo             Repository
+	rotationInterval time.Duration
+	retiredKeyTTL    time.Duration
+
+	mu     sync.RWMutex
+	active *models.SigningKey
+	keys   []*models.SigningKey
+}
+
+// New takes retiredKeyTTL for how long retired keys stay published, it has to exceed the access token TTL.
+func New(logger *zap.SugaredLogger, repo Repository, rotationInterval time.Duration, retiredKeyTTL time.Duration) *KeySet {
+	return &KeySet{
+		logger:           logger,
+		repo:             repo,
+		rotationInterval: rotationInterval,
+		retiredKeyTTL:    retiredKeyTTL,
+	}
+}
+
+// Refresh reloads the keys, rotating in a new one first if the active key is missing or too old.
+// Every sso-service instance calls it periodically, so keys rotated by one instance reach the others.
+func (k *KeySet) Refresh(ctx context.Context) error {
+	const op = "sso.Keys.Refresh"
+
+	keys, err := k.repo.ListSigningKeys(ctx, time.Now().Add(-k.retiredKeyTTL))
+	if err != nil {
+		return err
+	}
+
+	active := activeKey(keys)
+	if active == nil || time.Since(active.CreatedAt) >= k.rotationInterval {
+		if err := k.rotate(ctx); err != nil {
+			return err
+		}
+		if keys, err = k.repo.ListSigningKeys(ctx, time.Now().Add(-k.retiredKeyTTL)); err != nil {
+			return err
+		}
+		active = activeKey(keys)
+	}
+	if active == nil {
+		return ErrNoSigningKey
+	}
+
+	k.mu.Lock()
+	k.active = active
+	k.keys = keys
+	k.mu.Unlock()
+
+	k.logger.Debugw("Signing keys loaded", "active_kid", active.Kid, "published", len(keys), "op", op)
+	return nil
+}
+
+// Run refreshes the keys every interval until ctx is done.
+func (k *KeySet) Run(ctx context.Context, interval time.Duration) {
+	const op = "sso.Keys.Run"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Refresh(ctx); err != nil {
+				k.logger.Errorw("failed to refresh signing keys", "error", err, "op", op)
+			}
+		}
+	}
+}
+
+// Sign issues an EdDSA token naming the signing key in its kid header.
+func (k *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	k.mu.RLock()
+	active := k.active
+	k.mu.RUnlock()
+
+	if active == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = active.Kid
+	return token.SignedString(active.PrivateKey)
+}
+
+// PublicKeys returns the public keys tokens may currently be verified with, by kid.
+func (k *KeySet) PublicKeys() map[string]ed25519.PublicKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	publicKeys := make(map[string]ed25519.PublicKey, len(k.keys))
+	for _, key := range k.keys {
+		publicKeys[key.Kid] = key.PrivateKey.Public().(ed25519.PublicKey)
+	}
+	return publicKeys
+}
+
+func (k *KeySet) rotate(ctx context.Context) error {
+	const op = "sso.Keys.rotate"
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return err
+	}
+
+	key := &models.SigningKey{
+		Kid:        hex.EncodeToString(kid),
+		PrivateKey: privateKey,
+		CreatedAt:  time.Now(),
+	}
+	if err := k.repo.CreateSigningKey(ctx, key); err != nil {
+		return err
+	}
+
+	k.logger.Infow("Rotated signing key", "kid", key.Kid, "op", op)
+	return nil
+}
+
+func activeKey(keys []*models.SigningKey) *models.SigningKey {
+	for _, key := range keys {
+		if key.RetiredAt == nil {
+			return key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890", in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateRFC6238(t *testing.T) {
+	key, err := encoding.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatalf("decoding secret: %v", err)
+	}
+
+	// the RFC lists 8 digit codes, these are their last 6 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := generate(key, Step(time.Unix(tt.unix, 0))); got != tt.want {
+				t.Errorf("generate() at %d = %s, want %s", tt.unix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	key, err := encoding.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatalf("decoding secret: %v", err)
+	}
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+	codeAt := func(step int64) string {
+		return generate(key, step)
+	}
+
+	tests := []struct {
+		name         string
+		secret       string
+		code         string
+		lastUsedStep int64
+		wantStep     int64
+		wantOK       bool
+	}{
+		{
+			name:     "current step",
+			secret:   rfcSecret,
+			code:     codeAt(current),
+			wantStep: current,
+			wantOK:   true,
+		},
+		{
+			name:     "previous step within skew",
+			secret:   rfcSecret,
+			code:     codeAt(current - 1),
+			wantStep: current - 1,
+			wantOK:   true,
+		},
+		{
+			name:     "next step within skew",
+			secret:   rfcSecret,
+			code:     codeAt(current + 1),
+			wantStep: current + 1,
+			wantOK:   true,
+		},
+		{
+			name:   "too old",
+			secret: rfcSecret,
+			code:   codeAt(current - 2),
+		},
+		{
+			name:   "too far ahead",
+			secret: rfcSecret,
+			code:   codeAt(current + 2),
+		},
+		{
+			name:         "replayed step",
+			secret:       rfcSecret,
+			code:         codeAt(current),
+			lastUsedStep: current,
+		},
+		{
+			name:         "older than last used step",
+			secret:       rfcSecret,
+			code:         codeAt(current - 1),
+			lastUsedStep: current,
+		},
+		{
+			name:         "newer than last used step",
+			secret:       rfcSecret,
+			code:         codeAt(current + 1),
+			lastUsedStep: current,
+			wantStep:     current + 1,
+			wantOK:       true,
+		},
+		{
+			name:     "lower case secret",
+			secret:   strings.ToLower(rfcSecret),
+			code:     codeAt(current),
+			wantStep: current,
+			wantOK:   true,
+		},
+		{
+			name:   "wrong code",
+			secret: rfcSecret,
+			code:   "000000",
+		},
+		{
+			name:   "too short",
+			secret: rfcSecret,
+			code:   codeAt(current)[:Digits-1],
+		},
+		{
+			name:   "too long",
+			secret: rfcSecret,
+			code:   codeAt(current) + "0",
+		},
+		{
+			name:   "invalid secret",
+			secret: "not base32!",
+			code:   codeAt(current),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now, tt.lastUsedStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("GenerateSecret() = %q, not base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("GenerateSecret() key is %d bytes, want 20", len(key))
+	}
+
+	now := time.Now()
+	if _, ok := Validate(secret, generate(key, Step(now)), now, 0); !ok {
+		t.Errorf("Validate() rejected the current code of a generated secret")
+	}
+}